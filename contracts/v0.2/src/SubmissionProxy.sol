@@ -389,7 +389,7 @@ contract SubmissionProxy is Ownable {
                 continue;
             }
 
-            bytes32 message_ = keccak256(abi.encodePacked(_answers[i], _timestamps[i], _feedHashes[i]));
+            bytes32 message_ = proofMessage(_feedHashes[i], _answers[i], _timestamps[i]);
             if (validateProof(_feedHashes[i], message_, proofs_)) {
                 feeds[_feedHashes[i]].submit(_answers[i]);
                 lastSubmissionTimes[_feedHashes[i]] = _timestamps[i];
@@ -460,7 +460,7 @@ contract SubmissionProxy is Ownable {
             revert InvalidFeedHash();
         }
 
-        bytes32 message_ = keccak256(abi.encodePacked(_answer, _timestamp, _feedHash));
+        bytes32 message_ = proofMessage(_feedHash, _answer, _timestamp);
         if (validateProof(_feedHash, message_, proofs_)) {
             feeds[_feedHash].submit(_answer);
             lastSubmissionTimes[_feedHash] = _timestamp;
@@ -538,7 +538,7 @@ contract SubmissionProxy is Ownable {
             revert InvalidFeedHash();
         }
 
-        bytes32 message_ = keccak256(abi.encodePacked(_answer, _timestamp, _feedHash));
+        bytes32 message_ = proofMessage(_feedHash, _answer, _timestamp);
         if (validateProof(_feedHash, message_, proofs_)) {
             feeds[_feedHash].submit(_answer);
             lastSubmissionTimes[_feedHash] = _timestamp;
@@ -660,6 +660,23 @@ contract SubmissionProxy is Ownable {
         return oracles;
     }
 
+    /**
+     * @notice Build the message signed by oracles
+     * @dev Feeds with decimals other than 8 have their decimals appended
+     * to the message, so a proof can not be replayed on another scale.
+     * @param _feedHash The hash of the feed
+     * @param _answer The submission answer
+     * @param _timestamp The timestamp of the answer
+     * @return The hash of the message
+     */
+    function proofMessage(bytes32 _feedHash, int256 _answer, uint256 _timestamp) private view returns (bytes32) {
+        uint8 decimals_ = feeds[_feedHash].decimals();
+        if (decimals_ == 8) {
+            return keccak256(abi.encodePacked(_answer, _timestamp, _feedHash));
+        }
+        return keccak256(abi.encodePacked(_answer, _timestamp, _feedHash, uint256(decimals_)));
+    }
+
     /**
      * @notice Validate the proof
      * @dev The order of the proofs must be in ascending order of the
@@ -5,4 +5,6 @@ interface IFeed {
     function submit(int256 answer) external;
 
     function name() external view returns (string memory);
+
+    function decimals() external view returns (uint8);
 }
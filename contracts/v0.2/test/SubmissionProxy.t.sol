@@ -9,7 +9,7 @@ import {IFeed} from "../src/interfaces/IFeed.sol";
 
 contract SubmissionProxyTest is Test {
     SubmissionProxy submissionProxy;
-    uint8 DECIMALS = 18;
+    uint8 DECIMALS = 8; // oracles sign the legacy message for feeds of 8 decimals
     string[] SAMPLE_NAMES = [
         "BTC-USDT",
         "ETH-USDT",
@@ -433,6 +433,95 @@ contract SubmissionProxyTest is Test {
         IFeed(feeds_[0]).latestRoundData();
     }
 
+    function test_SubmitCorrectProofNonDefaultDecimals() public {
+        (address alice_, uint256 aliceSk_) = makeAddrAndKey("alice");
+        (address bob_, uint256 bobSk_) = makeAddrAndKey("bob");
+        submissionProxy.addOracle(alice_);
+        submissionProxy.addOracle(bob_);
+
+        uint8 decimals_ = 6;
+        (
+            bytes32[] memory feedHashes_,
+            int256[] memory submissions_,
+            bytes[] memory proofs_,
+            uint256[] memory timestamps_,
+            address[] memory feeds_
+        ) = prepareFeedSubmissionWithDecimals(decimals_, 10);
+
+        // decimals other than 8 are appended to the signed message
+        bytes32 hash_ =
+            keccak256(abi.encodePacked(submissions_[0], timestamps_[0], feedHashes_[0], uint256(decimals_)));
+        proofs_[0] = abi.encodePacked(createProof(aliceSk_, hash_), createProof(bobSk_, hash_));
+
+        submissionProxy.setProofThreshold(feedHashes_[0], 100);
+        submissionProxy.submit(feedHashes_, submissions_, timestamps_, proofs_);
+
+        // don't raise `NoDataPresent`
+        (, int256 answer_,) = IFeed(feeds_[0]).latestRoundData();
+        assertEq(answer_, 10);
+    }
+
+    function test_SubmitSubUnitPriceWithMaxDecimals() public {
+        (address alice_, uint256 aliceSk_) = makeAddrAndKey("alice");
+        (address bob_, uint256 bobSk_) = makeAddrAndKey("bob");
+        submissionProxy.addOracle(alice_);
+        submissionProxy.addOracle(bob_);
+
+        // 1e-10 is 10^8 units at 18 decimals, it would be rounded to zero at 8
+        uint8 decimals_ = 18;
+        (
+            bytes32[] memory feedHashes_,
+            int256[] memory submissions_,
+            bytes[] memory proofs_,
+            uint256[] memory timestamps_,
+            address[] memory feeds_
+        ) = prepareFeedSubmissionWithDecimals(decimals_, 100_000_000);
+
+        bytes32 hash_ =
+            keccak256(abi.encodePacked(submissions_[0], timestamps_[0], feedHashes_[0], uint256(decimals_)));
+        proofs_[0] = abi.encodePacked(createProof(aliceSk_, hash_), createProof(bobSk_, hash_));
+
+        submissionProxy.setProofThreshold(feedHashes_[0], 100);
+        submissionProxy.submit(feedHashes_, submissions_, timestamps_, proofs_);
+
+        (, int256 answer_,) = IFeed(feeds_[0]).latestRoundData();
+        assertEq(answer_, 100_000_000);
+        assertEq(IFeed(feeds_[0]).decimals(), 18);
+    }
+
+    function test_SubmitProofReplayedOnAnotherScale() public {
+        (address alice_, uint256 aliceSk_) = makeAddrAndKey("alice");
+        (address bob_, uint256 bobSk_) = makeAddrAndKey("bob");
+        submissionProxy.addOracle(alice_);
+        submissionProxy.addOracle(bob_);
+
+        (
+            bytes32[] memory feedHashes_,
+            int256[] memory submissions_,
+            bytes[] memory proofs_,
+            uint256[] memory timestamps_,
+            address[] memory feeds_
+        ) = prepareFeedSubmissionWithDecimals(6, 10);
+        submissionProxy.setProofThreshold(feedHashes_[0], 100);
+
+        // proof of the same answer signed for a feed of 8 decimals
+        bytes32 legacyHash_ = keccak256(abi.encodePacked(submissions_[0], timestamps_[0], feedHashes_[0]));
+        proofs_[0] = abi.encodePacked(createProof(aliceSk_, legacyHash_), createProof(bobSk_, legacyHash_));
+        submissionProxy.submit(feedHashes_, submissions_, timestamps_, proofs_);
+
+        vm.expectRevert(Feed.NoDataPresent.selector);
+        IFeed(feeds_[0]).latestRoundData();
+
+        // proof of the same answer signed for a feed of 18 decimals
+        bytes32 otherHash_ =
+            keccak256(abi.encodePacked(submissions_[0], timestamps_[0], feedHashes_[0], uint256(18)));
+        proofs_[0] = abi.encodePacked(createProof(aliceSk_, otherHash_), createProof(bobSk_, otherHash_));
+        submissionProxy.submit(feedHashes_, submissions_, timestamps_, proofs_);
+
+        vm.expectRevert(Feed.NoDataPresent.selector);
+        IFeed(feeds_[0]).latestRoundData();
+    }
+
     function test_SubmitStrict() public {
         (address alice_, uint256 aliceSk_) = makeAddrAndKey("alice");
         (address bob_, uint256 bobSk_) = makeAddrAndKey("bob");
@@ -558,6 +647,27 @@ contract SubmissionProxyTest is Test {
         return (feedHashes_, submissions_, proofs_, timestamps_, feeds_);
     }
 
+    function prepareFeedSubmissionWithDecimals(uint8 _decimals, int256 _submissionValue)
+        private
+        returns (bytes32[] memory, int256[] memory, bytes[] memory, uint256[] memory, address[] memory)
+    {
+        (
+            bytes32[] memory feedHashes_,
+            int256[] memory submissions_,
+            bytes[] memory proofs_,
+            uint256[] memory timestamps_
+        ) = createSubmitParameters(1);
+        address[] memory feeds_ = new address[](1);
+        Feed feed_ = new Feed(_decimals, SAMPLE_NAMES[0], address(submissionProxy));
+        feeds_[0] = address(feed_);
+        feedHashes_[0] = keccak256(abi.encodePacked(SAMPLE_NAMES[0]));
+        submissions_[0] = _submissionValue;
+        timestamps_[0] = block.timestamp * 1000;
+        submissionProxy.updateFeed(feedHashes_[0], address(feeds_[0]));
+
+        return (feedHashes_, submissions_, proofs_, timestamps_, feeds_);
+    }
+
     function prepareFeedsSubmissionsWrongName(uint256 _numOracles, int256 _submissionValue, uint256 _oracleSk)
         private
         returns (bytes32[] memory, int256[] memory, bytes[] memory, uint256[] memory, address[] memory)
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"bisonai.com/miko/node/pkg/backtest"
+	"bisonai.com/miko/node/pkg/db"
+	"bisonai.com/miko/node/pkg/fetcher"
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	envFile := flag.String("env", "", "env file")
+	configName := flag.String("config", "", "config name, e.g. BTC-USDT")
+	fromRaw := flag.String("from", "", "range start in RFC3339, defaults to 24 hours before to")
+	toRaw := flag.String("to", "", "range end in RFC3339, defaults to now")
+	dataFile := flag.String("data", "", "feed_data csv export (feed_id,value,timestamp,volume,spread) instead of reading postgres")
+	referenceFile := flag.String("reference", "", "reference csv (timestamp,value) instead of the stored global_aggregates")
+	algorithmName := flag.String("algorithm", backtest.DefaultAlgorithm, "aggregation algorithm")
+	maxOutlierRemovalRatio := flag.Float64("max-outlier-ratio", fetcher.MaxOutlierRemovalRatio, "share of feeds which may be removed as outliers")
+	medianRatio := flag.Float64("median-ratio", fetcher.DefaultMedianRatio, "weight of the median of feeds without volume")
+	maxSpreadRatio := flag.Float64("max-spread-ratio", fetcher.DefaultMaxSpreadRatio, "drop order book feeds whose spread exceeds this multiple of the median spread, 0 disables")
+	interval := flag.Duration("interval", fetcher.DefaultLocalAggregateInterval, "local aggregation interval")
+	staleness := flag.Duration("staleness", backtest.DefaultStaleness, "feeds without updates for this long are left out")
+	maxReferenceAge := flag.Duration("max-reference-age", 0, "skip points whose latest reference is older, 0 disables")
+	flag.Parse()
+
+	if *envFile != "" {
+		err := godotenv.Load(*envFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load env file")
+		}
+	}
+	if *configName == "" {
+		log.Fatal().Msg("config is required")
+	}
+
+	to := time.Now()
+	if *toRaw != "" {
+		parsed, err := time.Parse(time.RFC3339, *toRaw)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid to")
+		}
+		to = parsed
+	}
+	from := to.Add(-24 * time.Hour)
+	if *fromRaw != "" {
+		parsed, err := time.Parse(time.RFC3339, *fromRaw)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid from")
+		}
+		from = parsed
+	}
+
+	algorithm, err := backtest.NewAlgorithm(*algorithmName, fetcher.AggregationParams{
+		MaxOutlierRemovalRatio: *maxOutlierRemovalRatio,
+		MedianRatio:            *medianRatio,
+		MaxSpreadRatio:         *maxSpreadRatio,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Str("algorithm", *algorithmName).Msg("failed to create algorithm")
+	}
+
+	ctx := context.Background()
+	defer db.ClosePool()
+
+	configId := int32(0)
+	if *dataFile == "" || *referenceFile == "" {
+		configId, err = backtest.LoadConfigId(ctx, *configName)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load config")
+		}
+	}
+
+	var feedData []*fetcher.FeedData
+	if *dataFile != "" {
+		feedData, err = backtest.LoadFeedDataCsv(*dataFile, from, to)
+	} else {
+		feedData, err = backtest.LoadFeedData(ctx, configId, from, to)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load feed data")
+	}
+
+	var reference []backtest.Point
+	if *referenceFile != "" {
+		reference, err = backtest.LoadReferenceCsv(*referenceFile, from, to)
+	} else {
+		reference, err = backtest.LoadGlobalAggregates(ctx, configId, from, to)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load reference")
+	}
+
+	points, replayStats, err := backtest.Replay(feedData, backtest.ReplayConfig{
+		Name:      *configName,
+		Algorithm: algorithm,
+		Interval:  *interval,
+		Staleness: *staleness,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to replay feed data")
+	}
+
+	report := backtest.Compare(points, reference, *maxReferenceAge)
+	report.ReplayStats = replayStats
+
+	result, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to marshal report")
+	}
+	fmt.Fprintln(os.Stdout, string(result))
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS websocket_providers;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS websocket_providers (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    config JSONB NOT NULL
+)
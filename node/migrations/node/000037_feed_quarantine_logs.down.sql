@@ -0,0 +1 @@
+DROP TABLE IF EXISTS feed_quarantine_logs;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS feed_quarantine_logs (
+    id BIGSERIAL PRIMARY KEY,
+    feed_id INT4 NOT NULL,
+    decision TEXT NOT NULL,
+    value INT8 NOT NULL,
+    reference INT8,
+    reason TEXT NOT NULL,
+    timestamp TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT feed_quarantine_logs_feed_id_fkey FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS feed_quarantine_logs_feed_id_idx ON feed_quarantine_logs(feed_id);
@@ -0,0 +1,2 @@
+ALTER TABLE configs
+ADD COLUMN IF NOT EXISTS decimals INT4 DEFAULT 8 NOT NULL;
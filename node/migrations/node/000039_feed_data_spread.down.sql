@@ -0,0 +1 @@
+ALTER TABLE feed_data DROP COLUMN IF EXISTS spread;
\ No newline at end of file
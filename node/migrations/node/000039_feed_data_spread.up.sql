@@ -0,0 +1 @@
+ALTER TABLE feed_data ADD COLUMN IF NOT EXISTS spread DOUBLE PRECISION;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE feed_data ALTER COLUMN value TYPE INT8 USING value::INT8;
+ALTER TABLE feed_quarantine_logs ALTER COLUMN value TYPE INT8 USING value::INT8, ALTER COLUMN reference TYPE INT8 USING reference::INT8;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE feed_data ALTER COLUMN value TYPE NUMERIC;
+ALTER TABLE feed_quarantine_logs ALTER COLUMN value TYPE NUMERIC, ALTER COLUMN reference TYPE NUMERIC;
\ No newline at end of file
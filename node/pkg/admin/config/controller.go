@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"bisonai.com/miko/node/pkg/admin/feed"
+	"bisonai.com/miko/node/pkg/common/types"
 	"bisonai.com/miko/node/pkg/db"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/utils/request"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
@@ -30,6 +32,7 @@ type ConfigInsertModel struct {
 	FetchInterval     *int              `db:"fetch_interval" json:"fetchInterval"`
 	AggregateInterval *int              `db:"aggregate_interval" json:"aggregateInterval"`
 	SubmitInterval    *int              `db:"submit_interval" json:"submitInterval"`
+	Decimals          *int              `db:"decimals" json:"decimals"`
 	Feeds             []FeedInsertModel `json:"feeds"`
 }
 
@@ -39,6 +42,7 @@ type ConfigModel struct {
 	FetchInterval     *int   `db:"fetch_interval" json:"fetchInterval"`
 	AggregateInterval *int   `db:"aggregate_interval" json:"aggregateInterval"`
 	SubmitInterval    *int   `db:"submit_interval" json:"submitInterval"`
+	Decimals          *int   `db:"decimals" json:"decimals"`
 }
 
 type ConfigNameIdModel struct {
@@ -149,6 +153,11 @@ func sync(ctx context.Context) error {
 			if !ok {
 				continue
 			}
+			err = validateDefinition(feed.Definition)
+			if err != nil {
+				log.Error().Err(err).Str("Player", "Admin").Str("Feed", feed.Name).Msg("skipping feed with invalid definition")
+				continue
+			}
 			upsertRows = append(upsertRows, []any{feed.Name, feed.Definition, configId})
 		}
 	}
@@ -163,13 +172,25 @@ func Insert(c *fiber.Ctx) error {
 		return err
 	}
 
+	for _, feed := range config.Feeds {
+		err := validateDefinition(feed.Definition)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("invalid definition of feed " + feed.Name + ": " + err.Error())
+		}
+	}
+
 	setDefaultIntervals(config)
+	err := setDefaultDecimals(config)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
 
 	result, err := db.QueryRow[ConfigModel](c.Context(), InsertConfigQuery, map[string]any{
 		"name":               config.Name,
 		"fetch_interval":     config.FetchInterval,
 		"aggregate_interval": config.AggregateInterval,
-		"submit_interval":    config.SubmitInterval})
+		"submit_interval":    config.SubmitInterval,
+		"decimals":           config.Decimals})
 	if err != nil {
 		log.Error().Err(err).Str("Player", "Admin").Msg("failed to insert config")
 		return err
@@ -187,6 +208,18 @@ func Insert(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// validateDefinition rejects auth blocks holding anything but secret names, so credentials never reach the feeds table
+func validateDefinition(definition json.RawMessage) error {
+	var parsed struct {
+		Auth *request.Auth `json:"auth"`
+	}
+	err := json.Unmarshal(definition, &parsed)
+	if err != nil {
+		return err
+	}
+	return parsed.Auth.Validate()
+}
+
 func Get(c *fiber.Ctx) error {
 	configs, err := db.QueryRows[ConfigModel](c.Context(), SelectConfigQuery, nil)
 	if err != nil {
@@ -228,10 +261,15 @@ func getConfigUrl() string {
 func bulkUpsertConfigs(ctx context.Context, configs []ConfigInsertModel) error {
 	upsertRows := make([][]any, 0, len(configs))
 	for _, config := range configs {
-		upsertRows = append(upsertRows, []any{config.Name, config.FetchInterval, config.AggregateInterval, config.SubmitInterval})
+		err := setDefaultDecimals(&config)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "Admin").Str("config", config.Name).Int("decimals", *config.Decimals).Msg("invalid decimals")
+			return err
+		}
+		upsertRows = append(upsertRows, []any{config.Name, config.FetchInterval, config.AggregateInterval, config.SubmitInterval, config.Decimals})
 	}
 
-	return db.BulkUpsert(ctx, "configs", []string{"name", "fetch_interval", "aggregate_interval", "submit_interval"}, upsertRows, []string{"name"}, []string{"fetch_interval", "aggregate_interval", "submit_interval"})
+	return db.BulkUpsert(ctx, "configs", []string{"name", "fetch_interval", "aggregate_interval", "submit_interval", "decimals"}, upsertRows, []string{"name"}, []string{"fetch_interval", "aggregate_interval", "submit_interval", "decimals"})
 }
 
 func setDefaultIntervals(config *ConfigInsertModel) {
@@ -248,3 +286,15 @@ func setDefaultIntervals(config *ConfigInsertModel) {
 		*config.SubmitInterval = 15000
 	}
 }
+
+// setDefaultDecimals rejects decimals above types.MaxDecimals
+func setDefaultDecimals(config *ConfigInsertModel) error {
+	if config.Decimals == nil || *config.Decimals <= 0 {
+		config.Decimals = new(int)
+		*config.Decimals = types.DefaultDecimals
+	}
+	if *config.Decimals > types.MaxDecimals {
+		return errorSentinel.ErrAdminInvalidDecimals
+	}
+	return nil
+}
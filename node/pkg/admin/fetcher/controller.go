@@ -52,3 +52,33 @@ func refresh(c *fiber.Ctx) error {
 	}
 	return c.SendString("fetcher refreshed: " + strconv.FormatBool(resp.Success))
 }
+
+func health(c *fiber.Ctx) error {
+	msg, err := utils.SendMessage(c, bus.FETCHER, bus.GET_WEBSOCKET_HEALTH, nil)
+	if err != nil {
+		log.Error().Err(err).Str("Player", "Admin").Msg("failed to send message to fetcher")
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to get websocket health: " + err.Error())
+	}
+	resp := <-msg.Response
+	if !resp.Success {
+		log.Error().Str("Player", "Admin").Msg("failed to get websocket health")
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to get websocket health: " + resp.Args["error"].(string))
+	}
+
+	return c.JSON(resp.Args)
+}
+
+func dryRun(c *fiber.Ctx) error {
+	msg, err := utils.SendMessage(c, bus.FETCHER, bus.DRY_RUN_FEED, map[string]any{"definition": string(c.Body())})
+	if err != nil {
+		log.Error().Err(err).Str("Player", "Admin").Msg("failed to send message to fetcher")
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to dry run feed: " + err.Error())
+	}
+	resp := <-msg.Response
+	if !resp.Success {
+		log.Error().Str("Player", "Admin").Msg("failed to dry run feed")
+		return c.Status(fiber.StatusInternalServerError).SendString("failed to dry run feed: " + resp.Args["error"].(string))
+	}
+
+	return c.JSON(resp.Args)
+}
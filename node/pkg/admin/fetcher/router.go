@@ -10,4 +10,6 @@ func Routes(router fiber.Router) {
 	fetcher.Post("/start", start)
 	fetcher.Post("/stop", stop)
 	fetcher.Post("/refresh", refresh)
+	fetcher.Get("/health", health)
+	fetcher.Post("/dry-run", dryRun)
 }
@@ -8,6 +8,8 @@ import (
 
 	"bisonai.com/miko/node/pkg/admin/config"
 	"bisonai.com/miko/node/pkg/admin/feed"
+	"bisonai.com/miko/node/pkg/common/types"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,6 +80,35 @@ func TestConfigInsert(t *testing.T) {
 
 }
 
+func TestConfigInsertHighDecimals(t *testing.T) {
+	ctx := context.Background()
+	cleanup, testItems, err := setup(ctx)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+	defer func() {
+		err = cleanup()
+		if err != nil {
+			t.Logf("Cleanup failed: %v", err)
+		}
+	}()
+
+	decimals := types.MaxDecimals + 1
+	result, err := RawPostRequest(testItems.app, "/api/v1/config", config.ConfigInsertModel{Name: "test-decimals", Decimals: &decimals})
+	if err != nil {
+		t.Fatalf("error inserting config: %v", err)
+	}
+	assert.Contains(t, string(result), errorSentinel.ErrAdminInvalidDecimals.Error())
+
+	configs, err := GetRequest[[]config.ConfigModel](testItems.app, "/api/v1/config", nil)
+	if err != nil {
+		t.Fatalf("error getting configs: %v", err)
+	}
+	for _, c := range configs {
+		assert.NotEqual(t, "test-decimals", c.Name)
+	}
+}
+
 func TestConfigRead(t *testing.T) {
 	ctx := context.Background()
 	cleanup, testItems, err := setup(ctx)
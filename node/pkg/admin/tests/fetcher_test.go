@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"bisonai.com/miko/node/pkg/bus"
+	"bisonai.com/miko/node/pkg/websocketfetcher"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +66,46 @@ func TestFetcherRefresh(t *testing.T) {
 
 	assert.Equal(t, string(result), "fetcher refreshed: true")
 }
+
+func TestFetcherHealth(t *testing.T) {
+	ctx := context.Background()
+	cleanup, testItems, err := setup(ctx)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+	defer cleanup()
+
+	providers := []websocketfetcher.ProviderHealth{{Name: "binance", State: websocketfetcher.ConnectionStateConnected, Connections: 1}}
+	channel := testItems.mb.Subscribe(bus.FETCHER)
+	waitForMessageWithResponse(t, channel, bus.ADMIN, bus.FETCHER, bus.GET_WEBSOCKET_HEALTH, map[string]any{"providers": providers})
+
+	result, err := GetRequest[struct {
+		Providers []websocketfetcher.ProviderHealth `json:"providers"`
+	}](testItems.app, "/api/v1/fetcher/health", nil)
+	if err != nil {
+		t.Fatalf("error getting websocket health: %v", err)
+	}
+
+	assert.Equal(t, providers, result.Providers)
+}
+
+func TestFetcherDryRun(t *testing.T) {
+	ctx := context.Background()
+	cleanup, testItems, err := setup(ctx)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+	defer cleanup()
+
+	channel := testItems.mb.Subscribe(bus.FETCHER)
+	waitForMessageWithResponse(t, channel, bus.ADMIN, bus.FETCHER, bus.DRY_RUN_FEED, map[string]any{"value": float64(46760000)})
+
+	result, err := PostRequest[struct {
+		Value float64 `json:"value"`
+	}](testItems.app, "/api/v1/fetcher/dry-run", map[string]any{"url": "https://example.com", "method": "POST", "body": map[string]any{"query": "{ price }"}})
+	if err != nil {
+		t.Fatalf("error dry running feed: %v", err)
+	}
+
+	assert.Equal(t, float64(46760000), result.Value)
+}
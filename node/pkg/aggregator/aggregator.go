@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/common/types"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/raft"
 	"bisonai.com/miko/node/pkg/utils/calculator"
+	"bisonai.com/miko/node/pkg/utils/fxcalendar"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/rs/zerolog/log"
@@ -139,6 +141,10 @@ func (n *Aggregator) HandleTriggerMessage(ctx context.Context, msg raft.Message)
 		// it is to proceed further steps even if current node fails to get latest local aggregate
 		// if not enough messages collected from HandleSyncReplyMessage, it will hang in certain round
 		value = -1
+	} else if types.DecimalsOrDefault(localAggregate.Decimals) != types.DecimalsOrDefault(n.Decimals) {
+		// the fetcher still runs with decimals from before a config change, its value is on another scale
+		log.Warn().Str("Player", "Aggregator").Int32("localDecimals", localAggregate.Decimals).Int32("decimals", n.Decimals).Msg("local aggregate decimals do not match config")
+		value = -1
 	} else {
 		value = localAggregate.Value
 	}
@@ -265,7 +271,7 @@ func (n *Aggregator) HandlePriceFixMessage(ctx context.Context, msg raft.Message
 
 	n.roundPriceFixes.locked[priceFixMessage.RoundID] = true
 
-	proof, err := n.Signer.MakeGlobalAggregateProof(priceFixMessage.PriceData, priceFixMessage.Timestamp, n.Name)
+	proof, err := n.Signer.MakeGlobalAggregateProofWithDecimals(priceFixMessage.PriceData, priceFixMessage.Timestamp, n.Name, types.DecimalsOrDefault(n.Decimals))
 	if err != nil {
 		log.Error().Str("Player", "Aggregator").Err(err).Msg("failed to make global aggregate proof")
 		return err
@@ -356,10 +362,12 @@ func (n *Aggregator) processCollectedProofs(ctx context.Context, proofMessage Pr
 	log.Debug().Str("Player", "Aggregator").Str("Name", n.Name).Int("peerCount", n.Raft.SubscribersCount()).Int32("roundId", proofMessage.RoundID).Any("collected proofs", n.roundProofs.proofs[proofMessage.RoundID]).Msg("collected proofs")
 
 	globalAggregate := GlobalAggregate{
-		ConfigID:  n.ID,
-		Value:     proofMessage.Value,
-		Round:     proofMessage.RoundID,
-		Timestamp: proofMessage.Timestamp}
+		ConfigID:     n.ID,
+		Value:        proofMessage.Value,
+		Round:        proofMessage.RoundID,
+		Timestamp:    proofMessage.Timestamp,
+		MarketClosed: fxcalendar.MarketClosed(n.Name, proofMessage.Timestamp),
+		Decimals:     types.DecimalsOrDefault(n.Decimals)}
 
 	concatProof := bytes.Join(n.roundProofs.proofs[proofMessage.RoundID], nil)
 	proof := Proof{ConfigID: n.ID, Round: proofMessage.RoundID, Proof: concatProof}
@@ -19,7 +19,7 @@ import (
 )
 
 const (
-	InsertConfigQuery         = `INSERT INTO configs (name, fetch_interval, aggregate_interval, submit_interval) VALUES (@name, @fetch_interval, @aggregate_interval, @submit_interval) RETURNING name, id, aggregate_interval;`
+	InsertConfigQuery         = `INSERT INTO configs (name, fetch_interval, aggregate_interval, submit_interval) VALUES (@name, @fetch_interval, @aggregate_interval, @submit_interval) RETURNING name, id, aggregate_interval, decimals;`
 	InsertLocalAggregateQuery = `INSERT INTO local_aggregates (config_id, value, timestamp) VALUES (@config_id, @value, @time) RETURNING *;`
 	DeleteGlobalAggregates    = `DELETE FROM global_aggregates;`
 	DeleteLocalAggregates     = `DELETE FROM local_aggregates;`
@@ -21,7 +21,7 @@ const (
 	PriceFix  raft.MessageType = "priceFix"
 	ProofMsg  raft.MessageType = "proof"
 
-	SelectConfigQuery                = `SELECT id, name, aggregate_interval FROM configs`
+	SelectConfigQuery                = `SELECT id, name, aggregate_interval, decimals FROM configs`
 	SelectLatestLocalAggregateQuery  = `SELECT * FROM local_aggregates WHERE config_id = @config_id ORDER BY timestamp DESC LIMIT 1`
 	InsertGlobalAggregateQuery       = `INSERT INTO global_aggregates (config_id, value, round, timestamp) VALUES (@config_id, @value, @round, @timestamp) RETURNING *`
 	SelectLatestGlobalAggregateQuery = `SELECT * FROM global_aggregates WHERE config_id = @config_id ORDER BY round DESC LIMIT 1`
@@ -52,6 +52,7 @@ type Config struct {
 	ID                int32  `db:"id"`
 	Name              string `db:"name"`
 	AggregateInterval int32  `db:"aggregate_interval"`
+	Decimals          int32  `db:"decimals"`
 }
 
 type RoundTriggers struct {
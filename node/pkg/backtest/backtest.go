@@ -0,0 +1,144 @@
+package backtest
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/fetcher"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"github.com/montanaflynn/stats"
+	"github.com/rs/zerolog/log"
+)
+
+// Algorithms are the aggregation algorithms which can be selected by name
+var Algorithms = map[string]func(fetcher.AggregationParams) fetcher.AggregationAlgorithm{
+	DefaultAlgorithm: fetcher.NewAggregationAlgorithm,
+	"median":         newMedianAlgorithm,
+}
+
+func NewAlgorithm(name string, params fetcher.AggregationParams) (fetcher.AggregationAlgorithm, error) {
+	newAlgorithm, exists := Algorithms[name]
+	if !exists {
+		return nil, errorSentinel.ErrBacktestUnknownAlgorithm
+	}
+	return newAlgorithm(params), nil
+}
+
+// newMedianAlgorithm takes the plain median of every source, ignoring volume and outliers
+func newMedianAlgorithm(fetcher.AggregationParams) fetcher.AggregationAlgorithm {
+	return func(name string, feeds []*FeedData) (fetcher.AggregationResult, error) {
+		values := make([]*big.Rat, len(feeds))
+		for i, feed := range feeds {
+			values[i] = new(big.Rat).SetInt(feed.Value)
+		}
+		return fetcher.AggregationResult{Value: decimal.Round(decimal.Median(values))}, nil
+	}
+}
+
+// Replay feeds the data, sorted by timestamp, through the algorithm the way the local aggregator does:
+// every interval the latest value of each feed which is not stale is aggregated.
+func Replay(feedData []*FeedData, config ReplayConfig) ([]Point, ReplayStats, error) {
+	replayStats := ReplayStats{}
+	if len(feedData) == 0 {
+		return nil, replayStats, errorSentinel.ErrBacktestNoFeedData
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = fetcher.DefaultLocalAggregateInterval
+	}
+	staleness := config.Staleness
+	if staleness <= 0 {
+		staleness = DefaultStaleness
+	}
+	algorithm := config.Algorithm
+	if algorithm == nil {
+		algorithm = fetcher.DefaultAggregationAlgorithm
+	}
+
+	start := *feedData[0].Timestamp
+	end := *feedData[len(feedData)-1].Timestamp
+
+	points := []Point{}
+	latest := map[int32]*FeedData{}
+	next := 0
+	for tick := start.Truncate(interval).Add(interval); !tick.After(end.Add(interval)); tick = tick.Add(interval) {
+		for next < len(feedData) && !feedData[next].Timestamp.After(tick) {
+			latest[feedData[next].FeedID] = feedData[next]
+			next++
+		}
+
+		feeds := make([]*FeedData, 0, len(latest))
+		for _, data := range latest {
+			if tick.Sub(*data.Timestamp) <= staleness {
+				feeds = append(feeds, data)
+			}
+		}
+		if len(feeds) == 0 {
+			continue
+		}
+
+		replayStats.Ticks++
+		result, err := algorithm(config.Name, feeds)
+		if err != nil {
+			replayStats.Errors++
+			continue
+		}
+		if result.OutliersRemoved > 0 {
+			replayStats.OutliersRemoved += result.OutliersRemoved
+			replayStats.TicksWithOutliers++
+		}
+		// zero aggregates are not streamed by the local aggregator either
+		if result.Value == nil || result.Value.Sign() == 0 {
+			continue
+		}
+		value, _ := new(big.Float).SetInt(result.Value).Float64()
+		points = append(points, Point{Timestamp: tick, Value: value})
+	}
+
+	log.Debug().Str("Player", "Backtest").Int("ticks", replayStats.Ticks).Int("points", len(points)).Msg("replay done")
+	return points, replayStats, nil
+}
+
+// Compare measures points, sorted by timestamp, against the latest reference value at or before each point.
+// References older than maxReferenceAge are not compared, 0 compares against any older reference.
+func Compare(points []Point, reference []Point, maxReferenceAge time.Duration) Report {
+	report := Report{}
+	deviations := []float64{}
+	differences := []float64{}
+
+	next := 0
+	var current *Point
+	for _, point := range points {
+		for next < len(reference) && !reference[next].Timestamp.After(point.Timestamp) {
+			current = &reference[next]
+			next++
+		}
+		if current == nil || current.Value == 0 {
+			continue
+		}
+		if maxReferenceAge > 0 && point.Timestamp.Sub(current.Timestamp) > maxReferenceAge {
+			continue
+		}
+
+		difference := (point.Value - current.Value) / current.Value
+		deviation := math.Abs(difference)
+		if deviation > report.MaxDeviation || report.MaxDeviationAt == nil {
+			timestamp := point.Timestamp
+			report.MaxDeviation = deviation
+			report.MaxDeviationAt = &timestamp
+		}
+		deviations = append(deviations, deviation)
+		differences = append(differences, difference)
+	}
+
+	report.Compared = len(deviations)
+	if report.Compared == 0 {
+		return report
+	}
+	report.MeanDeviation, _ = stats.Mean(deviations)
+	report.TrackingError, _ = stats.StandardDeviation(differences)
+	return report
+}
@@ -0,0 +1,71 @@
+//nolint:all
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/fetcher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacktest(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "feed_data.csv")
+	referenceFile := filepath.Join(dir, "reference.csv")
+
+	// eight sources, feed 8 reports a wrong price from the second tick on
+	err := os.WriteFile(dataFile, []byte(`feed_id,value,timestamp,volume
+1,100,2024-06-01 00:00:00.1+00,1
+2,101,2024-06-01 00:00:00.1+00,1
+3,100,2024-06-01 00:00:00.1+00,1
+4,100,2024-06-01 00:00:00.1+00,1
+5,100,2024-06-01 00:00:00.1+00,1
+6,100,2024-06-01 00:00:00.1+00,1
+7,101,2024-06-01 00:00:00.1+00,1
+8,100,2024-06-01 00:00:00.1+00,1
+8,200,2024-06-01 00:00:01.1+00,1
+1,100,1717200005000,1
+`), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(referenceFile, []byte("timestamp,value\n2024-06-01T00:00:00Z,100\n2024-06-01T00:00:03Z,101\n"), 0644)
+	assert.NoError(t, err)
+
+	feedData, err := LoadFeedDataCsv(dataFile, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, feedData, 10)
+	reference, err := LoadReferenceCsv(referenceFile, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, reference, 2)
+
+	t.Run("default algorithm removes the outlier", func(t *testing.T) {
+		points, replayStats, err := Replay(feedData, ReplayConfig{Name: "TEST-USDT", Interval: time.Second, Staleness: time.Minute})
+		assert.NoError(t, err)
+		assert.Len(t, points, 6)
+		assert.Equal(t, 5, replayStats.TicksWithOutliers)
+		assert.Equal(t, 5, replayStats.OutliersRemoved)
+
+		report := Compare(points, reference, 0)
+		assert.Equal(t, 6, report.Compared)
+		// 702/7 is rounded to 100, against the second reference of 101
+		assert.InDelta(t, 1.0/101, report.MaxDeviation, 1e-9)
+		assert.Greater(t, report.TrackingError, 0.0)
+	})
+
+	t.Run("median algorithm keeps the outlier", func(t *testing.T) {
+		algorithm, err := NewAlgorithm("median", fetcher.DefaultAggregationParams())
+		assert.NoError(t, err)
+
+		points, replayStats, err := Replay(feedData, ReplayConfig{Name: "TEST-USDT", Algorithm: algorithm, Interval: time.Second, Staleness: time.Minute})
+		assert.NoError(t, err)
+		assert.Zero(t, replayStats.OutliersRemoved)
+
+		report := Compare(points, reference, time.Second)
+		assert.Equal(t, 3, report.Compared)
+	})
+
+	_, err = NewAlgorithm("unknown", fetcher.DefaultAggregationParams())
+	assert.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bisonai.com/miko/node/pkg/common/types"
+	"bisonai.com/miko/node/pkg/db"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"github.com/rs/zerolog/log"
+)
+
+// layouts accepted for csv timestamps besides unix seconds and milliseconds, the latter two are written by psql \copy
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func LoadConfigId(ctx context.Context, name string) (int32, error) {
+	config, err := db.QueryRow[ConfigId](ctx, SelectConfigIdByNameQuery, map[string]any{"name": name})
+	if err != nil {
+		log.Error().Str("Player", "Backtest").Err(err).Str("config", name).Msg("failed to load config")
+		return 0, err
+	}
+	if config.ID == 0 {
+		return 0, errorSentinel.ErrBacktestConfigNotFound
+	}
+	return config.ID, nil
+}
+
+// LoadFeedData reads the stored feed data of every feed of the config within the range
+func LoadFeedData(ctx context.Context, configId int32, from time.Time, to time.Time) ([]*FeedData, error) {
+	rows, err := db.QueryRows[feedDataRow](ctx, SelectFeedDataByConfigQuery, map[string]any{"config_id": configId, "from": from, "to": to})
+	if err != nil {
+		log.Error().Str("Player", "Backtest").Err(err).Int32("configId", configId).Msg("failed to load feed data")
+		return nil, err
+	}
+
+	result := make([]*FeedData, 0, len(rows))
+	for _, row := range rows {
+		if !row.Value.Valid || row.Value.Int == nil {
+			continue
+		}
+		result = append(result, &FeedData{
+			FeedID:    row.FeedID,
+			Value:     decimal.ScaleInt(row.Value.Int, int(row.Value.Exp)),
+			Volume:    row.Volume,
+			Timestamp: row.Timestamp,
+			Spread:    row.Spread,
+		})
+	}
+	return result, nil
+}
+
+// LoadGlobalAggregates reads the stored global aggregates of the config within the range as a reference series
+func LoadGlobalAggregates(ctx context.Context, configId int32, from time.Time, to time.Time) ([]Point, error) {
+	globalAggregates, err := db.QueryRows[types.GlobalAggregate](ctx, SelectGlobalAggregatesQuery, map[string]any{"config_id": configId, "from": from, "to": to})
+	if err != nil {
+		log.Error().Str("Player", "Backtest").Err(err).Int32("configId", configId).Msg("failed to load global aggregates")
+		return nil, err
+	}
+
+	points := make([]Point, len(globalAggregates))
+	for i, globalAggregate := range globalAggregates {
+		points[i] = Point{Timestamp: globalAggregate.Timestamp, Value: float64(globalAggregate.Value)}
+	}
+	return points, nil
+}
+
+// LoadFeedDataCsv reads a feed_data export with a header holding feed_id, value, timestamp and optionally volume and spread.
+// Rows outside of from and to are skipped, zero times leave the range open.
+func LoadFeedDataCsv(path string, from time.Time, to time.Time) ([]*FeedData, error) {
+	feedData := []*FeedData{}
+	err := readCsv(path, []string{"feed_id", "value", "timestamp"}, func(row map[string]string) error {
+		timestamp, err := parseTimestamp(row["timestamp"])
+		if err != nil {
+			return err
+		}
+		if !inRange(timestamp, from, to) {
+			return nil
+		}
+
+		feedId, err := strconv.ParseInt(row["feed_id"], 10, 32)
+		if err != nil {
+			return err
+		}
+		value, err := decimal.Parse(row["value"])
+		if err != nil {
+			return err
+		}
+		volume := 0.0
+		if row["volume"] != "" {
+			volume, err = strconv.ParseFloat(row["volume"], 64)
+			if err != nil {
+				return err
+			}
+		}
+
+		var spread *float64
+		if row["spread"] != "" {
+			parsed, err := strconv.ParseFloat(row["spread"], 64)
+			if err != nil {
+				return err
+			}
+			spread = &parsed
+		}
+
+		feedData = append(feedData, &FeedData{FeedID: int32(feedId), Value: decimal.Round(value), Volume: volume, Timestamp: &timestamp, Spread: spread})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(feedData, func(i, j int) bool { return feedData[i].Timestamp.Before(*feedData[j].Timestamp) })
+	return feedData, nil
+}
+
+// LoadReferenceCsv reads a reference series with a header holding timestamp and value
+func LoadReferenceCsv(path string, from time.Time, to time.Time) ([]Point, error) {
+	points := []Point{}
+	err := readCsv(path, []string{"timestamp", "value"}, func(row map[string]string) error {
+		timestamp, err := parseTimestamp(row["timestamp"])
+		if err != nil {
+			return err
+		}
+		if !inRange(timestamp, from, to) {
+			return nil
+		}
+
+		value, err := strconv.ParseFloat(row["value"], 64)
+		if err != nil {
+			return err
+		}
+		points = append(points, Point{Timestamp: timestamp, Value: value})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points, nil
+}
+
+func readCsv(path string, required []string, handle func(row map[string]string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		log.Error().Str("Player", "Backtest").Err(err).Str("path", path).Msg("failed to read csv header")
+		return errorSentinel.ErrBacktestInvalidCsv
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	for _, column := range required {
+		found := false
+		for _, name := range header {
+			found = found || name == column
+		}
+		if !found {
+			log.Error().Str("Player", "Backtest").Str("path", path).Str("column", column).Msg("missing csv column")
+			return errorSentinel.ErrBacktestInvalidCsv
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Error().Str("Player", "Backtest").Err(err).Str("path", path).Int("line", line).Msg("failed to read csv row")
+			return errorSentinel.ErrBacktestInvalidCsv
+		}
+
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = strings.TrimSpace(record[i])
+			}
+		}
+		err = handle(row)
+		if err != nil {
+			log.Error().Str("Player", "Backtest").Err(err).Str("path", path).Int("line", line).Msg("invalid csv row")
+			return errorSentinel.ErrBacktestInvalidCsv
+		}
+	}
+}
+
+func parseTimestamp(raw string) (time.Time, error) {
+	unix, err := strconv.ParseInt(raw, 10, 64)
+	if err == nil {
+		// milliseconds from 2001 on
+		if unix > 1_000_000_000_000 {
+			return time.UnixMilli(unix), nil
+		}
+		return time.Unix(unix, 0), nil
+	}
+
+	for _, layout := range timestampLayouts {
+		timestamp, err := time.Parse(layout, raw)
+		if err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, errorSentinel.ErrBacktestInvalidCsv
+}
+
+func inRange(timestamp time.Time, from time.Time, to time.Time) bool {
+	return (from.IsZero() || !timestamp.Before(from)) && (to.IsZero() || !timestamp.After(to))
+}
@@ -0,0 +1,63 @@
+package backtest
+
+import (
+	"time"
+
+	"bisonai.com/miko/node/pkg/fetcher"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	SelectConfigIdByNameQuery   = `SELECT id FROM configs WHERE name = @name`
+	SelectFeedDataByConfigQuery = `SELECT feed_id, value, timestamp, volume, spread FROM feed_data WHERE feed_id IN (SELECT id FROM feeds WHERE config_id = @config_id) AND timestamp BETWEEN @from AND @to ORDER BY timestamp`
+	SelectGlobalAggregatesQuery = `SELECT config_id, value, round, timestamp FROM global_aggregates WHERE config_id = @config_id AND timestamp BETWEEN @from AND @to ORDER BY timestamp`
+
+	DefaultAlgorithm = "default"
+	// feeds without an update for this long are left out of the aggregation, as the websocket health registry does
+	DefaultStaleness = 2 * time.Minute
+)
+
+type FeedData = fetcher.FeedData
+
+// feedDataRow is feed_data as stored, pgx scans NUMERIC values into pgtype.Numeric but not into *big.Int
+type feedDataRow struct {
+	FeedID    int32          `db:"feed_id"`
+	Value     pgtype.Numeric `db:"value"`
+	Volume    float64        `db:"volume"`
+	Timestamp *time.Time     `db:"timestamp"`
+	Spread    *float64       `db:"spread"`
+}
+
+type ConfigId struct {
+	ID int32 `db:"id"`
+}
+
+// Point is a value of a series under comparison, only reported so float64 is precise enough
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+type ReplayConfig struct {
+	Name      string // config name, fx pairs are aggregated differently
+	Algorithm fetcher.AggregationAlgorithm
+	Interval  time.Duration
+	Staleness time.Duration
+}
+
+type ReplayStats struct {
+	Ticks             int `json:"ticks"`
+	Errors            int `json:"errors"`
+	OutliersRemoved   int `json:"outliersRemoved"`
+	TicksWithOutliers int `json:"ticksWithOutliers"`
+}
+
+type Report struct {
+	ReplayStats
+	Compared int `json:"compared"`
+	// relative deviations from the reference
+	MaxDeviation   float64    `json:"maxDeviation"`
+	MaxDeviationAt *time.Time `json:"maxDeviationAt"`
+	MeanDeviation  float64    `json:"meanDeviation"`
+	TrackingError  float64    `json:"trackingError"` // standard deviation of the signed relative difference
+}
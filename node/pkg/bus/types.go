@@ -17,6 +17,9 @@ const (
 	STOP_FETCHER_APP    = "stop_fetcher_app"
 	REFRESH_FETCHER_APP = "refresh_fetcher_app"
 
+	GET_WEBSOCKET_HEALTH = "get_websocket_health"
+	DRY_RUN_FEED         = "dry_run_feed"
+
 	ACTIVATE_FETCHER   = "activate_fetcher"
 	DEACTIVATE_FETCHER = "deactivate_fetcher"
 
@@ -29,7 +29,7 @@ func setProviderAndReporter(config *ChainHelperConfig, blockchainType Blockchain
 			}
 		}
 
-		if config.ReporterPk == "" {
+		if config.ReporterPk == "" && !config.ReadOnly {
 			config.ReporterPk = secrets.GetSecret(KaiaReporterPk)
 			if config.ReporterPk == "" {
 				log.Warn().Msg("reporter pk not set")
@@ -44,7 +44,7 @@ func setProviderAndReporter(config *ChainHelperConfig, blockchainType Blockchain
 			}
 		}
 
-		if config.ReporterPk == "" {
+		if config.ReporterPk == "" && !config.ReadOnly {
 			config.ReporterPk = secrets.GetSecret(EthReporterPk)
 			if config.ReporterPk == "" {
 				log.Warn().Msg("reporter pk not set")
@@ -81,6 +81,13 @@ func NewChainHelper(ctx context.Context, opts ...ChainHelperOption) (*ChainHelpe
 		return nil, err
 	}
 
+	if config.ReadOnly {
+		return &ChainHelper{
+			client:  primaryClient,
+			chainID: chainID,
+		}, nil
+	}
+
 	wallet := strings.TrimPrefix(config.ReporterPk, "0x")
 
 	nonceManager, err := noncemanagerv2.New(ctx, primaryClient, wallet)
@@ -88,7 +95,11 @@ func NewChainHelper(ctx context.Context, opts ...ChainHelperOption) (*ChainHelpe
 		return nil, err
 	}
 
-	delegatorUrl := os.Getenv(EnvDelegatorUrl)
+	// fee delegation is kaia only, other chains always submit direct
+	delegatorUrl := ""
+	if config.BlockchainType == Kaia {
+		delegatorUrl = os.Getenv(EnvDelegatorUrl)
+	}
 
 	return &ChainHelper{
 		client:       primaryClient,
@@ -96,6 +107,7 @@ func NewChainHelper(ctx context.Context, opts ...ChainHelperOption) (*ChainHelpe
 		chainID:      chainID,
 		delegatorUrl: delegatorUrl,
 		noncemanager: nonceManager,
+		txTracker:    NewTxTracker(primaryClient, config.TxTrackerOptions...),
 	}, nil
 }
 
@@ -186,26 +198,43 @@ func (t *ChainHelper) PublicAddressString() (string, error) {
 	return address.Hex(), nil
 }
 
+// SubmitDelegatedFallbackDirect sends the tx and tracks it until it is mined, replacing it with a bumped gas price
+// while it stays pending. Reverted and dropped transactions are returned as errors.
 func (t *ChainHelper) SubmitDelegatedFallbackDirect(ctx context.Context, contractAddress, functionString string, args ...interface{}) error {
 	nonce := t.noncemanager.GetNonce()
 	log.Debug().Uint64("nonce", nonce).Msg("nonce")
 
-	tx, err := utils.MakeFeeDelegatedTx(ctx, t.client, contractAddress, t.wallet, functionString, t.chainID, nonce, args...)
+	tx, err := t.makeDelegatedFallbackDirectTx(ctx, contractAddress, functionString, nonce, nil, args...)
 	if err != nil {
 		return err
 	}
 
-	tx, err = t.GetSignedFromDelegator(tx)
+	err = utils.SendRawTx(ctx, t.client, tx)
 	if err != nil {
-		tx, err = utils.MakeDirectTx(ctx, t.client, contractAddress, t.wallet, functionString, t.chainID, nonce, args...)
-		if err != nil {
-			return err
-		}
+		return err
+	}
+
+	result, err := t.txTracker.Track(ctx, tx, func(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error) {
+		return t.makeDelegatedFallbackDirectTx(ctx, contractAddress, functionString, nonce, gasPrice, args...)
+	})
+	if err != nil {
+		return err
+	}
+	return result.Err()
+}
 
-		return utils.SubmitRawTx(ctx, t.client, tx)
+// fee delegated tx signed by the delegator, or a direct tx if the delegator is unavailable
+func (t *ChainHelper) makeDelegatedFallbackDirectTx(ctx context.Context, contractAddress, functionString string, nonce uint64, gasPrice *big.Int, args ...interface{}) (*types.Transaction, error) {
+	tx, err := utils.MakeFeeDelegatedTxWithGasPrice(ctx, t.client, contractAddress, t.wallet, functionString, t.chainID, nonce, gasPrice, args...)
+	if err != nil {
+		return nil, err
 	}
 
-	return utils.SubmitRawTx(ctx, t.client, tx)
+	signedTx, err := t.GetSignedFromDelegator(tx)
+	if err != nil {
+		return utils.MakeDirectTxWithGasPrice(ctx, t.client, contractAddress, t.wallet, functionString, t.chainID, nonce, gasPrice, args...)
+	}
+	return signedTx, nil
 }
 
 func (t *ChainHelper) SubmitDirect(ctx context.Context, contractAddress, functionString string, args ...interface{}) error {
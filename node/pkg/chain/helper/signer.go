@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/common/types"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/secrets"
 	"github.com/klaytn/klaytn/common"
@@ -119,10 +120,14 @@ func NewSigner(ctx context.Context, opts ...SignerOption) (*Signer, error) {
 }
 
 func (s *Signer) MakeGlobalAggregateProof(val int64, timestamp time.Time, name string) ([]byte, error) {
+	return s.MakeGlobalAggregateProofWithDecimals(val, timestamp, name, types.DefaultDecimals)
+}
+
+func (s *Signer) MakeGlobalAggregateProofWithDecimals(val int64, timestamp time.Time, name string, decimals int32) ([]byte, error) {
 	s.mu.RLock()
 	pk := s.PK
 	s.mu.RUnlock()
-	return utils.MakeValueSignature(val, timestamp.UnixMilli(), name, pk)
+	return utils.MakeValueSignatureWithDecimals(val, timestamp.UnixMilli(), name, decimals, pk)
 }
 
 func (s *Signer) autoRenew(ctx context.Context) {
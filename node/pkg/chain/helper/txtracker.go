@@ -0,0 +1,196 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/big"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/utils"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/rs/zerolog/log"
+)
+
+func NewTxTracker(client utils.ClientInterface, opts ...TxTrackerOption) *TxTracker {
+	config := TxTrackerConfig{
+		PollInterval:    DefaultTxPollInterval,
+		PendingDeadline: DefaultTxPendingDeadline,
+		GasBumpPercent:  DefaultGasBumpPercent,
+		MaxReplacements: DefaultMaxTxReplacements,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &TxTracker{
+		client:  client,
+		config:  config,
+		pending: make(map[uint64]common.Hash),
+	}
+}
+
+func (s TxStatus) String() string {
+	switch s {
+	case TxConfirmed:
+		return "confirmed"
+	case TxReverted:
+		return "reverted"
+	case TxDropped:
+		return "dropped"
+	default:
+		return "pending"
+	}
+}
+
+// Err maps reverted and dropped transactions to errors, confirmed ones to nil
+func (r TxResult) Err() error {
+	switch r.Status {
+	case TxReverted:
+		if r.OutOfGas {
+			return errorSentinel.ErrChainTransactionOutOfGas
+		}
+		return errorSentinel.ErrChainTransactionFail
+	case TxDropped:
+		return errorSentinel.ErrChainTransactionDropped
+	default:
+		return nil
+	}
+}
+
+// Pending returns the latest hash sent for every nonce still waiting to be mined
+func (t *TxTracker) Pending() map[uint64]common.Hash {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return maps.Clone(t.pending)
+}
+
+// Track polls receipts of an already sent transaction until it is mined. Transactions pending past the deadline
+// are replaced at the same nonce with a bumped gas price, once the replacements are used up they are given up as
+// dropped. Every hash sent for the nonce is polled, any of them may be the one which gets mined.
+func (t *TxTracker) Track(ctx context.Context, tx *types.Transaction, replace ReplaceTxFunc) (TxResult, error) {
+	nonce := tx.Nonce()
+	hashes := []common.Hash{tx.Hash()}
+	gasPrice := tx.GasPrice()
+	gasLimit := tx.Gas()
+	result := TxResult{Status: TxPending, Nonce: nonce, Hash: tx.Hash()}
+
+	t.setPending(nonce, tx.Hash())
+	defer t.removePending(nonce)
+
+	deadline := time.Now().Add(t.config.PendingDeadline)
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+
+		receipt := t.receipt(ctx, hashes)
+		if receipt != nil {
+			return withReceipt(result, receipt, gasLimit), nil
+		}
+		if time.Now().Before(deadline) {
+			continue
+		}
+
+		if replace == nil || result.Replacements >= t.config.MaxReplacements {
+			log.Warn().Str("Player", "TxTracker").Uint64("nonce", nonce).Str("tx", result.Hash.String()).Int("replacements", result.Replacements).Msg("tx not mined, giving up")
+			result.Status = TxDropped
+			return result, nil
+		}
+
+		// failed attempts count as well, so a replacement which keeps failing can not hold the nonce forever
+		result.Replacements++
+		deadline = time.Now().Add(t.config.PendingDeadline)
+
+		bumped := t.bumpGasPrice(ctx, gasPrice)
+		replacement, err := replace(ctx, bumped)
+		if err == nil {
+			err = utils.SendRawTx(ctx, t.client, replacement)
+		}
+		if err != nil {
+			if utils.IsNonceError(err) {
+				// the nonce is used up, either by one of the sent hashes or by another tx
+				receipt = t.receipt(ctx, hashes)
+				if receipt != nil {
+					return withReceipt(result, receipt, gasLimit), nil
+				}
+				result.Status = TxDropped
+				return result, nil
+			}
+			log.Warn().Str("Player", "TxTracker").Err(err).Uint64("nonce", nonce).Msg("failed to replace pending tx")
+			continue
+		}
+
+		log.Info().Str("Player", "TxTracker").Uint64("nonce", nonce).Str("tx", replacement.Hash().String()).Str("gasPrice", bumped.String()).Msg("replaced pending tx")
+		gasPrice = bumped
+		gasLimit = replacement.Gas()
+		hashes = append(hashes, replacement.Hash())
+		result.Hash = replacement.Hash()
+		t.setPending(nonce, result.Hash)
+	}
+}
+
+// receipt of whichever hash got mined, nil while all of them are pending
+func (t *TxTracker) receipt(ctx context.Context, hashes []common.Hash) *types.Receipt {
+	for i := len(hashes) - 1; i >= 0; i-- {
+		receipt, err := t.client.TransactionReceipt(ctx, hashes[i])
+		if err != nil {
+			if !errors.Is(err, klaytn.NotFound) {
+				log.Debug().Str("Player", "TxTracker").Err(err).Str("tx", hashes[i].String()).Msg("failed to get receipt")
+			}
+			continue
+		}
+		if receipt != nil {
+			return receipt
+		}
+	}
+	return nil
+}
+
+// bumpGasPrice raises the gas price by the bump percent, or to the suggested gas price if that is higher
+func (t *TxTracker) bumpGasPrice(ctx context.Context, gasPrice *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(gasPrice, big.NewInt(100+t.config.GasBumpPercent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(gasPrice) <= 0 {
+		bumped.Add(gasPrice, big.NewInt(1))
+	}
+
+	suggested, err := t.client.SuggestGasPrice(ctx)
+	if err == nil && suggested.Cmp(bumped) > 0 {
+		return suggested
+	}
+	return bumped
+}
+
+func (t *TxTracker) setPending(nonce uint64, hash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[nonce] = hash
+}
+
+func (t *TxTracker) removePending(nonce uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, nonce)
+}
+
+func withReceipt(result TxResult, receipt *types.Receipt, gasLimit uint64) TxResult {
+	result.Hash = receipt.TxHash
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		// kaia reports out of gas as its own status, ethereum receipts only show the whole gas limit used up
+		result.OutOfGas = receipt.Status == types.ReceiptStatusErrOutOfGas || receipt.GasUsed >= gasLimit
+		log.Error().Str("Player", "TxTracker").Str("tx", receipt.TxHash.String()).Bool("outOfGas", result.OutOfGas).Msg("tx reverted")
+		result.Status = TxReverted
+		return result
+	}
+	result.Status = TxConfirmed
+	return result
+}
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"math/big"
 	"sync"
@@ -9,7 +10,9 @@ import (
 	"bisonai.com/miko/node/pkg/chain/eth_client"
 	"bisonai.com/miko/node/pkg/chain/noncemanagerv2"
 	"bisonai.com/miko/node/pkg/chain/utils"
+	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/client"
+	"github.com/klaytn/klaytn/common"
 )
 
 type ChainHelper struct {
@@ -18,6 +21,7 @@ type ChainHelper struct {
 	chainID      *big.Int
 	delegatorUrl string
 	noncemanager *noncemanagerv2.NonceManagerV2
+	txTracker    *TxTracker
 }
 
 type ChainHelperConfig struct {
@@ -25,6 +29,8 @@ type ChainHelperConfig struct {
 	ReporterPk                string
 	BlockchainType            BlockchainType
 	UseAdditionalProviderUrls bool
+	ReadOnly                  bool
+	TxTrackerOptions          []TxTrackerOption
 }
 
 type ChainHelperOption func(*ChainHelperConfig)
@@ -47,6 +53,85 @@ func WithBlockchainType(t BlockchainType) ChainHelperOption {
 	}
 }
 
+// read only helpers skip the reporter pk and nonce manager, only contract reads are available
+func WithReadOnly() ChainHelperOption {
+	return func(c *ChainHelperConfig) {
+		c.ReadOnly = true
+	}
+}
+
+// WithTxTrackerOptions configures how submitted transactions are tracked until they are mined
+func WithTxTrackerOptions(opts ...TxTrackerOption) ChainHelperOption {
+	return func(c *ChainHelperConfig) {
+		c.TxTrackerOptions = opts
+	}
+}
+
+type TxStatus int
+
+const (
+	TxPending TxStatus = iota
+	TxConfirmed
+	TxReverted
+	TxDropped
+)
+
+// TxResult is the outcome of a tracked transaction
+type TxResult struct {
+	Status       TxStatus
+	Nonce        uint64
+	Hash         common.Hash // hash which got mined, the last one sent otherwise
+	Replacements int
+	OutOfGas     bool // reverted for running out of gas rather than by the contract
+}
+
+// ReplaceTxFunc rebuilds a pending transaction at the same nonce with the given gas price
+type ReplaceTxFunc func(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error)
+
+type TxTrackerConfig struct {
+	PollInterval    time.Duration
+	PendingDeadline time.Duration
+	GasBumpPercent  int64
+	MaxReplacements int
+}
+
+type TxTrackerOption func(*TxTrackerConfig)
+
+// WithTxPollInterval sets how often receipts of pending transactions are polled
+func WithTxPollInterval(interval time.Duration) TxTrackerOption {
+	return func(c *TxTrackerConfig) {
+		c.PollInterval = interval
+	}
+}
+
+// WithTxPendingDeadline sets how long a transaction may stay pending before it is replaced
+func WithTxPendingDeadline(deadline time.Duration) TxTrackerOption {
+	return func(c *TxTrackerConfig) {
+		c.PendingDeadline = deadline
+	}
+}
+
+// WithGasBumpPercent sets how much the gas price of a replacement is raised over the pending transaction
+func WithGasBumpPercent(percent int64) TxTrackerOption {
+	return func(c *TxTrackerConfig) {
+		c.GasBumpPercent = percent
+	}
+}
+
+// WithMaxTxReplacements sets how often a pending transaction is replaced before it is given up as dropped
+func WithMaxTxReplacements(replacements int) TxTrackerOption {
+	return func(c *TxTrackerConfig) {
+		c.MaxReplacements = replacements
+	}
+}
+
+type TxTracker struct {
+	client  utils.ClientInterface
+	config  TxTrackerConfig
+	pending map[uint64]common.Hash
+	mu      sync.RWMutex
+}
+
 type Signer struct {
 	PK                          *ecdsa.PrivateKey
 	chainHelper                 *ChainHelper
@@ -88,6 +173,10 @@ const (
 	EthReporterPk   = "ETH_REPORTER_PK"
 
 	DelegatorTimeout            = 10 * time.Second
+	DefaultTxPollInterval       = time.Second
+	DefaultTxPendingDeadline    = 10 * time.Second
+	DefaultGasBumpPercent       = 20 // nodes reject replacements below a 10% bump
+	DefaultMaxTxReplacements    = 3
 	DefaultSignerRenewInterval  = 12 * time.Hour
 	DefaultSignerRenewThreshold = 7 * 24 * time.Hour
 	SignerDetailFuncSignature   = "whitelist(address) returns ((uint256, uint256))"
@@ -510,6 +510,29 @@ func TestMakeGlobalAggregateProof(t *testing.T) {
 	assert.Equal(t, addrFromEnv.Hex(), addr.Hex())
 }
 
+func TestValue2HashForSignWithDecimals(t *testing.T) {
+	timestamp := time.Now().UnixMilli()
+
+	// default decimals keep the hash the submission proxy verifies for existing feeds
+	assert.Equal(t, utils.Value2HashForSign(200000000, timestamp, "test-aggregate"), utils.Value2HashForSignWithDecimals(200000000, timestamp, "test-aggregate", 8))
+	assert.NotEqual(t, utils.Value2HashForSign(200000000, timestamp, "test-aggregate"), utils.Value2HashForSignWithDecimals(200000000, timestamp, "test-aggregate", 6))
+
+	pk, err := utils.StringToPk("0x27894b84849f129e08f37634be4e8ccc4c7267d824eb8cfd285185854ba5b78d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	proof, err := utils.MakeValueSignatureWithDecimals(200000000, timestamp, "test-aggregate", 6, pk)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	addr, err := utils.RecoverSigner(utils.Value2HashForSignWithDecimals(200000000, timestamp, "test-aggregate", 6), proof)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assert.Equal(t, crypto.PubkeyToAddress(pk.PublicKey).Hex(), addr.Hex())
+}
+
 func TestMakeMultiGlobalAggregateProof(t *testing.T) {
 	ctx := context.Background()
 
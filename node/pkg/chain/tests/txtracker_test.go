@@ -0,0 +1,199 @@
+//nolint:all
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/chain/utils"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiptClient mines transactions from the mined callback, every other client call is left unimplemented
+type receiptClient struct {
+	utils.ClientInterface
+
+	mu      sync.Mutex
+	sent    []*types.Transaction
+	mined   func(tx *types.Transaction) *types.Receipt
+	sendErr error
+}
+
+func (c *receiptClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendErr != nil {
+		return c.sendErr
+	}
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *receiptClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tx := range c.sent {
+		if tx.Hash() != hash {
+			continue
+		}
+		if receipt := c.mined(tx); receipt != nil {
+			return receipt, nil
+		}
+	}
+	return nil, klaytn.NotFound
+}
+
+func (c *receiptClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func signedTestTx(t *testing.T, nonce uint64, gasPrice *big.Int) *types.Transaction {
+	pk, err := crypto.HexToECDSA("27894b84849f129e08f37634be4e8ccc4c7267d824eb8cfd285185854ba5b78d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tx := types.NewTransaction(nonce, common.HexToAddress("0x93120927379723583c7a0dd2236fcb255e96949f"), big.NewInt(0), 21000, gasPrice, nil)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(1001)), pk)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return signed
+}
+
+func newTestTracker(client utils.ClientInterface) *helper.TxTracker {
+	return helper.NewTxTracker(client,
+		helper.WithTxPollInterval(5*time.Millisecond),
+		helper.WithTxPendingDeadline(20*time.Millisecond),
+		helper.WithGasBumpPercent(20),
+		helper.WithMaxTxReplacements(2))
+}
+
+func TestTxTrackerConfirmed(t *testing.T) {
+	ctx := context.Background()
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt {
+		return &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}
+	}}
+
+	tx := signedTestTx(t, 1, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	result, err := newTestTracker(client).Track(ctx, tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.TxConfirmed, result.Status)
+	assert.Equal(t, tx.Hash(), result.Hash)
+	assert.NoError(t, result.Err())
+}
+
+func TestTxTrackerReverted(t *testing.T) {
+	ctx := context.Background()
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt {
+		return &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash()}
+	}}
+
+	tx := signedTestTx(t, 1, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	result, err := newTestTracker(client).Track(ctx, tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.TxReverted, result.Status)
+	assert.ErrorIs(t, result.Err(), errorSentinel.ErrChainTransactionFail)
+}
+
+func TestTxTrackerOutOfGas(t *testing.T) {
+	ctx := context.Background()
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt {
+		return &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash(), GasUsed: tx.Gas()}
+	}}
+
+	tx := signedTestTx(t, 1, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	result, err := newTestTracker(client).Track(ctx, tx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.TxReverted, result.Status)
+	assert.True(t, result.OutOfGas)
+	assert.ErrorIs(t, result.Err(), errorSentinel.ErrChainTransactionOutOfGas)
+}
+
+func TestTxTrackerReplacesWithBumpedGasPrice(t *testing.T) {
+	ctx := context.Background()
+	// only gas prices of at least 144 get mined, two bumps of 20% away from 100
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt {
+		if tx.GasPrice().Cmp(big.NewInt(144)) < 0 {
+			return nil
+		}
+		return &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}
+	}}
+
+	tx := signedTestTx(t, 7, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	tracker := newTestTracker(client)
+	gasPrices := []*big.Int{}
+	result, err := tracker.Track(ctx, tx, func(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error) {
+		gasPrices = append(gasPrices, gasPrice)
+		return signedTestTx(t, 7, gasPrice), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, helper.TxConfirmed, result.Status)
+	assert.Equal(t, 2, result.Replacements)
+	assert.Equal(t, []*big.Int{big.NewInt(120), big.NewInt(144)}, gasPrices)
+	assert.Equal(t, uint64(7), result.Nonce)
+	assert.Empty(t, tracker.Pending())
+
+	for _, sent := range client.sent {
+		assert.Equal(t, uint64(7), sent.Nonce())
+	}
+}
+
+func TestTxTrackerDropped(t *testing.T) {
+	ctx := context.Background()
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt { return nil }}
+
+	tx := signedTestTx(t, 3, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	t.Run("replacements used up", func(t *testing.T) {
+		result, err := newTestTracker(client).Track(ctx, tx, func(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error) {
+			return signedTestTx(t, 3, gasPrice), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, helper.TxDropped, result.Status)
+		assert.Equal(t, 2, result.Replacements)
+		assert.ErrorIs(t, result.Err(), errorSentinel.ErrChainTransactionDropped)
+	})
+
+	t.Run("nonce used by another tx", func(t *testing.T) {
+		client.sendErr = errors.New("nonce too low")
+		result, err := newTestTracker(client).Track(ctx, tx, func(ctx context.Context, gasPrice *big.Int) (*types.Transaction, error) {
+			return signedTestTx(t, 3, gasPrice), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, helper.TxDropped, result.Status)
+		assert.Equal(t, 1, result.Replacements)
+	})
+}
+
+func TestTxTrackerContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	client := &receiptClient{mined: func(tx *types.Transaction) *types.Receipt { return nil }}
+
+	tx := signedTestTx(t, 1, big.NewInt(100))
+	assert.NoError(t, client.SendTransaction(ctx, tx))
+
+	tracker := helper.NewTxTracker(client, helper.WithTxPollInterval(time.Millisecond), helper.WithTxPendingDeadline(time.Hour))
+	result, err := tracker.Track(ctx, tx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, helper.TxPending, result.Status)
+}
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	commonTypes "bisonai.com/miko/node/pkg/common/types"
 	"bisonai.com/miko/node/pkg/db"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/utils/encryptor"
@@ -157,6 +158,12 @@ func GetChainID(ctx context.Context, client ClientInterface) (*big.Int, error) {
 }
 
 func MakeDirectTx(ctx context.Context, client ClientInterface, contractAddressHex string, reporter string, functionString string, chainID *big.Int, nonce uint64, args ...interface{}) (*types.Transaction, error) {
+	return MakeDirectTxWithGasPrice(ctx, client, contractAddressHex, reporter, functionString, chainID, nonce, nil, args...)
+}
+
+// MakeDirectTxWithGasPrice builds the tx with the given gas price, e.g. to replace a pending tx at the same nonce.
+// A nil gas price uses the suggested one.
+func MakeDirectTxWithGasPrice(ctx context.Context, client ClientInterface, contractAddressHex string, reporter string, functionString string, chainID *big.Int, nonce uint64, gasPrice *big.Int, args ...interface{}) (*types.Transaction, error) {
 	if client == nil {
 		return nil, errorSentinel.ErrChainEmptyClientParam
 	}
@@ -205,9 +212,11 @@ func MakeDirectTx(ctx context.Context, client ClientInterface, contractAddressHe
 		return nil, err
 	}
 
-	gasPrice, err := client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, err
+	if gasPrice == nil {
+		gasPrice, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	contractAddress := common.HexToAddress(contractAddressHex)
@@ -230,6 +239,11 @@ func MakeDirectTx(ctx context.Context, client ClientInterface, contractAddressHe
 }
 
 func MakeFeeDelegatedTx(ctx context.Context, client ClientInterface, contractAddressHex string, reporter string, functionString string, chainID *big.Int, nonce uint64, args ...interface{}) (*types.Transaction, error) {
+	return MakeFeeDelegatedTxWithGasPrice(ctx, client, contractAddressHex, reporter, functionString, chainID, nonce, nil, args...)
+}
+
+// MakeFeeDelegatedTxWithGasPrice builds the tx with the given gas price, a nil gas price uses the suggested one
+func MakeFeeDelegatedTxWithGasPrice(ctx context.Context, client ClientInterface, contractAddressHex string, reporter string, functionString string, chainID *big.Int, nonce uint64, gasPrice *big.Int, args ...interface{}) (*types.Transaction, error) {
 	if client == nil {
 		return nil, errorSentinel.ErrChainEmptyClientParam
 	}
@@ -286,9 +300,11 @@ func MakeFeeDelegatedTx(ctx context.Context, client ClientInterface, contractAdd
 
 	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 
-	gasPrice, err := client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, err
+	if gasPrice == nil {
+		gasPrice, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	contractAddress := common.HexToAddress(contractAddressHex)
@@ -346,13 +362,10 @@ func SignTxByFeePayer(ctx context.Context, tx *types.Transaction, chainID *big.I
 }
 
 func SubmitRawTx(ctx context.Context, client ClientInterface, tx *types.Transaction) error {
-	log.Debug().Str("Player", "ChainHelper").Str("tx", tx.Hash().String()).Msg("submitting tx")
-	err := client.SendTransaction(ctx, tx)
+	err := SendRawTx(ctx, client, tx)
 	if err != nil {
-		log.Error().Str("Player", "ChainHelper").Err(err).Msg("failed to send tx")
 		return err
 	}
-	log.Debug().Str("Player", "ChainHelper").Str("tx", tx.Hash().String()).Msg("tx sent")
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, DEFAULT_MINE_WAIT_TIME)
 	defer cancel()
@@ -374,6 +387,18 @@ func SubmitRawTx(ctx context.Context, client ClientInterface, tx *types.Transact
 	return nil
 }
 
+// SendRawTx sends the tx without waiting for it to be mined
+func SendRawTx(ctx context.Context, client ClientInterface, tx *types.Transaction) error {
+	log.Debug().Str("Player", "ChainHelper").Str("tx", tx.Hash().String()).Msg("submitting tx")
+	err := client.SendTransaction(ctx, tx)
+	if err != nil {
+		log.Error().Str("Player", "ChainHelper").Err(err).Msg("failed to send tx")
+		return err
+	}
+	log.Debug().Str("Player", "ChainHelper").Str("tx", tx.Hash().String()).Msg("tx sent")
+	return nil
+}
+
 func SubmitRawTxString(ctx context.Context, client ClientInterface, rawTx string) error {
 	rawTxBytes, err := hex.DecodeString(rawTx)
 	if err != nil {
@@ -566,7 +591,11 @@ func IsJsonRpcFailureError(errorCode int) bool {
 }
 
 func MakeValueSignature(value int64, timestamp int64, name string, pk *ecdsa.PrivateKey) ([]byte, error) {
-	hash := Value2HashForSign(value, timestamp, name)
+	return MakeValueSignatureWithDecimals(value, timestamp, name, commonTypes.DefaultDecimals, pk)
+}
+
+func MakeValueSignatureWithDecimals(value int64, timestamp int64, name string, decimals int32, pk *ecdsa.PrivateKey) ([]byte, error) {
+	hash := Value2HashForSignWithDecimals(value, timestamp, name, decimals)
 	signature, err := crypto.Sign(hash, pk)
 	if err != nil {
 		return nil, err
@@ -581,6 +610,13 @@ func MakeValueSignature(value int64, timestamp int64, name string, pk *ecdsa.Pri
 }
 
 func Value2HashForSign(value int64, timestamp int64, name string) []byte {
+	return Value2HashForSignWithDecimals(value, timestamp, name, commonTypes.DefaultDecimals)
+}
+
+// Value2HashForSignWithDecimals binds the decimals of the value into the hash, so a proof can not be replayed
+// for the same digits on another scale. Default decimals keep the original layout, proofs of existing feeds
+// stay valid with the submission proxy.
+func Value2HashForSignWithDecimals(value int64, timestamp int64, name string, decimals int32) []byte {
 	bigIntVal := big.NewInt(value)
 	bigIntTimestamp := big.NewInt(timestamp)
 
@@ -592,7 +628,14 @@ func Value2HashForSign(value int64, timestamp int64, name string) []byte {
 
 	feedHash := crypto.Keccak256([]byte(name))
 
-	concatBytes := bytes.Join([][]byte{valueBuf, timestampBuf, feedHash}, nil)
+	parts := [][]byte{valueBuf, timestampBuf, feedHash}
+	if decimals != commonTypes.DefaultDecimals {
+		decimalsBuf := make([]byte, 32)
+		big.NewInt(int64(decimals)).FillBytes(decimalsBuf)
+		parts = append(parts, decimalsBuf)
+	}
+
+	concatBytes := bytes.Join(parts, nil)
 	return crypto.Keccak256(concatBytes)
 }
 
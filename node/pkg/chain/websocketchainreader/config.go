@@ -0,0 +1,97 @@
+package websocketchainreader
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/db"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SelectAllProviderUrlsQuery = `SELECT * FROM provider_urls ORDER BY chain_id, priority;`
+	// comma separated <chainId>=<websocket url> pairs, e.g. "42161=wss://arb.example,8453=wss://base.example"
+	ChainWebsocketUrlsEnv = "CHAIN_WEBSOCKET_URLS"
+	// comma separated <chainId>=<retry interval> pairs, e.g. "42161=2s,8453=500ms"
+	ChainRetryIntervalsEnv = "CHAIN_RETRY_INTERVALS"
+)
+
+// LoadChainConfigs collects websocket urls keyed by chain id, from CHAIN_WEBSOCKET_URLS first and then
+// from the websocket entries of the provider_urls table ordered by priority. The first url found for a chain
+// is dialed first, the others are its fallbacks in the same order.
+func LoadChainConfigs(ctx context.Context) ([]ChainConfig, error) {
+	providerUrls, err := db.QueryRows[utils.ProviderUrl](ctx, SelectAllProviderUrlsQuery, nil)
+	if err != nil {
+		log.Error().Err(err).Str("Player", "ChainReader").Msg("failed to load provider urls")
+		return nil, err
+	}
+
+	return buildChainConfigs(os.Getenv(ChainWebsocketUrlsEnv), os.Getenv(ChainRetryIntervalsEnv), providerUrls), nil
+}
+
+func buildChainConfigs(rawUrls string, rawRetryIntervals string, providerUrls []utils.ProviderUrl) []ChainConfig {
+	urls := parseChainPairs(rawUrls)
+	for _, providerUrl := range providerUrls {
+		if providerUrl.ChainId == nil || !isWebsocketUrl(providerUrl.Url) {
+			continue
+		}
+		urls = append(urls, [2]string{strconv.Itoa(*providerUrl.ChainId), providerUrl.Url})
+	}
+
+	retryIntervals := map[string]time.Duration{}
+	for _, pair := range parseChainPairs(rawRetryIntervals) {
+		interval, err := time.ParseDuration(pair[1])
+		if err != nil || interval <= 0 {
+			log.Warn().Str("Player", "ChainReader").Str("chainId", pair[0]).Str("interval", pair[1]).Msg("invalid retry interval, using default")
+			continue
+		}
+		retryIntervals[pair[0]] = interval
+	}
+
+	indexes := map[string]int{}
+	result := []ChainConfig{}
+	for _, pair := range urls {
+		chainId, url := pair[0], pair[1]
+		if index, exists := indexes[chainId]; exists {
+			config := &result[index]
+			if url != config.WebsocketUrl && !slices.Contains(config.FallbackUrls, url) {
+				config.FallbackUrls = append(config.FallbackUrls, url)
+			}
+			continue
+		}
+		indexes[chainId] = len(result)
+
+		config := ChainConfig{ChainId: chainId, WebsocketUrl: url}
+		if interval, exists := retryIntervals[chainId]; exists {
+			config.Policy = &ReconnectPolicy{
+				RetryInterval:    interval,
+				MaxRetryInterval: max(DefaultMaxRetryInterval, interval),
+				RedialThreshold:  DefaultRedialThreshold,
+			}
+		}
+		result = append(result, config)
+	}
+
+	return result
+}
+
+func parseChainPairs(raw string) [][2]string {
+	result := [][2]string{}
+	for _, entry := range strings.Split(raw, ",") {
+		chainId, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || chainId == "" || value == "" {
+			continue
+		}
+		result = append(result, [2]string{strings.TrimSpace(chainId), strings.TrimSpace(value)})
+	}
+	return result
+}
+
+func isWebsocketUrl(url string) bool {
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
@@ -0,0 +1,52 @@
+//nolint:all
+package websocketchainreader
+
+import (
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChainPairs(t *testing.T) {
+	assert.Equal(t, [][2]string{{"42161", "wss://arb.example"}, {"8453", "wss://base.example"}}, parseChainPairs(" 42161 = wss://arb.example ,8453=wss://base.example"))
+	assert.Equal(t, [][2]string{{"1", "2s"}}, parseChainPairs("1=2s,=wss://missing-chain,2=,malformed,"))
+	assert.Empty(t, parseChainPairs(""))
+}
+
+func TestBuildChainConfigs(t *testing.T) {
+	chainId := func(id int) *int { return &id }
+	providerUrls := []utils.ProviderUrl{
+		{ChainId: chainId(42161), Url: "wss://arb-provider.example"},
+		{ChainId: chainId(42161), Url: "https://arb-rpc.example"},
+		{ChainId: chainId(42161), Url: "wss://arb.example"},
+		{ChainId: chainId(8453), Url: "wss://base.example"},
+		{ChainId: nil, Url: "wss://unknown.example"},
+	}
+
+	configs := buildChainConfigs("42161=wss://arb.example", "42161=2s,8453=invalid", providerUrls)
+	assert.Len(t, configs, 2)
+
+	// env urls come first, provider urls follow as fallbacks in priority order without duplicates
+	assert.Equal(t, "42161", configs[0].ChainId)
+	assert.Equal(t, "wss://arb.example", configs[0].WebsocketUrl)
+	assert.Equal(t, []string{"wss://arb-provider.example"}, configs[0].FallbackUrls)
+	assert.Equal(t, 2*time.Second, configs[0].Policy.RetryInterval)
+	assert.Equal(t, DefaultMaxRetryInterval, configs[0].Policy.MaxRetryInterval)
+
+	assert.Equal(t, "8453", configs[1].ChainId)
+	assert.Empty(t, configs[1].FallbackUrls)
+	assert.Nil(t, configs[1].Policy)
+
+	assert.Empty(t, buildChainConfigs("", "", nil))
+}
+
+func TestReconnectPolicyBackoff(t *testing.T) {
+	policy := ReconnectPolicy{RetryInterval: time.Second, MaxRetryInterval: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+	assert.Equal(t, 5*time.Second, policy.backoff(100))
+}
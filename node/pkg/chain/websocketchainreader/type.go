@@ -2,10 +2,12 @@ package websocketchainreader
 
 import (
 	"math/big"
+	"sync"
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/utils"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
 )
 
 type BlockchainType int
@@ -13,12 +15,42 @@ type BlockchainType int
 const (
 	Kaia     BlockchainType = 1
 	Ethereum BlockchainType = 2
+	// chains other than kaia and ethereum are assigned types from here on, in the order they are configured
+	firstGenericChainType BlockchainType = 3
+
+	DefaultRetryInterval    = 1 * time.Second
+	DefaultMaxRetryInterval = 30 * time.Second
+	DefaultRedialThreshold  = 5
+
+	// number of blocks behind the last processed block that are re-read on resubscription to catch reorgs
+	DefaultReorgDepth = 12
+	// max block span of a single FilterLogs call while backfilling
+	DefaultBackfillBlockRange = 1000
 )
 
+// kaia chain ids are dialed with the klaytn client, every other chain with the eth client
+var KaiaChainIds = []string{"8217", "1001"}
+
+type ReconnectPolicy struct {
+	RetryInterval    time.Duration // first wait after a failed subscription, doubled on each consecutive failure
+	MaxRetryInterval time.Duration
+	RedialThreshold  int // consecutive failures before the websocket client is dialed again, 0 disables redialing
+}
+
+type ChainConfig struct {
+	ChainId      string // optional, verified against the node when set
+	WebsocketUrl string
+	FallbackUrls []string         // dialed in order when the websocket url fails
+	Policy       *ReconnectPolicy // falls back to the reader's default policy when nil
+}
+
 type ChainReaderConfig struct {
-	KaiaWebsocketUrl string
-	EthWebsocketUrl  string
-	RetryInterval    time.Duration
+	KaiaWebsocketUrl   string
+	EthWebsocketUrl    string
+	Chains             []ChainConfig
+	RetryInterval      time.Duration
+	ReorgDepth         uint64
+	BackfillBlockRange uint64
 }
 
 type ChainReaderOption func(*ChainReaderConfig)
@@ -35,19 +67,54 @@ func WithEthWebsocketUrl(url string) ChainReaderOption {
 	}
 }
 
+func WithChains(chains []ChainConfig) ChainReaderOption {
+	return func(c *ChainReaderConfig) {
+		c.Chains = append(c.Chains, chains...)
+	}
+}
+
 func WithRetryInterval(interval time.Duration) ChainReaderOption {
 	return func(c *ChainReaderConfig) {
 		c.RetryInterval = interval
 	}
 }
 
+func WithReorgDepth(depth uint64) ChainReaderOption {
+	return func(c *ChainReaderConfig) {
+		c.ReorgDepth = depth
+	}
+}
+
+func WithBackfillBlockRange(blockRange uint64) ChainReaderOption {
+	return func(c *ChainReaderConfig) {
+		c.BackfillBlockRange = blockRange
+	}
+}
+
 type ChainReader struct {
-	KaiaClient         utils.ClientInterface
-	EthClient          utils.ClientInterface
-	RetryPeriod        time.Duration
+	Chains             map[BlockchainType]*Chain
+	ReorgDepth         uint64
+	BackfillBlockRange uint64
+
+	// guarded by mu, chains added without chain id are registered once they were dialed, read it through ChainType
+	mu                 sync.RWMutex
 	ChainIdToChainType map[string]BlockchainType
 }
 
+type Chain struct {
+	ChainId      string
+	WebsocketUrl string
+	Policy       ReconnectPolicy
+
+	mu     sync.RWMutex
+	client utils.ClientInterface // nil until one of the urls could be dialed
+	dial   func(url string) (utils.ClientInterface, error)
+
+	dialMu   sync.Mutex
+	urls     []string
+	urlIndex int
+}
+
 type SubscribeConfig struct {
 	Address     string
 	Ch          chan<- types.Log
@@ -80,3 +147,16 @@ func WithStartBlockNumber(blockNumber *big.Int) SubscribeOption {
 		c.BlockNumber = blockNumber
 	}
 }
+
+type logKey struct {
+	BlockHash common.Hash
+	TxHash    common.Hash
+	Index     uint
+}
+
+// per subscription progress, used to backfill the gap after a reconnection without duplicates
+type subscriptionState struct {
+	lastBlockNumber uint64
+	started         bool
+	seen            map[logKey]uint64
+}
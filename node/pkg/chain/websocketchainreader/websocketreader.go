@@ -2,7 +2,10 @@ package websocketchainreader
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"slices"
+	"sort"
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/eth_client"
@@ -17,65 +20,153 @@ import (
 
 func New(opts ...ChainReaderOption) (*ChainReader, error) {
 	config := &ChainReaderConfig{
-		RetryInterval: 1 * time.Second,
+		RetryInterval:      DefaultRetryInterval,
+		ReorgDepth:         DefaultReorgDepth,
+		BackfillBlockRange: DefaultBackfillBlockRange,
 	}
 	for _, opt := range opts {
 		opt(config)
 	}
 
-	if config.EthWebsocketUrl == "" && config.KaiaWebsocketUrl == "" {
+	if config.EthWebsocketUrl == "" && config.KaiaWebsocketUrl == "" && len(config.Chains) == 0 {
 		return nil, errorSentinel.ErrChainWebsocketUrlNotProvided
 	}
 
-	chainIdToChainType := make(map[string]BlockchainType)
+	defaultPolicy := ReconnectPolicy{
+		RetryInterval:    config.RetryInterval,
+		MaxRetryInterval: max(DefaultMaxRetryInterval, config.RetryInterval),
+		RedialThreshold:  DefaultRedialThreshold,
+	}
 
-	var err error
+	reader := &ChainReader{
+		Chains:             make(map[BlockchainType]*Chain),
+		ReorgDepth:         config.ReorgDepth,
+		BackfillBlockRange: config.BackfillBlockRange,
+		ChainIdToChainType: make(map[string]BlockchainType),
+	}
 
-	var ethClient *eth_client.EthClient
-	var ethChainId *big.Int
 	if config.EthWebsocketUrl != "" {
-		ethClient, err = eth_client.Dial(config.EthWebsocketUrl)
+		err := reader.addChain(Ethereum, ChainConfig{WebsocketUrl: config.EthWebsocketUrl}, dialEth, defaultPolicy)
 		if err != nil {
-			return nil, err
+			log.Error().Err(err).Str("Player", "ChainReader").Msg("failed to add ethereum chain, skipping")
 		}
+	}
 
-		ethChainId, err = ethClient.ChainID(context.Background())
+	if config.KaiaWebsocketUrl != "" {
+		err := reader.addChain(Kaia, ChainConfig{WebsocketUrl: config.KaiaWebsocketUrl}, dialKaia, defaultPolicy)
 		if err != nil {
-			return nil, err
+			log.Error().Err(err).Str("Player", "ChainReader").Msg("failed to add kaia chain, skipping")
 		}
-
-		chainIdToChainType[ethChainId.String()] = Ethereum
 	}
 
-	var kaiaClient *client.Client
-	var kaiaChainId *big.Int
-	if config.KaiaWebsocketUrl != "" {
-		kaiaClient, err = client.Dial(config.KaiaWebsocketUrl)
-		if err != nil {
-			return nil, err
+	nextChainType := firstGenericChainType
+	for _, chainConfig := range config.Chains {
+		if _, exists := reader.ChainType(chainConfig.ChainId); exists && chainConfig.ChainId != "" {
+			log.Warn().Str("Player", "ChainReader").Str("chainId", chainConfig.ChainId).Msg("chain already configured, skipping")
+			continue
 		}
 
-		kaiaChainId, err = kaiaClient.ChainID(context.Background())
+		dial := dialEth
+		if slices.Contains(KaiaChainIds, chainConfig.ChainId) {
+			dial = dialKaia
+		}
+
+		err := reader.addChain(nextChainType, chainConfig, dial, defaultPolicy)
 		if err != nil {
-			return nil, err
+			if !errors.Is(err, errorSentinel.ErrChainWebsocketDuplicateChain) {
+				log.Error().Err(err).Str("Player", "ChainReader").Str("chainId", chainConfig.ChainId).Msg("failed to add chain, skipping")
+			}
+			continue
+		}
+		nextChainType++
+	}
+
+	if len(reader.Chains) == 0 {
+		return nil, errorSentinel.ErrChainWebsocketChainNotFound
+	}
+	return reader, nil
+}
+
+// addChain registers the chain even when none of its urls can be dialed yet, subscriptions redial it following its
+// reconnect policy. Chains which can not be identified, neither by a configured chain id nor by their type, are skipped.
+func (c *ChainReader) addChain(chainType BlockchainType, config ChainConfig, dial func(url string) (utils.ClientInterface, error), defaultPolicy ReconnectPolicy) error {
+	policy := defaultPolicy
+	if config.Policy != nil {
+		policy = *config.Policy
+	}
+
+	chain := &Chain{
+		ChainId:      config.ChainId,
+		WebsocketUrl: config.WebsocketUrl,
+		Policy:       policy,
+		urls:         append([]string{config.WebsocketUrl}, config.FallbackUrls...),
+		dial:         dial,
+	}
+
+	websocketClient, chainId, err := chain.connect()
+	switch {
+	case err == nil:
+		chain.ChainId = chainId
+		chain.client = websocketClient
+	case errors.Is(err, errorSentinel.ErrChainWebsocketChainIdMismatch):
+		return err
+	case config.ChainId == "" && chainType >= firstGenericChainType:
+		return err
+	default:
+		log.Warn().Err(err).Str("Player", "ChainReader").Str("chainId", config.ChainId).Msg("failed to dial chain, redialing with its reconnect policy")
+	}
+
+	if chain.ChainId != "" && !c.registerChainId(chain.ChainId, chainType) {
+		if chain.client != nil {
+			chain.client.Close()
 		}
+		log.Warn().Str("Player", "ChainReader").Str("chainId", chain.ChainId).Msg("chain already configured, skipping")
+		return errorSentinel.ErrChainWebsocketDuplicateChain
+	}
+	c.Chains[chainType] = chain
+	return nil
+}
 
-		chainIdToChainType[kaiaChainId.String()] = Kaia
+// ChainType looks up the chain serving chainId, chains added without chain id are found once they were dialed
+func (c *ChainReader) ChainType(chainId string) (BlockchainType, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	chainType, exists := c.ChainIdToChainType[chainId]
+	return chainType, exists
+}
+
+// registerChainId maps chainId to chainType unless another chain serves it already
+func (c *ChainReader) registerChainId(chainId string, chainType BlockchainType) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if registered, exists := c.ChainIdToChainType[chainId]; exists {
+		return registered == chainType
 	}
+	c.ChainIdToChainType[chainId] = chainType
+	return true
+}
 
-	return &ChainReader{
-		EthClient:          ethClient,
-		KaiaClient:         kaiaClient,
-		RetryPeriod:        config.RetryInterval,
-		ChainIdToChainType: chainIdToChainType,
-	}, nil
+// redial dials the chain again, registering the chain id reported by the node for chains which were added without one
+func (c *ChainReader) redial(chainType BlockchainType) {
+	chainId := c.Chains[chainType].redial()
+	if chainId != "" && !c.registerChainId(chainId, chainType) {
+		log.Warn().Str("Player", "ChainReader").Str("chainId", chainId).Msg("chain id served by another chain, not registering it")
+	}
 }
 
 func (c *ChainReader) BlockNumber(ctx context.Context, chainType BlockchainType) (*big.Int, error) {
+	if _, exists := c.Chains[chainType]; !exists {
+		return nil, errorSentinel.ErrChainWebsocketChainNotFound
+	}
 	websocketClient := c.client(chainType)
+	if websocketClient == nil {
+		return nil, errorSentinel.ErrChainWebsocketNotConnected
+	}
 	return websocketClient.BlockNumber(ctx)
 }
 
+// Subscribe delivers logs of the address to the channel until ctx is done, resubscribing and backfilling missed
+// blocks on failure. Logs are delivered once per block hash, logs removed by a reorg are not delivered.
 func (c *ChainReader) Subscribe(ctx context.Context, opts ...SubscribeOption) error {
 	config := &SubscribeConfig{
 		ChainType: Ethereum,
@@ -93,17 +184,32 @@ func (c *ChainReader) Subscribe(ctx context.Context, opts ...SubscribeOption) er
 		return errorSentinel.ErrChainWebsocketChannelNotfound
 	}
 
+	if _, exists := c.Chains[config.ChainType]; !exists {
+		return errorSentinel.ErrChainWebsocketChainNotFound
+	}
+
 	go c.handleSubscription(ctx, config)
 	return nil
 }
 
 func (c *ChainReader) handleSubscription(ctx context.Context, config *SubscribeConfig) {
-	initialTrigger := true
+	state := &subscriptionState{seen: make(map[logKey]uint64)}
+	failures := 0
 	for {
-		blockNumber, err := c.getBlockNumber(ctx, config, initialTrigger)
+		websocketClient := c.client(config.ChainType)
+		if websocketClient == nil {
+			log.Warn().Str("Player", "ChainReader").Msg("chain not connected, retrying")
+			failures++
+			if !c.retry(ctx, config.ChainType, failures) {
+				return
+			}
+			continue
+		}
+		blockNumber, err := websocketClient.BlockNumber(ctx)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to get block number, retrying")
-			if !retryWithContext(ctx, c.RetryPeriod) {
+			failures++
+			if !c.retry(ctx, config.ChainType, failures) {
 				return
 			}
 			log.Debug().Err(err).Str("Player", "ChainReader").Msg("Retrying subscription")
@@ -115,44 +221,219 @@ func (c *ChainReader) handleSubscription(ctx context.Context, config *SubscribeC
 			Addresses: []common.Address{common.HexToAddress(config.Address)},
 		}
 
+		// subscribe before backfilling so that logs emitted meanwhile are not lost, duplicates are dropped by state
 		logs := make(chan types.Log)
-		sub, err := c.client(config.ChainType).SubscribeFilterLogs(ctx, query, logs)
+		sub, err := websocketClient.SubscribeFilterLogs(ctx, query, logs)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to subscribe, retrying")
-			if !retryWithContext(ctx, c.RetryPeriod) {
+			failures++
+			if !c.retry(ctx, config.ChainType, failures) {
 				return
 			}
 			log.Debug().Err(err).Str("Player", "ChainReader").Msg("Retrying subscription")
 			continue
 		}
-		defer sub.Unsubscribe()
 
-		if !processLogs(ctx, sub, logs, config.Ch) {
-			if !retryWithContext(ctx, c.RetryPeriod) {
+		fromBlock, shouldBackfill := c.backfillStart(config, state, blockNumber.Uint64())
+		if shouldBackfill {
+			err = c.backfill(ctx, websocketClient, config, state, query.Addresses, fromBlock, blockNumber.Uint64())
+			if err != nil {
+				sub.Unsubscribe()
+				log.Error().Err(err).Str("Player", "ChainReader").Msg("Failed to backfill logs, retrying")
+				failures++
+				if !c.retry(ctx, config.ChainType, failures) {
+					return
+				}
+				continue
+			}
+		}
+		failures = 0
+
+		ok := processLogs(ctx, sub, logs, config.Ch, state, c.ReorgDepth)
+		sub.Unsubscribe()
+		if !ok {
+			failures++
+			if !c.retry(ctx, config.ChainType, failures) {
 				return
 			}
-			log.Debug().Err(err).Str("Player", "ChainReader").Msg("Retrying subscription")
+			log.Debug().Str("Player", "ChainReader").Msg("Retrying subscription")
 			continue
 		}
+	}
+}
+
+// waits according to the chain's reconnect policy, redialing the client once consecutive failures reach the threshold
+// or on every retry while the chain has never been connected
+func (c *ChainReader) retry(ctx context.Context, chainType BlockchainType, failures int) bool {
+	chain := c.Chains[chainType]
+	if !retryWithContext(ctx, chain.Policy.backoff(failures)) {
+		return false
+	}
+
+	if chain.Client() == nil || (chain.Policy.RedialThreshold > 0 && failures%chain.Policy.RedialThreshold == 0) {
+		c.redial(chainType)
+	}
+	return true
+}
+
+// first subscription only backfills from the configured start block, resubscriptions re-read the reorg window and the gap
+func (c *ChainReader) backfillStart(config *SubscribeConfig, state *subscriptionState, head uint64) (uint64, bool) {
+	if !state.started {
+		state.started = true
+		if config.BlockNumber == nil {
+			state.lastBlockNumber = head
+			return 0, false
+		}
+		// keep the start block as the resume point until logs are delivered, in case this backfill fails
+		if config.BlockNumber.Sign() > 0 {
+			state.lastBlockNumber = config.BlockNumber.Uint64() - 1
+		}
+		return config.BlockNumber.Uint64(), true
+	}
 
-		initialTrigger = false
+	if state.lastBlockNumber > c.ReorgDepth {
+		return state.lastBlockNumber - c.ReorgDepth, true
 	}
+	return 0, true
 }
 
-func (c *ChainReader) getBlockNumber(ctx context.Context, config *SubscribeConfig, initialTrigger bool) (*big.Int, error) {
-	if initialTrigger && config.BlockNumber != nil {
-		return config.BlockNumber, nil
+// reads logs in [fromBlock, toBlock] and delivers the ones not yet seen, in order.
+// logs of reorged blocks carry a different block hash, so they are delivered again while canonical ones are skipped
+func (c *ChainReader) backfill(ctx context.Context, websocketClient utils.ClientInterface, config *SubscribeConfig, state *subscriptionState, addresses []common.Address, fromBlock uint64, toBlock uint64) error {
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	blockRange := c.BackfillBlockRange
+	if blockRange == 0 {
+		blockRange = DefaultBackfillBlockRange
+	}
+
+	log.Debug().Str("Player", "ChainReader").Str("address", config.Address).Uint64("from", fromBlock).Uint64("to", toBlock).Msg("backfilling logs")
+	for start := fromBlock; start <= toBlock; start += blockRange {
+		end := min(start+blockRange-1, toBlock)
+		backfilled, err := websocketClient.FilterLogs(ctx, klaytn.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: addresses,
+		})
+		if err != nil {
+			return err
+		}
+
+		sort.SliceStable(backfilled, func(i, j int) bool {
+			if backfilled[i].BlockNumber != backfilled[j].BlockNumber {
+				return backfilled[i].BlockNumber < backfilled[j].BlockNumber
+			}
+			return backfilled[i].Index < backfilled[j].Index
+		})
+
+		for _, vLog := range backfilled {
+			if !state.deliver(ctx, vLog, config.Ch, c.ReorgDepth) {
+				return ctx.Err()
+			}
+		}
 	}
 
-	return c.client(config.ChainType).BlockNumber(ctx)
+	// blocks up to toBlock are read, the next resubscription only re-reads the reorg window behind it
+	if toBlock > state.lastBlockNumber {
+		state.lastBlockNumber = toBlock
+		state.prune(c.ReorgDepth)
+	}
+	return nil
 }
 
 func (c *ChainReader) client(chainType BlockchainType) utils.ClientInterface {
-	if chainType == Ethereum {
-		return c.EthClient
+	chain, exists := c.Chains[chainType]
+	if !exists {
+		return nil
+	}
+	return chain.Client()
+}
+
+func (c *Chain) Client() utils.ClientInterface {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// redial replaces the client starting from the url after the current one, so a dead provider is left behind.
+// Returns the chain id reported by the node, empty when no url could be dialed
+func (c *Chain) redial() string {
+	c.dialMu.Lock()
+	defer c.dialMu.Unlock()
+
+	if c.Client() != nil {
+		c.urlIndex = (c.urlIndex + 1) % len(c.urls)
+	}
+	websocketClient, chainId, err := c.connect()
+	if err != nil {
+		log.Error().Err(err).Str("Player", "ChainReader").Str("chainId", c.ChainId).Msg("failed to redial websocket client")
+		return ""
+	}
+	// chains added without chain id learn it from the first successful dial, later dials verify it
+	c.ChainId = chainId
+	log.Info().Str("Player", "ChainReader").Str("chainId", c.ChainId).Str("url", c.urls[c.urlIndex]).Msg("websocket client redialed")
+
+	c.mu.Lock()
+	previous := c.client
+	c.client = websocketClient
+	c.mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return chainId
+}
+
+// connect dials the urls in order starting from the current one, verifying the chain id when it is known
+func (c *Chain) connect() (utils.ClientInterface, string, error) {
+	var lastErr error
+	for i := range c.urls {
+		index := (c.urlIndex + i) % len(c.urls)
+		websocketClient, err := c.dial(c.urls[index])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		chainId, err := utils.GetChainID(context.Background(), websocketClient)
+		if err != nil {
+			websocketClient.Close()
+			lastErr = err
+			continue
+		}
+
+		if c.ChainId != "" && c.ChainId != chainId.String() {
+			websocketClient.Close()
+			log.Error().Str("Player", "ChainReader").Str("expected", c.ChainId).Str("actual", chainId.String()).Str("url", c.urls[index]).Msg("chain id mismatch")
+			lastErr = errorSentinel.ErrChainWebsocketChainIdMismatch
+			continue
+		}
+
+		c.urlIndex = index
+		return websocketClient, chainId.String(), nil
+	}
+	return nil, "", lastErr
+}
+
+func (p ReconnectPolicy) backoff(failures int) time.Duration {
+	wait := p.RetryInterval
+	for i := 1; i < failures && wait < p.MaxRetryInterval; i++ {
+		wait *= 2
 	}
+	if p.MaxRetryInterval > 0 && wait > p.MaxRetryInterval {
+		return p.MaxRetryInterval
+	}
+	return wait
+}
 
-	return c.KaiaClient
+func dialKaia(url string) (utils.ClientInterface, error) {
+	return client.Dial(url)
+}
+
+func dialEth(url string) (utils.ClientInterface, error) {
+	return eth_client.Dial(url)
 }
 
 func (c *ChainReader) ReadContractOnce(ctx context.Context, chain BlockchainType, contractAddressHex string, functionString string, args ...interface{}) (interface{}, error) {
@@ -168,19 +449,56 @@ func retryWithContext(ctx context.Context, duration time.Duration) bool {
 	}
 }
 
-func processLogs(ctx context.Context, sub klaytn.Subscription, logs <-chan types.Log, ch chan<- types.Log) bool {
+func processLogs(ctx context.Context, sub klaytn.Subscription, logs <-chan types.Log, ch chan<- types.Log, state *subscriptionState, reorgDepth uint64) bool {
 	for {
 		select {
 		case err := <-sub.Err():
 			log.Warn().Err(err).Msg("Error in subscription")
 			return false
 		case vLog := <-logs:
-			select {
-			case ch <- vLog:
-				continue
-			case <-ctx.Done():
+			if !state.deliver(ctx, vLog, ch, reorgDepth) {
 				return false
 			}
 		}
 	}
 }
+
+// forwards the log unless it was already delivered, returns false only when the context is done.
+// Removed logs are never forwarded, consumers only see the replacing logs which arrive with the new block hash.
+func (s *subscriptionState) deliver(ctx context.Context, vLog types.Log, ch chan<- types.Log, reorgDepth uint64) bool {
+	key := logKey{BlockHash: vLog.BlockHash, TxHash: vLog.TxHash, Index: vLog.Index}
+	if vLog.Removed {
+		// reverted by a reorg, the replacing logs arrive with a new block hash
+		delete(s.seen, key)
+		return true
+	}
+
+	if _, exists := s.seen[key]; exists {
+		return true
+	}
+
+	select {
+	case ch <- vLog:
+	case <-ctx.Done():
+		return false
+	}
+
+	s.seen[key] = vLog.BlockNumber
+	if vLog.BlockNumber > s.lastBlockNumber {
+		s.lastBlockNumber = vLog.BlockNumber
+		s.prune(reorgDepth)
+	}
+	return true
+}
+
+func (s *subscriptionState) prune(reorgDepth uint64) {
+	if s.lastBlockNumber <= reorgDepth {
+		return
+	}
+	threshold := s.lastBlockNumber - reorgDepth
+	for key, blockNumber := range s.seen {
+		if blockNumber < threshold {
+			delete(s.seen, key)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+//nolint:all
+package websocketchainreader
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/utils"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSubscription struct {
+	errCh chan error
+	logs  chan<- types.Log
+}
+
+func (s *mockSubscription) Unsubscribe()      {}
+func (s *mockSubscription) Err() <-chan error { return s.errCh }
+
+// mockClient serves FilterLogs from a canned chain and hands every subscription to the test
+type mockClient struct {
+	mu            sync.Mutex
+	head          uint64
+	chain         []types.Log
+	subscriptions chan *mockSubscription
+	networkId     int64
+}
+
+func (m *mockClient) setChain(head uint64, chain []types.Log) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.head = head
+	m.chain = chain
+}
+
+func (m *mockClient) BlockNumber(ctx context.Context) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return new(big.Int).SetUint64(m.head), nil
+}
+
+func (m *mockClient) FilterLogs(ctx context.Context, q klaytn.FilterQuery) ([]types.Log, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []types.Log{}
+	for _, vLog := range m.chain {
+		if vLog.BlockNumber >= q.FromBlock.Uint64() && vLog.BlockNumber <= q.ToBlock.Uint64() {
+			result = append(result, vLog)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockClient) SubscribeFilterLogs(ctx context.Context, q klaytn.FilterQuery, ch chan<- types.Log) (klaytn.Subscription, error) {
+	sub := &mockSubscription{errCh: make(chan error, 1), logs: ch}
+	m.subscriptions <- sub
+	return sub, nil
+}
+
+func (m *mockClient) Close() {}
+func (m *mockClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, nil
+}
+func (m *mockClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) { return nil, nil }
+func (m *mockClient) EstimateGas(ctx context.Context, call klaytn.CallMsg) (uint64, error) {
+	return 0, nil
+}
+func (m *mockClient) SendTransaction(ctx context.Context, tx *types.Transaction) error { return nil }
+func (m *mockClient) CallContract(ctx context.Context, call klaytn.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+func (m *mockClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	if m.networkId == 0 {
+		return big.NewInt(1), nil
+	}
+	return big.NewInt(m.networkId), nil
+}
+func (m *mockClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+func (m *mockClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, nil
+}
+func (m *mockClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (klaytn.Subscription, error) {
+	return nil, nil
+}
+
+func testLog(blockNumber uint64, blockHash string, txHash string) types.Log {
+	return types.Log{
+		BlockNumber: blockNumber,
+		BlockHash:   common.HexToHash(blockHash),
+		TxHash:      common.HexToHash(txHash),
+	}
+}
+
+func receiveLogs(t *testing.T, ch <-chan types.Log, count int) []types.Log {
+	result := []types.Log{}
+	for len(result) < count {
+		select {
+		case vLog := <-ch:
+			result = append(result, vLog)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d of %d logs", len(result), count)
+		}
+	}
+	return result
+}
+
+func TestSubscribeBackfill(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logAt10 := testLog(10, "0xa10", "0x1")
+	logAt15 := testLog(15, "0xa15", "0x2")
+	logAt20 := testLog(20, "0xa20", "0x3")
+	logAt21 := testLog(21, "0xa21", "0x4")
+	logAt25 := testLog(25, "0xa25", "0x5")
+	// block 20 reorged, the same tx mined in a block with another hash
+	reorgedLogAt20 := testLog(20, "0xb20", "0x3")
+
+	mock := &mockClient{subscriptions: make(chan *mockSubscription, 10)}
+	mock.setChain(20, []types.Log{logAt10, logAt15, logAt20})
+
+	reader := &ChainReader{
+		Chains: map[BlockchainType]*Chain{Kaia: {
+			Policy: ReconnectPolicy{RetryInterval: time.Millisecond, MaxRetryInterval: time.Millisecond},
+			client: mock,
+		}},
+		ReorgDepth:         2,
+		BackfillBlockRange: 4,
+	}
+
+	ch := make(chan types.Log)
+	err := reader.Subscribe(ctx, WithAddress("0x1"), WithChainType(Kaia), WithChannel(ch), WithStartBlockNumber(big.NewInt(10)))
+	assert.NoError(t, err)
+
+	sub := <-mock.subscriptions
+	assert.Equal(t, []types.Log{logAt10, logAt15, logAt20}, receiveLogs(t, ch, 3))
+
+	// live log, then the subscription drops while blocks 22 to 30 are mined and block 20 is reorged
+	sub.logs <- logAt21
+	assert.Equal(t, []types.Log{logAt21}, receiveLogs(t, ch, 1))
+	mock.setChain(30, []types.Log{logAt10, logAt15, reorgedLogAt20, logAt21, logAt25})
+	sub.errCh <- errors.New("connection lost")
+
+	// the reorg window and the gap are read again, logs already delivered are skipped
+	sub = <-mock.subscriptions
+	assert.Equal(t, []types.Log{reorgedLogAt20, logAt25}, receiveLogs(t, ch, 2))
+
+	// a removed log is not forwarded, a live log sent twice is delivered once
+	removed := logAt25
+	removed.Removed = true
+	logAt30 := testLog(30, "0xa30", "0x6")
+	logAt31 := testLog(31, "0xa31", "0x7")
+	go func() {
+		for _, vLog := range []types.Log{removed, logAt30, logAt30, logAt31} {
+			sub.logs <- vLog
+		}
+	}()
+	assert.Equal(t, []types.Log{logAt30, logAt31}, receiveLogs(t, ch, 2))
+}
+
+func TestBackfillAdvancesLastBlockNumber(t *testing.T) {
+	ctx := context.Background()
+	mock := &mockClient{}
+	mock.setChain(50, nil)
+
+	reader := &ChainReader{ReorgDepth: 2, BackfillBlockRange: 10}
+	state := &subscriptionState{started: true, lastBlockNumber: 20, seen: map[logKey]uint64{}}
+	config := &SubscribeConfig{Address: "0x1", Ch: make(chan types.Log)}
+
+	err := reader.backfill(ctx, mock, config, state, nil, 18, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50), state.lastBlockNumber)
+
+	// without logs in the gap the next resubscription starts from the reorg window below the backfilled head
+	fromBlock, shouldBackfill := reader.backfillStart(config, state, 60)
+	assert.True(t, shouldBackfill)
+	assert.Equal(t, uint64(48), fromBlock)
+}
+
+func TestAddChainWithFailingDial(t *testing.T) {
+	dialed := []string{}
+	up := map[string]bool{"wss://fallback.example": true}
+	networkIds := map[string]int64{"wss://down.example": 2}
+	dial := func(url string) (utils.ClientInterface, error) {
+		dialed = append(dialed, url)
+		if !up[url] {
+			return nil, errors.New("connection refused")
+		}
+		return &mockClient{networkId: networkIds[url]}, nil
+	}
+
+	reader := &ChainReader{Chains: map[BlockchainType]*Chain{}, ChainIdToChainType: map[string]BlockchainType{}}
+	policy := ReconnectPolicy{RetryInterval: time.Millisecond, MaxRetryInterval: time.Millisecond, RedialThreshold: 1}
+
+	// the fallback url is dialed when the primary one is down
+	err := reader.addChain(firstGenericChainType, ChainConfig{ChainId: "1", WebsocketUrl: "wss://primary.example", FallbackUrls: []string{"wss://fallback.example"}}, dial, policy)
+	assert.NoError(t, err)
+	assert.NotNil(t, reader.Chains[firstGenericChainType].Client())
+	assert.Equal(t, []string{"wss://primary.example", "wss://fallback.example"}, dialed)
+
+	// a chain which can not be dialed yet is kept and connected by its reconnect policy
+	err = reader.addChain(firstGenericChainType+1, ChainConfig{ChainId: "2", WebsocketUrl: "wss://down.example"}, dial, policy)
+	assert.NoError(t, err)
+	chain := reader.Chains[firstGenericChainType+1]
+	assert.Nil(t, chain.Client())
+	assert.Equal(t, firstGenericChainType+1, reader.ChainIdToChainType["2"])
+	_, err = reader.BlockNumber(context.Background(), firstGenericChainType+1)
+	assert.ErrorIs(t, err, errorSentinel.ErrChainWebsocketNotConnected)
+
+	up["wss://down.example"] = true
+	assert.True(t, reader.retry(context.Background(), firstGenericChainType+1, 1))
+	assert.NotNil(t, chain.Client())
+
+	// nothing identifies a generic chain without chain id which can not be dialed
+	err = reader.addChain(firstGenericChainType+2, ChainConfig{WebsocketUrl: "wss://unknown.example"}, dial, policy)
+	assert.Error(t, err)
+	assert.NotContains(t, reader.Chains, firstGenericChainType+2)
+
+	// chain ids reported by the node have to match the configured one
+	err = reader.addChain(firstGenericChainType+2, ChainConfig{ChainId: "3", WebsocketUrl: "wss://fallback.example"}, dial, policy)
+	assert.ErrorIs(t, err, errorSentinel.ErrChainWebsocketChainIdMismatch)
+}
+
+func TestRedialRotatesUrls(t *testing.T) {
+	dialed := []string{}
+	dial := func(url string) (utils.ClientInterface, error) {
+		dialed = append(dialed, url)
+		return &mockClient{}, nil
+	}
+
+	chain := &Chain{ChainId: "1", urls: []string{"wss://a.example", "wss://b.example"}, dial: dial}
+	chain.redial()
+	assert.Equal(t, []string{"wss://a.example"}, dialed)
+	chain.redial()
+	chain.redial()
+	assert.Equal(t, []string{"wss://a.example", "wss://b.example", "wss://a.example"}, dialed)
+}
+
+func TestRedialRegistersChainIdOfLegacyChain(t *testing.T) {
+	up := false
+	dial := func(url string) (utils.ClientInterface, error) {
+		if !up {
+			return nil, errors.New("connection refused")
+		}
+		return &mockClient{}, nil
+	}
+
+	reader := &ChainReader{Chains: map[BlockchainType]*Chain{}, ChainIdToChainType: map[string]BlockchainType{}}
+	policy := ReconnectPolicy{RetryInterval: time.Millisecond, MaxRetryInterval: time.Millisecond, RedialThreshold: 1}
+
+	// legacy ETH_WEBSOCKET_URL chains carry no chain id, it is only known once the node answers
+	err := reader.addChain(Ethereum, ChainConfig{WebsocketUrl: "wss://eth.example"}, dial, policy)
+	assert.NoError(t, err)
+	_, exists := reader.ChainType("1")
+	assert.False(t, exists)
+
+	assert.True(t, reader.retry(context.Background(), Ethereum, 1))
+	_, exists = reader.ChainType("1")
+	assert.False(t, exists)
+
+	up = true
+	assert.True(t, reader.retry(context.Background(), Ethereum, 2))
+	chainType, exists := reader.ChainType("1")
+	assert.True(t, exists)
+	assert.Equal(t, Ethereum, chainType)
+	assert.Equal(t, "1", reader.Chains[Ethereum].ChainId)
+	assert.NotNil(t, reader.Chains[Ethereum].Client())
+}
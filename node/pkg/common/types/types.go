@@ -3,10 +3,26 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 )
 
+// DefaultDecimals is the precision of configs which do not set their own
+const DefaultDecimals = 8
+
+// MaxDecimals matches the 18 decimals of on-chain answers, feed values are exact at any precision
+// and local aggregates out of the int64 range are dropped by the local aggregator
+const MaxDecimals = 18
+
+// DecimalsOrDefault treats unset decimals, e.g. from nodes not reporting them yet, as DefaultDecimals
+func DecimalsOrDefault(decimals int32) int32 {
+	if decimals <= 0 {
+		return DefaultDecimals
+	}
+	return decimals
+}
+
 type Proxy struct {
 	ID       int64   `db:"id"`
 	Protocol string  `db:"protocol"`
@@ -27,16 +43,20 @@ type Feed struct {
 }
 
 type FeedData struct {
-	FeedID    int32      `db:"feed_id"`
-	Value     float64    `db:"value"`
+	FeedID int32 `db:"feed_id"`
+	// price scaled by the decimals of the feed's config, kept exact from the source to the local aggregate
+	Value     *big.Int   `db:"value"`
 	Volume    float64    `db:"volume"`
 	Timestamp *time.Time `db:"timestamp"`
+	// relative bid-ask spread, only set for order book sources
+	Spread *float64 `db:"spread"`
 }
 
 type LocalAggregate struct {
 	ConfigID  int32     `db:"config_id" json:"configId"`
 	Value     int64     `db:"value" json:"value"`
 	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	Decimals  int32     `db:"-" json:"decimals"`
 }
 
 type GlobalAggregate struct {
@@ -44,6 +64,9 @@ type GlobalAggregate struct {
 	Value     int64     `db:"value" json:"value"`
 	Round     int32     `db:"round" json:"round"`
 	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	// fx configs outside of trading hours, the value is the last close
+	MarketClosed bool  `db:"-" json:"marketClosed"`
+	Decimals     int32 `db:"-" json:"decimals"`
 }
 
 type Proof struct {
@@ -58,11 +81,19 @@ type Config struct {
 	FetchInterval     int    `db:"fetch_interval" json:"fetchInterval"`
 	AggregateInterval int    `db:"aggregate_interval" json:"aggregateInterval"`
 	SubmitInterval    int    `db:"submit_interval" json:"submitInterval"`
+	Decimals          int32  `db:"decimals" json:"decimals"`
+}
+
+// FeedDataValidator screens incoming feed data before it is stored, returning the accepted entries.
+// It is called with the map locked and may read latest but must not modify it.
+type FeedDataValidator interface {
+	Validate(feedData []*FeedData, latest map[int32]*FeedData) []*FeedData
 }
 
 type LatestFeedDataMap struct {
 	FeedDataMap map[int32]*FeedData
 	Mu          sync.RWMutex
+	Validator   FeedDataValidator
 }
 
 func (m *LatestFeedDataMap) GetLatestFeedData(feedIds []int32) ([]*FeedData, error) {
@@ -85,6 +116,9 @@ func (m *LatestFeedDataMap) SetLatestFeedData(feedData []*FeedData) error {
 
 	m.Mu.Lock()
 	defer m.Mu.Unlock()
+	if m.Validator != nil {
+		feedData = m.Validator.Validate(feedData, m.FeedDataMap)
+	}
 	for _, data := range feedData {
 		if data == nil {
 			continue
@@ -23,9 +23,8 @@ import (
 )
 
 const (
-	DefaultDecimals = "8"
-	GetAllOracles   = "getAllOracles() public view returns (address[] memory)"
-	OracleAdded     = "OracleAdded(address oracle, uint256 expirationTime)"
+	GetAllOracles = "getAllOracles() public view returns (address[] memory)"
+	OracleAdded   = "OracleAdded(address oracle, uint256 expirationTime)"
 )
 
 type Config = types.Config
@@ -245,12 +244,14 @@ func (c *Collector) IncomingDataToOutgoingData(ctx context.Context, data *aggreg
 		return nil, errorsentinel.ErrDalFeedHashNotFound
 	}
 
+	decimals := types.DecimalsOrDefault(data.GlobalAggregate.Decimals)
 	orderedProof, err := orderProof(
 		ctx,
 		data.Proof.Proof,
 		data.GlobalAggregate.Value,
 		data.GlobalAggregate.Timestamp,
 		data.Symbol,
+		decimals,
 		whitelist)
 	if err != nil {
 		log.Error().Err(err).Str("Player", "DalCollector").Str("Symbol", data.Symbol).Msg("failed to order proof")
@@ -274,7 +275,8 @@ func (c *Collector) IncomingDataToOutgoingData(ctx context.Context, data *aggreg
 		AggregateTime: strconv.FormatInt(data.GlobalAggregate.Timestamp.UnixMilli(), 10),
 		Proof:         formatBytesToHex(orderedProof),
 		FeedHash:      formatBytesToHex(feedHashBytes),
-		Decimals:      DefaultDecimals,
+		Decimals:      strconv.Itoa(int(decimals)),
+		MarketClosed:  data.GlobalAggregate.MarketClosed,
 	}, nil
 }
 
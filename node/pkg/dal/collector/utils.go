@@ -78,14 +78,14 @@ func subscribeAddOracleEvent(ctx context.Context, chainReader *websocketchainrea
 	return nil
 }
 
-func orderProof(ctx context.Context, proof []byte, value int64, timestamp time.Time, symbol string, cachedWhitelist []klaytncommon.Address) ([]byte, error) {
+func orderProof(ctx context.Context, proof []byte, value int64, timestamp time.Time, symbol string, decimals int32, cachedWhitelist []klaytncommon.Address) ([]byte, error) {
 	proofChunks, err := getUniqueProofChunks(proof)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to remove duplicate proofs in orderProof")
 		return nil, err
 	}
 
-	hash := chainutils.Value2HashForSign(value, timestamp.UnixMilli(), symbol)
+	hash := chainutils.Value2HashForSignWithDecimals(value, timestamp.UnixMilli(), symbol, decimals)
 
 	signers, err := getSignerListFromProofs(hash, proofChunks)
 	if err != nil {
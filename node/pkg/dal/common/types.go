@@ -7,4 +7,6 @@ type OutgoingSubmissionData struct {
 	Proof         string `json:"proof"`
 	FeedHash      string `json:"feedHash"`
 	Decimals      string `json:"decimals"`
+	// fx configs outside of trading hours publish their last close
+	MarketClosed bool `json:"marketClosed,omitempty"`
 }
@@ -83,6 +83,7 @@ var (
 	ErrAdminDbPoolNotFound     = &CustomError{Service: Admin, Code: InternalError, Message: "db pool not found"}
 	ErrAdminRedisConnNotFound  = &CustomError{Service: Admin, Code: InternalError, Message: "redisconn not found"}
 	ErrAdminMessageBusNotFound = &CustomError{Service: Admin, Code: InternalError, Message: "messagebus not found"}
+	ErrAdminInvalidDecimals    = &CustomError{Service: Admin, Code: InvalidInputError, Message: "decimals exceed the supported maximum"}
 
 	ErrAggregatorInvalidInitValue         = &CustomError{Service: Aggregator, Code: InvalidInputError, Message: "Invalid init value parameters"}
 	ErrAggregatorUnhandledCustomMessage   = &CustomError{Service: Aggregator, Code: UnknownCaseError, Message: "Unhandled custom message"}
@@ -104,6 +105,8 @@ var (
 	ErrBusUnknownCommand   = &CustomError{Service: Others, Code: InvalidBusMessageError, Message: "Unknown command"}
 
 	ErrChainTransactionFail                  = &CustomError{Service: Others, Code: InternalError, Message: "transaction failed"}
+	ErrChainTransactionDropped               = &CustomError{Service: Others, Code: InternalError, Message: "transaction dropped"}
+	ErrChainTransactionOutOfGas              = &CustomError{Service: Others, Code: InternalError, Message: "transaction ran out of gas"}
 	ErrChainEmptyNameParam                   = &CustomError{Service: Others, Code: InvalidInputError, Message: "empty name param"}
 	ErrChainFailedToFindMethodSignatureMatch = &CustomError{Service: Others, Code: InternalError, Message: "failed to find method signature match"}
 	ErrChainInvalidSignatureLength           = &CustomError{Service: Others, Code: InvalidInputError, Message: "invalid signature length"}
@@ -125,6 +128,10 @@ var (
 	ErrChainWebsocketChannelNotfound         = &CustomError{Service: Others, Code: InvalidInputError, Message: "websocket channel not found"}
 	ErrChainEmptyEventNameStringParam        = &CustomError{Service: Others, Code: InvalidInputError, Message: "empty event name string param"}
 	ErrChainWebsocketUrlNotProvided          = &CustomError{Service: Others, Code: InvalidInputError, Message: "websocket url not provided"}
+	ErrChainWebsocketChainNotFound           = &CustomError{Service: Others, Code: InvalidInputError, Message: "websocket chain not configured"}
+	ErrChainWebsocketChainIdMismatch         = &CustomError{Service: Others, Code: InvalidInputError, Message: "websocket chain id mismatch"}
+	ErrChainWebsocketDuplicateChain          = &CustomError{Service: Others, Code: InvalidInputError, Message: "websocket chain already configured"}
+	ErrChainWebsocketNotConnected            = &CustomError{Service: Others, Code: InternalError, Message: "websocket chain not connected"}
 	ErrChainSubmissionProxyContractNotFound  = &CustomError{Service: Others, Code: InvalidInputError, Message: "submission proxy contract not found"}
 	ErrChainFailedToParseContractResult      = &CustomError{Service: Others, Code: InvalidInputError, Message: "failed to parse contract result"}
 	ErrChainCachedAbiNotFound                = &CustomError{Service: Others, Code: InvalidInputError, Message: "cached abi not found"}
@@ -153,9 +160,19 @@ var (
 	ErrFeedDataBulkWriterCancelNotFound       = &CustomError{Service: Fetcher, Code: InternalError, Message: "FeedDataBulkWriter cancel function not found"}
 	ErrLocalAggregateBulkWriterCancelNotFound = &CustomError{Service: Fetcher, Code: InternalError, Message: "LocalAggregateBulkWriter cancel function not found"}
 	ErrFetcherNoMatchingChainID               = &CustomError{Service: Fetcher, Code: InvalidInputError, Message: "No matching chain ID"}
+	ErrFetcherStaleOnchainData                = &CustomError{Service: Fetcher, Code: InternalError, Message: "Onchain data is stale"}
+	ErrFetcherInvalidOnchainAnswer            = &CustomError{Service: Fetcher, Code: InternalError, Message: "Invalid onchain answer"}
+	ErrFetcherInvalidGenericProviderConfig    = &CustomError{Service: Fetcher, Code: InvalidInputError, Message: "Invalid generic websocket provider config"}
+	ErrFetcherEmptyOrderBook                  = &CustomError{Service: Fetcher, Code: InternalError, Message: "Order book is empty"}
+	ErrFetcherInsufficientBookDepth           = &CustomError{Service: Fetcher, Code: InternalError, Message: "Insufficient order book depth for notional"}
+	ErrFetcherSubscriptionNotSupported        = &CustomError{Service: Fetcher, Code: InternalError, Message: "Incremental subscription not supported"}
 	ErrFetcherFailedToGetDexResultSlice       = &CustomError{Service: Fetcher, Code: InternalError, Message: "Failed to get dex result slice"}
 	ErrFetcherFailedBigIntConvert             = &CustomError{Service: Fetcher, Code: InternalError, Message: "Failed to convert to fetched data to big.Int"}
 	ErrFetcherFeedNotFound                    = &CustomError{Service: Fetcher, Code: InvalidInputError, Message: "Feed not found"}
+	ErrFetcherInsufficientDexLiquidity        = &CustomError{Service: Fetcher, Code: InvalidInputError, Message: "Insufficient dex pool liquidity"}
+	ErrFetcherAggregateOutOfRange             = &CustomError{Service: Fetcher, Code: InternalError, Message: "Aggregate exceeds the exactly representable range"}
+	ErrFetcherInvalidTwapObservation          = &CustomError{Service: Fetcher, Code: InternalError, Message: "Invalid twap observation"}
+	ErrFetcherInvalidRequestBody              = &CustomError{Service: Fetcher, Code: InvalidInputError, Message: "Invalid request body in definition"}
 
 	ErrLibP2pEmptyNonLocalAddress = &CustomError{Service: Others, Code: InternalError, Message: "Host has no non-local addresses"}
 	ErrLibP2pAddressSplitFail     = &CustomError{Service: Others, Code: InternalError, Message: "Failed to split address"}
@@ -202,6 +219,10 @@ var (
 	ErrReporterValidateAggregateTimestampValues = &CustomError{Service: Reporter, Code: InternalError, Message: "Failed to validate aggregate timestamp values"}
 	ErrReporterDalApiKeyNotFound                = &CustomError{Service: Reporter, Code: InternalError, Message: "DAL API key not found in reporter"}
 	ErrReporterDalWsDataProcessingFailed        = &CustomError{Service: Reporter, Code: InternalError, Message: "Failed to process DAL WS data"}
+	ErrReporterInvalidTargetConfig              = &CustomError{Service: Reporter, Code: InvalidInputError, Message: "Invalid reporter target config"}
+	ErrReporterNoTargetsSet                     = &CustomError{Service: Reporter, Code: InternalError, Message: "No reporter targets set"}
+	ErrReporterProofBelowThreshold              = &CustomError{Service: Reporter, Code: InternalError, Message: "Valid proof signers below threshold"}
+	ErrReporterProofSignersNotAscending         = &CustomError{Service: Reporter, Code: InternalError, Message: "Proof signers not in ascending oracle order"}
 
 	ErrDalEmptyProofParam      = &CustomError{Service: Dal, Code: InvalidInputError, Message: "Empty proof param"}
 	ErrDalInvalidProofLength   = &CustomError{Service: Dal, Code: InvalidInputError, Message: "Invalid proof length"}
@@ -216,6 +237,7 @@ var (
 	ErrDalChainEnvNotFound     = &CustomError{Service: Dal, Code: InternalError, Message: "Chain env not found"}
 
 	ErrReducerCastToFloatFail          = &CustomError{Service: Others, Code: InternalError, Message: "Failed to cast to float"}
+	ErrReducerDecimalsMismatch         = &CustomError{Service: Others, Code: InvalidInputError, Message: "POW10 reducers do not scale into the expected decimals"}
 	ErrReducerIndexCastToInterfaceFail = &CustomError{Service: Others, Code: InternalError, Message: "Failed to cast to interface from INDEX"}
 	ErrReducerParseCastToInterfaceFail = &CustomError{Service: Others, Code: InternalError, Message: "Failed to cast to interface from PARSE"}
 	ErrReducerParseCastToStringFail    = &CustomError{Service: Others, Code: InternalError, Message: "Failed to cast to string from PARSE"}
@@ -227,14 +249,24 @@ var (
 	ErrReducerUnknownReducerFunc       = &CustomError{Service: Others, Code: InternalError, Message: "Unknown reducer function"}
 	ErrRequestStatusNotOk              = &CustomError{Service: Others, Code: InternalError, Message: "Request status not OK"}
 	ErrRequestInvalidMethod            = &CustomError{Service: Others, Code: InvalidInputError, Message: "Invalid method"}
+	ErrRequestInvalidAuth              = &CustomError{Service: Others, Code: InvalidInputError, Message: "Invalid request auth"}
+	ErrRequestAuthSecretNotFound       = &CustomError{Service: Others, Code: InternalError, Message: "Request auth secret not found"}
+	ErrRequestAuthTokenNotFound        = &CustomError{Service: Others, Code: InternalError, Message: "Access token not found in token response"}
 	ErrCalculatorEmptyArr              = &CustomError{Service: Others, Code: InternalError, Message: "Empty array"}
 	ErrReducerIndexOutOfBounds         = &CustomError{Service: Others, Code: InvalidInputError, Message: "Index out of bounds"}
+	ErrReducerDivFromDivisionByZero    = &CustomError{Service: Others, Code: InternalError, Message: "Division by zero from DIVFROM"}
+	ErrDecimalInvalidValue             = &CustomError{Service: Others, Code: InvalidInputError, Message: "Invalid decimal value"}
 
 	ErrLogTimestampNotExist = &CustomError{Service: Others, Code: InvalidInputError, Message: "Log timestamp not exist"}
 	ErrLogMsgNotExist       = &CustomError{Service: Others, Code: InvalidInputError, Message: "Log message not exist"}
 	ErrLogLvlNotExist       = &CustomError{Service: Others, Code: InvalidInputError, Message: "Log level not exist"}
 	ErrLogEmptyLogByte      = &CustomError{Service: Others, Code: InvalidInputError, Message: "Empty log byte"}
 
+	ErrBacktestConfigNotFound   = &CustomError{Service: Others, Code: InvalidInputError, Message: "Backtest config not found"}
+	ErrBacktestNoFeedData       = &CustomError{Service: Others, Code: InvalidInputError, Message: "No feed data in backtest range"}
+	ErrBacktestInvalidCsv       = &CustomError{Service: Others, Code: InvalidInputError, Message: "Invalid backtest csv"}
+	ErrBacktestUnknownAlgorithm = &CustomError{Service: Others, Code: InvalidInputError, Message: "Unknown backtest algorithm"}
+
 	ErrLogscribeDbPoolNotFound            = &CustomError{Service: Logscribe, Code: InternalError, Message: "db pool not found"}
 	ErrLogscribeInsertFailed              = &CustomError{Service: Logscribe, Code: InternalError, Message: "Failed to insert logs to Logscribe"}
 	ErrLogscribeServiceNotExist           = &CustomError{Service: Logscribe, Code: InvalidInputError, Message: "Service field not found in log entry"}
@@ -16,15 +16,18 @@ import (
 )
 
 func New(bus *bus.MessageBus) *App {
+	quarantine := NewQuarantine(DefaultQuarantineMaxDeviation, DefaultQuarantineConfirmTicks)
 	return &App{
 		Fetchers:         make(map[int32]*Fetcher, 0),
 		WebsocketFetcher: websocketfetcher.New(),
 		LatestFeedDataMap: &LatestFeedDataMap{
 			FeedDataMap: make(map[int32]*FeedData),
 			Mu:          sync.RWMutex{},
+			Validator:   quarantine,
 		},
 		FeedDataDumpChannel: make(chan *FeedData, DefaultFeedDataDumpChannelSize),
 		Bus:                 bus,
+		Quarantine:          quarantine,
 	}
 }
 
@@ -35,6 +38,7 @@ func (a *App) Run(ctx context.Context) error {
 	}
 
 	a.subscribe(ctx)
+	go a.Quarantine.Run(ctx, DefaultQuarantineLogInterval)
 
 	return a.startAll(ctx)
 }
@@ -93,7 +97,8 @@ func (a *App) handleMessage(ctx context.Context, msg bus.Message) {
 		}
 		msg.Response <- bus.MessageResponse{Success: true}
 	case bus.REFRESH_FETCHER_APP:
-		err := a.stopAll(ctx)
+		// websocket streams stay connected, the websocket fetcher applies feed changes on refresh
+		err := a.stopAllExceptWebsocketFetcher(ctx)
 		if err != nil {
 			log.Error().Err(err).Str("Player", "Fetcher").Msg("failed to stop all fetchers")
 			bus.HandleMessageError(err, msg, "failed to stop all fetchers")
@@ -114,6 +119,22 @@ func (a *App) handleMessage(ctx context.Context, msg bus.Message) {
 
 		log.Debug().Str("Player", "Fetcher").Msg("refreshing fetcher")
 		msg.Response <- bus.MessageResponse{Success: true}
+	case bus.GET_WEBSOCKET_HEALTH:
+		msg.Response <- bus.MessageResponse{Success: true, Args: map[string]any{"providers": a.WebsocketFetcher.Health()}}
+	case bus.DRY_RUN_FEED:
+		rawDefinition, err := bus.ParseStringMsgParam(msg, "definition")
+		if err != nil {
+			log.Error().Err(err).Str("Player", "Fetcher").Msg("failed to parse definition")
+			bus.HandleMessageError(err, msg, "failed to parse definition")
+			return
+		}
+		value, err := dryRun(ctx, rawDefinition)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "Fetcher").Msg("failed to dry run feed")
+			bus.HandleMessageError(err, msg, "failed to dry run feed")
+			return
+		}
+		msg.Response <- bus.MessageResponse{Success: true, Args: map[string]any{"value": value}}
 	}
 }
 
@@ -138,6 +159,10 @@ func (a *App) startAll(ctx context.Context) error {
 func (a *App) stopAll(ctx context.Context) error {
 	a.WebsocketFetcher.Stop()
 
+	return a.stopAllExceptWebsocketFetcher(ctx)
+}
+
+func (a *App) stopAllExceptWebsocketFetcher(ctx context.Context) error {
 	err := a.stopAllFetchers(ctx)
 	if err != nil {
 		return err
@@ -345,11 +370,16 @@ func (a *App) initialize(ctx context.Context) error {
 		return err
 	}
 
+	for _, chainHelper := range a.ChainHelpers {
+		chainHelper.Close()
+	}
+
 	a.Fetchers = make(map[int32]*Fetcher, len(configs))
 	a.LocalAggregators = make(map[int32]*LocalAggregator, len(configs))
 	a.LocalAggregateBulkWriter = NewLocalAggregateBulkWriter(DefaultLocalAggregateInterval)
 	a.LocalAggregateBulkWriter.localAggregatesChannel = make(chan *LocalAggregate, LocalAggregatesChannelSize)
 
+	configFeeds := make(map[int32][]int32, len(configs))
 	for _, config := range configs {
 		// for fetcher it'll get fetcherFeeds without websocket fetcherFeeds
 		fetcherFeeds, getFeedsErr := a.getFeedsWithoutWss(ctx, config.ID)
@@ -367,6 +397,20 @@ func (a *App) initialize(ctx context.Context) error {
 			return getFeedsErr
 		}
 		a.LocalAggregators[config.ID] = NewLocalAggregator(config, localAggregatorFeeds, a.LocalAggregateBulkWriter.localAggregatesChannel, a.Bus, a.LatestFeedDataMap)
+		a.LocalAggregators[config.ID].feedHealth = a.WebsocketFetcher
+		for _, feed := range localAggregatorFeeds {
+			configFeeds[config.ID] = append(configFeeds[config.ID], feed.ID)
+		}
+	}
+	a.Quarantine.SetFeeds(configFeeds)
+
+	onchainFeeds := []Feed{}
+	for _, fetcher := range a.Fetchers {
+		onchainFeeds = append(onchainFeeds, fetcher.Feeds...)
+	}
+	a.ChainHelpers = loadChainHelpers(ctx, onchainFeeds)
+	for _, fetcher := range a.Fetchers {
+		fetcher.chainHelpers = a.ChainHelpers
 	}
 	feedDataDumpIntervalRaw := os.Getenv("FEED_DATA_STREAM_INTERVAL")
 	dumpInterval, err := time.ParseDuration(feedDataDumpIntervalRaw)
@@ -381,6 +425,10 @@ func (a *App) initialize(ctx context.Context) error {
 	}
 	a.Proxies = proxies
 
+	if a.WebsocketFetcher.IsInitialized() {
+		return a.WebsocketFetcher.Refresh(ctx)
+	}
+
 	err = a.WebsocketFetcher.Init(ctx, websocketfetcher.WithLatestFeedDataMap(a.LatestFeedDataMap), websocketfetcher.WithFeedDataDumpChannel(a.FeedDataDumpChannel))
 	if err != nil {
 		return err
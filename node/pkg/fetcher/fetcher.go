@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"time"
 
+	"bisonai.com/miko/node/pkg/common/types"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/utils/reducer"
 	"bisonai.com/miko/node/pkg/utils/request"
@@ -22,6 +24,7 @@ func NewFetcher(config Config, feeds []Feed, latestFeedDataMap *LatestFeedDataMa
 		cancel:              nil,
 		latestFeedDataMap:   latestFeedDataMap,
 		FeedDataDumpChannel: feedDataDumpChannel,
+		chainHelpers:        map[string]ChainHelper{},
 	}
 }
 
@@ -93,7 +96,7 @@ func (f *Fetcher) fetch(proxies []Proxy) ([]*FeedData, error) {
 				return
 			}
 
-			var resultValue float64
+			var resultValue *big.Int
 			var fetchErr error
 
 			switch {
@@ -103,6 +106,12 @@ func (f *Fetcher) fetch(proxies []Proxy) ([]*FeedData, error) {
 					errChan <- fetchErr
 					return
 				}
+			case isOnchainType(*definition.Type):
+				resultValue, fetchErr = f.onchain(definition)
+				if fetchErr != nil {
+					errChan <- fetchErr
+					return
+				}
 			default:
 				errChan <- errorSentinel.ErrFetcherInvalidType
 				return
@@ -138,14 +147,20 @@ func (f *Fetcher) fetch(proxies []Proxy) ([]*FeedData, error) {
 	return data, nil
 }
 
-func (f *Fetcher) cex(definition *Definition, proxies []Proxy) (float64, error) {
+func (f *Fetcher) cex(definition *Definition, proxies []Proxy) (*big.Int, error) {
 	rawResult, err := f.requestFeed(definition, proxies)
 	if err != nil {
 		log.Warn().Str("Player", "Fetcher").Err(err).Msg("error in requestFeed")
-		return 0, err
+		return nil, err
 	}
 
-	return reducer.Reduce(rawResult, definition.Reducers)
+	// reducers of http definitions scale into DECIMALS, feeds of configs with other decimals are scaled into those instead
+	reducers, err := reducer.Rescale(definition.Reducers, DECIMALS, int(types.DecimalsOrDefault(f.Decimals)))
+	if err != nil {
+		log.Warn().Str("Player", "Fetcher").Err(err).Int32("decimals", f.Decimals).Msg("reducers can not be rescaled to config decimals")
+		return nil, err
+	}
+	return reduceValue(rawResult, reducers)
 }
 
 func (f *Fetcher) requestFeed(definition *Definition, proxies []Proxy) (interface{}, error) {
@@ -167,11 +182,19 @@ func (f *Fetcher) requestFeed(definition *Definition, proxies []Proxy) (interfac
 }
 
 func (f *Fetcher) requestWithoutProxy(definition *Definition) (interface{}, error) {
-	return request.Request[interface{}](request.WithEndpoint(*definition.Url), request.WithHeaders(definition.Headers))
+	opts, err := definition.requestOptions(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return request.Request[interface{}](opts...)
 }
 
 func (f *Fetcher) requestWithProxy(definition *Definition, proxyUrl string) (interface{}, error) {
-	return request.Request[interface{}](request.WithEndpoint(*definition.Url), request.WithHeaders(definition.Headers), request.WithProxy(proxyUrl))
+	opts, err := definition.requestOptions(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return request.Request[interface{}](append(opts, request.WithProxy(proxyUrl))...)
 }
 
 func (f *Fetcher) filterProxyByLocation(proxies []Proxy, location string) []Proxy {
@@ -4,12 +4,15 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"math/big"
 	"strconv"
 	"testing"
+	"time"
 
 	"net/http"
 
 	"bisonai.com/miko/node/pkg/admin/tests"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"github.com/elazarl/goproxy"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
@@ -205,7 +208,7 @@ func TestFetcherCex(t *testing.T) {
 			if err != nil {
 				t.Fatalf("error fetching: %v", err)
 			}
-			assert.Greater(t, result, float64(0))
+			assert.Equal(t, 1, result.Sign())
 		}
 	}
 }
@@ -279,3 +282,49 @@ func TestFetcherFilterProxyByLocation(t *testing.T) {
 	assert.Greater(t, len(res), 0)
 	assert.Equal(t, res[0], proxies[2])
 }
+
+type mockChainHelper struct {
+	results map[string]interface{}
+}
+
+func (m *mockChainHelper) ReadContract(ctx context.Context, contractAddress string, functionString string, args ...interface{}) (interface{}, error) {
+	return m.results[functionString], nil
+}
+
+func (m *mockChainHelper) ChainID() *big.Int {
+	return big.NewInt(1)
+}
+
+func (m *mockChainHelper) Close() {}
+
+func TestFetcherOnchain(t *testing.T) {
+	feedType := ChainlinkFeedType
+	chainId := "1"
+	address := "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+
+	chainHelper := &mockChainHelper{results: map[string]interface{}{
+		LatestRoundDataFuncSignature: []interface{}{big.NewInt(1), big.NewInt(250012345678), big.NewInt(0), big.NewInt(time.Now().Unix()), big.NewInt(1)},
+		DecimalsFuncSignature:        []interface{}{uint8(8)},
+	}}
+	fetcher := &Fetcher{chainHelpers: map[string]ChainHelper{chainId: chainHelper}}
+	definition := &Definition{Type: &feedType, ChainID: &chainId, Address: &address}
+
+	value, err := fetcher.onchain(definition)
+	if err != nil {
+		t.Fatalf("error fetching onchain value: %v", err)
+	}
+	assert.Equal(t, big.NewInt(250012345678), value)
+
+	staleness := "1h"
+	definition.MaxStaleness = &staleness
+	chainHelper.results[LatestRoundDataFuncSignature] = []interface{}{big.NewInt(1), big.NewInt(250012345678), big.NewInt(0), big.NewInt(time.Now().Add(-2 * time.Hour).Unix()), big.NewInt(1)}
+	_, err = fetcher.onchain(definition)
+	assert.ErrorIs(t, err, errorSentinel.ErrFetcherStaleOnchainData)
+}
+
+func TestScaleToDecimals(t *testing.T) {
+	rate, _ := new(big.Int).SetString("1150000000000000000", 10)
+	assert.Equal(t, big.NewInt(115000000), scaleToDecimals(rate, 18, DECIMALS))
+	assert.Equal(t, big.NewInt(1150000), scaleToDecimals(rate, 18, 6))
+	assert.Equal(t, big.NewInt(1234500000000), scaleToDecimals(big.NewInt(12345), 0, DECIMALS))
+}
@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"math/big"
 	"testing"
 	"time"
 
@@ -41,7 +42,7 @@ func TestLocalAggregateBulkWriter(t *testing.T) {
 		}
 		app.LocalAggregators[config.ID] = NewLocalAggregator(config, localAggregatorFeeds, localAggregatesChannel, testItems.messageBus, app.LatestFeedDataMap)
 		for _, feed := range localAggregatorFeeds {
-			feedData = append(feedData, &FeedData{FeedID: feed.ID, Value: DUMMY_FEED_VALUE, Timestamp: nil, Volume: DUMMY_FEED_VALUE})
+			feedData = append(feedData, &FeedData{FeedID: feed.ID, Value: big.NewInt(DUMMY_FEED_VALUE), Timestamp: nil, Volume: DUMMY_FEED_VALUE})
 		}
 	}
 	err = app.startAllLocalAggregators(ctx)
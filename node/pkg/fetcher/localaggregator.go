@@ -3,12 +3,16 @@ package fetcher
 import (
 	"context"
 	"fmt"
-	"math"
+	"math/big"
 	"os"
 	"slices"
 	"time"
 
 	"bisonai.com/miko/node/pkg/bus"
+	"bisonai.com/miko/node/pkg/common/types"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"bisonai.com/miko/node/pkg/utils/fxcalendar"
 	"github.com/montanaflynn/stats"
 	"github.com/rs/zerolog/log"
 )
@@ -27,6 +31,7 @@ func NewLocalAggregator(
 		bus:                    bus,
 		localAggregatesChannel: localAggregatesChannel,
 		latestFeedDataMap:      latestFeedDataMap,
+		calendar:               fxcalendar.Default(),
 	}
 }
 
@@ -76,108 +81,173 @@ func (c *LocalAggregator) Job(ctx context.Context) error {
 }
 
 func (c *LocalAggregator) processFeeds(ctx context.Context, feeds []*FeedData) error {
-	if isFXPricePair(c.Name) {
-		return c.processFXPricePair(ctx, feeds)
+	if c.marketClosed(time.Now()) {
+		lastClose, exists := c.getLastClose()
+		if exists {
+			return c.streamLocalAggregate(ctx, lastClose)
+		}
 	}
-	return c.processVolumeWeightedFeeds(ctx, feeds)
-}
 
-func (c *LocalAggregator) processFXPricePair(ctx context.Context, feeds []*FeedData) error {
-	median, err := calculateMedian(feeds)
-	if err != nil {
-		log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateMedian in localAggregator")
-		return err
+	algorithm := c.algorithm
+	if algorithm == nil {
+		algorithm = DefaultAggregationAlgorithm
 	}
-	return c.streamLocalAggregate(ctx, median)
-}
 
-func (c *LocalAggregator) processVolumeWeightedFeeds(ctx context.Context, feeds []*FeedData) error {
-	filtered, err := filterOutliers(feeds)
+	result, err := algorithm(c.Name, feeds)
 	if err != nil {
-		log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in filterOutliers in localAggregator")
 		return err
 	}
+	if isFXPricePair(c.Name) {
+		// while closed this is only reached without a last close, e.g. after starting on a weekend
+		c.setLastClose(result.Value)
+	}
+	return c.streamLocalAggregate(ctx, result.Value)
+}
 
-	volumeWeightedFeeds, medianFeeds := partitionFeeds(filtered)
-	vwap, err := calculateVWAP(volumeWeightedFeeds)
-	if err != nil {
-		log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateVWAP in localAggregator")
-		return err
+func (c *LocalAggregator) marketClosed(now time.Time) bool {
+	return isFXPricePair(c.Name) && c.calendar != nil && !c.calendar.IsOpen(now)
+}
+
+func (c *LocalAggregator) getLastClose() (*big.Int, bool) {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	return c.lastClose, c.lastClose != nil && c.lastClose.Sign() != 0
+}
+
+func (c *LocalAggregator) setLastClose(value *big.Int) {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	c.lastClose = value
+}
+
+// DefaultAggregationAlgorithm is the aggregation used by running nodes
+var DefaultAggregationAlgorithm = NewAggregationAlgorithm(DefaultAggregationParams())
+
+func DefaultAggregationParams() AggregationParams {
+	return AggregationParams{
+		MaxOutlierRemovalRatio: MaxOutlierRemovalRatio,
+		MedianRatio:            DefaultMedianRatio,
+		MaxSpreadRatio:         DefaultMaxSpreadRatio,
 	}
+}
 
-	median, err := calculateMedian(medianFeeds)
-	if err != nil {
-		log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateMedian in localAggregator")
-		return err
+// NewAggregationAlgorithm aggregates fx pairs by their median, other configs by the vwap of feeds with volume
+// blended with the median of feeds without, after removing order book feeds with wide spreads and quartile outliers
+func NewAggregationAlgorithm(params AggregationParams) AggregationAlgorithm {
+	return func(name string, feeds []*FeedData) (AggregationResult, error) {
+		if isFXPricePair(name) {
+			median, err := calculateMedian(feeds)
+			if err != nil {
+				log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateMedian in localAggregator")
+				return AggregationResult{}, err
+			}
+			return AggregationResult{Value: decimal.Round(median)}, nil
+		}
+
+		count := len(feeds)
+		filtered := filterOutliers(filterWideSpreads(feeds, params.MaxSpreadRatio), params.MaxOutlierRemovalRatio)
+
+		volumeWeightedFeeds, medianFeeds := partitionFeeds(filtered)
+		vwap, err := calculateVWAP(volumeWeightedFeeds)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateVWAP in localAggregator")
+			return AggregationResult{}, err
+		}
+
+		median, err := calculateMedian(medianFeeds)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "LocalAggregator").Msg("error in calculateMedian in localAggregator")
+			return AggregationResult{}, err
+		}
+		log.Debug().Str("Player", "LocalAggregator").Msg(fmt.Sprintf("VWAP: %s Median: %s", vwap.FloatString(2), median.FloatString(2)))
+		aggregated, err := calculateAggregatedPrice(vwap, median, params.MedianRatio)
+		if err != nil {
+			return AggregationResult{}, err
+		}
+		return AggregationResult{
+			Value:           decimal.Round(aggregated),
+			OutliersRemoved: count - len(filtered),
+		}, nil
 	}
-	log.Debug().Str("Player", "LocalAggregator").Msg(fmt.Sprintf("VWAP: %f Median: %f", vwap, median))
-	aggregated := calculateAggregatedPrice(vwap, median)
-	return c.streamLocalAggregate(ctx, aggregated)
 }
 
-func filterOutliers(feeds []*FeedData) ([]*FeedData, error) {
-	if len(feeds) < 5 {
-		return feeds, nil
+// filterWideSpreads drops order book feeds whose spread exceeds maxSpreadRatio times the median spread of the book feeds,
+// with fewer than three book feeds none of them can stand out
+func filterWideSpreads(feeds []*FeedData, maxSpreadRatio float64) []*FeedData {
+	if maxSpreadRatio <= 0 {
+		return feeds
 	}
 
-	data := make([]float64, len(feeds))
-	for i, feed := range feeds {
-		data[i] = feed.Value
+	spreads := stats.Float64Data{}
+	for _, feed := range feeds {
+		if feed.Spread != nil {
+			spreads = append(spreads, *feed.Spread)
+		}
+	}
+	if spreads.Len() < 3 {
+		return feeds
 	}
 
-	outliers, err := stats.QuartileOutliers(data)
-	if err != nil {
-		return nil, err
+	median, err := spreads.Median()
+	if err != nil || median <= 0 {
+		return feeds
+	}
+
+	return slices.DeleteFunc(slices.Clone(feeds), func(feed *FeedData) bool {
+		return feed.Spread != nil && *feed.Spread > median*maxSpreadRatio
+	})
+}
+
+func filterOutliers(feeds []*FeedData, maxOutlierRemovalRatio float64) []*FeedData {
+	if len(feeds) < 5 {
+		return feeds
 	}
 
-	if outliers.Mild.Len() == 0 && outliers.Extreme.Len() == 0 {
-		return feeds, nil
+	values := make([]*big.Rat, len(feeds))
+	for i, feed := range feeds {
+		values[i] = new(big.Rat).SetInt(feed.Value)
 	}
 
-	median, err := stats.Median(data)
-	if err != nil {
-		return nil, err
+	mild, extreme := decimal.QuartileOutliers(values)
+	if len(mild) == 0 && len(extreme) == 0 {
+		return feeds
 	}
 
-	maxOutliersToRemove := int(float64(len(feeds)) * MaxOutlierRemovalRatio)
+	median := decimal.Median(values)
+	maxOutliersToRemove := int(float64(len(feeds)) * maxOutlierRemovalRatio)
 
-	filtered := feeds
-	var extremes stats.Float64Data
-	if outliers.Extreme.Len() > 0 {
-		slices.SortFunc(outliers.Extreme, func(a, b float64) int {
-			if math.Abs(median-a) < math.Abs(median-b) {
-				return 1
-			} else if math.Abs(median-a) > math.Abs(median-b) {
-				return -1
-			} else {
-				return 0
-			}
-		})
+	// DeleteFunc works in place, the caller keeps its feeds
+	filtered := slices.Clone(feeds)
+	extremes := farthestFirst(extreme, median)[:min(maxOutliersToRemove, len(extreme))]
+	filtered = slices.DeleteFunc(filtered, func(feed *FeedData) bool {
+		return containsValue(extremes, feed.Value)
+	})
 
-		extremes = outliers.Extreme[:min(maxOutliersToRemove, outliers.Extreme.Len())]
-		filtered = slices.DeleteFunc(feeds, func(feed *FeedData) bool {
-			return slices.Contains(extremes, feed.Value)
+	if len(extremes) < maxOutliersToRemove && len(mild) > 0 {
+		milds := farthestFirst(mild, median)[:min(maxOutliersToRemove-len(extremes), len(mild))]
+		filtered = slices.DeleteFunc(filtered, func(feed *FeedData) bool {
+			return containsValue(milds, feed.Value)
 		})
 	}
 
-	if extremes.Len() < maxOutliersToRemove && outliers.Mild.Len() > 0 {
-		slices.SortFunc(outliers.Mild, func(a, b float64) int {
-			if math.Abs(median-a) < math.Abs(median-b) {
-				return 1
-			} else if math.Abs(median-a) > math.Abs(median-b) {
-				return -1
-			} else {
-				return 0
-			}
-		})
+	return filtered
+}
 
-		milds := outliers.Mild[:min(maxOutliersToRemove-extremes.Len(), outliers.Mild.Len())]
-		filtered = slices.DeleteFunc(filtered, func(feed *FeedData) bool {
-			return slices.Contains(milds, feed.Value)
-		})
+func farthestFirst(values []*big.Rat, median *big.Rat) []*big.Rat {
+	distance := func(value *big.Rat) *big.Rat {
+		return new(big.Rat).Abs(new(big.Rat).Sub(median, value))
 	}
+	slices.SortFunc(values, func(a, b *big.Rat) int {
+		return distance(b).Cmp(distance(a))
+	})
+	return values
+}
 
-	return filtered, nil
+func containsValue(values []*big.Rat, value *big.Int) bool {
+	target := new(big.Rat).SetInt(value)
+	return slices.ContainsFunc(values, func(v *big.Rat) bool {
+		return v.Cmp(target) == 0
+	})
 }
 
 func partitionFeeds(feeds []*FeedData) ([]*FeedData, []*FeedData) {
@@ -195,21 +265,36 @@ func partitionFeeds(feeds []*FeedData) ([]*FeedData, []*FeedData) {
 	return volumeWeightedFeeds, medianFeeds
 }
 
-func calculateAggregatedPrice(valueWeightedAveragePrice, medianPrice float64) float64 {
-	if valueWeightedAveragePrice == 0 {
-		return medianPrice
-	} else if medianPrice == 0 {
-		return valueWeightedAveragePrice
+// calculateAggregatedPrice blends both prices exactly, the caller rounds the result once
+func calculateAggregatedPrice(valueWeightedAveragePrice, medianPrice *big.Rat, medianRatio float64) (*big.Rat, error) {
+	if valueWeightedAveragePrice.Sign() == 0 {
+		return medianPrice, nil
+	} else if medianPrice.Sign() == 0 {
+		return valueWeightedAveragePrice, nil
 	}
-	return valueWeightedAveragePrice*(1-DefaultMedianRatio) + medianPrice*DefaultMedianRatio
+
+	ratio, err := decimal.FromFloat64(medianRatio)
+	if err != nil {
+		return nil, err
+	}
+	weighted := new(big.Rat).Mul(valueWeightedAveragePrice, new(big.Rat).Sub(big.NewRat(1, 1), ratio))
+	return weighted.Add(weighted, new(big.Rat).Mul(medianPrice, ratio)), nil
 }
 
-func (c *LocalAggregator) streamLocalAggregate(ctx context.Context, aggregated float64) error {
-	if aggregated != 0 {
+func (c *LocalAggregator) streamLocalAggregate(ctx context.Context, aggregated *big.Int) error {
+	if aggregated == nil {
+		return nil
+	}
+	if !aggregated.IsInt64() {
+		log.Error().Str("Player", "LocalAggregator").Str("name", c.Name).Str("aggregated", aggregated.String()).Int32("decimals", c.Decimals).Msg("aggregate out of range, dropping it")
+		return errorSentinel.ErrFetcherAggregateOutOfRange
+	}
+	if aggregated.Sign() != 0 {
 		localAggregate := &LocalAggregate{
 			ConfigID:  c.ID,
-			Value:     int64(aggregated),
+			Value:     aggregated.Int64(),
 			Timestamp: time.Now(),
+			Decimals:  types.DecimalsOrDefault(c.Decimals),
 		}
 
 		msg := bus.Message{
@@ -232,5 +317,12 @@ func (c *LocalAggregator) collect(ctx context.Context) ([]*FeedData, error) {
 	for i, feed := range c.Feeds {
 		feedIds[i] = feed.ID
 	}
-	return c.latestFeedDataMap.GetLatestFeedData(feedIds)
+	feeds, err := c.latestFeedDataMap.GetLatestFeedData(feedIds)
+	if err != nil || c.feedHealth == nil {
+		return feeds, err
+	}
+
+	return slices.DeleteFunc(feeds, func(feed *FeedData) bool {
+		return c.feedHealth.IsStale(feed.FeedID)
+	}), nil
 }
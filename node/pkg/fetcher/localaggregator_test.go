@@ -0,0 +1,170 @@
+//nolint:all
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/bus"
+	chainutils "bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/common/types"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	wsscommon "bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// nodes collect the same feeds in different orders, the aggregate must not depend on it
+func TestAggregationIsDeterministicAcrossNodes(t *testing.T) {
+	feeds := []*FeedData{
+		{FeedID: 1, Value: big.NewInt(6412312345678), Volume: 0.1},
+		{FeedID: 2, Value: big.NewInt(6412312345679), Volume: 0.2},
+		{FeedID: 3, Value: big.NewInt(6412312345690), Volume: 0.3},
+		{FeedID: 4, Value: big.NewInt(6412312345601), Volume: 1e-9},
+		{FeedID: 5, Value: big.NewInt(6412312345655), Volume: 123456.789},
+		{FeedID: 6, Value: big.NewInt(6412312345650)},
+		{FeedID: 7, Value: big.NewInt(6412312345651)},
+	}
+
+	expected, err := DefaultAggregationAlgorithm("BTC-USDT", feeds)
+	assert.NoError(t, err)
+	assert.True(t, expected.Value.IsInt64())
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		shuffled := make([]*FeedData, len(feeds))
+		copy(shuffled, feeds)
+		random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		result, err := DefaultAggregationAlgorithm("BTC-USDT", shuffled)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Value, result.Value)
+	}
+}
+
+// values beyond 2^53 are no longer exact as float64, neighbouring units must stay apart
+func TestAggregationIsExactBeyondFloatPrecision(t *testing.T) {
+	base, _ := new(big.Int).SetString("9007199254740993", 10)
+	feeds := []*FeedData{
+		{FeedID: 1, Value: base, Volume: 1},
+		{FeedID: 2, Value: new(big.Int).Add(base, big.NewInt(2)), Volume: 1},
+		{FeedID: 3, Value: new(big.Int).Add(base, big.NewInt(4)), Volume: 2},
+	}
+
+	// (a + (a+2) + 2(a+4)) / 4 = a + 2.5
+	result, err := DefaultAggregationAlgorithm("BTC-USDT", feeds)
+	assert.NoError(t, err)
+	assert.Equal(t, "9007199254740996", result.Value.String())
+
+	median, err := DefaultAggregationAlgorithm("KRW-USD", feeds[:2])
+	assert.NoError(t, err)
+	assert.Equal(t, "9007199254740994", median.Value.String())
+}
+
+func TestStreamLocalAggregateOutOfRange(t *testing.T) {
+	aggregator := &LocalAggregator{Config: Config{Name: "BTC-USDT"}}
+	tooLarge, _ := new(big.Int).SetString("9223372036854775808", 10)
+
+	err := aggregator.streamLocalAggregate(context.Background(), tooLarge)
+	assert.ErrorIs(t, err, errorSentinel.ErrFetcherAggregateOutOfRange)
+	assert.NoError(t, aggregator.streamLocalAggregate(context.Background(), new(big.Int)))
+}
+
+func TestAggregationRoundsHalfAwayFromZero(t *testing.T) {
+	// median of 100 and 101 is 100.5, which used to be truncated to 100 when streamed
+	result, err := DefaultAggregationAlgorithm("KRW-USD", []*FeedData{{Value: big.NewInt(100)}, {Value: big.NewInt(101)}})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(101), result.Value)
+
+	vwap, err := calculateVWAP([]*FeedData{{Value: big.NewInt(1), Volume: 1}, {Value: big.NewInt(2), Volume: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, "5/3", vwap.RatString())
+}
+
+func TestAggregationDropsWideSpreads(t *testing.T) {
+	tight, wide := 0.001, 0.02
+	feeds := []*FeedData{
+		{FeedID: 1, Value: big.NewInt(100), Spread: &tight},
+		{FeedID: 2, Value: big.NewInt(101), Spread: &tight},
+		{FeedID: 3, Value: big.NewInt(110), Spread: &wide},
+		{FeedID: 4, Value: big.NewInt(102)},
+	}
+
+	result, err := DefaultAggregationAlgorithm("BTC-USDT", feeds)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(101), result.Value)
+	assert.Equal(t, 1, result.OutliersRemoved)
+
+	// ticker feeds carry no spread and two book feeds can not single one out
+	assert.Len(t, filterWideSpreads(feeds[1:], DefaultMaxSpreadRatio), 3)
+	assert.Len(t, filterWideSpreads(feeds, 0), 4)
+}
+
+// 1e-10 rounds to zero below 10 decimals, at 18 it is fetched, aggregated and signed as 10^8 units
+func TestSubUnitPriceAtMaxDecimals(t *testing.T) {
+	ctx := context.Background()
+	config := Config{ID: 1, Name: "PEPE-USDT", Decimals: types.MaxDecimals}
+	expected := big.NewInt(100_000_000)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"price": "0.0000000001"}`))
+	}))
+	defer mockServer.Close()
+
+	definition := new(Definition)
+	err := json.Unmarshal([]byte(`{
+		"url": "`+mockServer.URL+`",
+		"reducers": [{"function": "PARSE", "args": ["price"]}, {"function": "POW10", "args": 8}, {"function": "ROUND"}]
+	}`), definition)
+	assert.NoError(t, err)
+	httpValue, err := (&Fetcher{Config: config}).cex(definition, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, httpValue)
+
+	feedType, chainId, address := ChainlinkFeedType, "1", "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+	chainHelper := &mockChainHelper{results: map[string]interface{}{
+		LatestRoundDataFuncSignature: []interface{}{big.NewInt(1), big.NewInt(100_000_000), big.NewInt(0), big.NewInt(time.Now().Unix()), big.NewInt(1)},
+		DecimalsFuncSignature:        []interface{}{uint8(18)},
+	}}
+	onchainFetcher := &Fetcher{Config: config, chainHelpers: map[string]ChainHelper{chainId: chainHelper}}
+	onchainValue, err := onchainFetcher.onchain(&Definition{Type: &feedType, ChainID: &chainId, Address: &address})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, onchainValue)
+
+	wssValue, err := wsscommon.PriceStringToValue("0.0000000001")
+	assert.NoError(t, err)
+	wssValue = wsscommon.RescalePrice(wssValue, config.Decimals)
+	assert.Equal(t, expected, wssValue)
+
+	result, err := DefaultAggregationAlgorithm(config.Name, []*FeedData{
+		{FeedID: 1, Value: httpValue},
+		{FeedID: 2, Value: onchainValue},
+		{FeedID: 3, Value: wssValue, Volume: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result.Value)
+
+	messageBus := bus.New(10)
+	messageBus.Subscribe(bus.AGGREGATOR)
+	localAggregatesChannel := make(chan *LocalAggregate, 1)
+	aggregator := NewLocalAggregator(config, nil, localAggregatesChannel, messageBus, nil)
+	assert.NoError(t, aggregator.streamLocalAggregate(ctx, result.Value))
+	localAggregate := <-localAggregatesChannel
+	assert.Equal(t, expected.Int64(), localAggregate.Value)
+	assert.Equal(t, int32(types.MaxDecimals), localAggregate.Decimals)
+
+	pk, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	timestamp := localAggregate.Timestamp.UnixMilli()
+	proof, err := chainutils.MakeValueSignatureWithDecimals(localAggregate.Value, timestamp, config.Name, localAggregate.Decimals, pk)
+	assert.NoError(t, err)
+	signer, err := chainutils.RecoverSigner(chainutils.Value2HashForSignWithDecimals(expected.Int64(), timestamp, config.Name, types.MaxDecimals), proof)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(pk.PublicKey), signer)
+}
@@ -0,0 +1,328 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
+	"bisonai.com/miko/node/pkg/common/types"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	klaytncommon "github.com/klaytn/klaytn/common"
+	"github.com/rs/zerolog/log"
+)
+
+func isOnchainType(definitionType string) bool {
+	return definitionType == ChainlinkFeedType || definitionType == Erc4626VaultType || definitionType == LstExchangeRateType
+}
+
+func (f *Fetcher) onchain(definition *Definition) (*big.Int, error) {
+	if definition.ChainID == nil || definition.Address == nil {
+		return nil, errorSentinel.ErrFetcherInvalidInput
+	}
+
+	chainHelper, ok := f.chainHelpers[*definition.ChainID]
+	if !ok {
+		log.Error().Str("Player", "Fetcher").Str("chainId", *definition.ChainID).Msg("chain helper not found for onchain feed")
+		return nil, errorSentinel.ErrFetcherChainHelperNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultOnchainReadTimeout)
+	defer cancel()
+
+	switch *definition.Type {
+	case ChainlinkFeedType:
+		return f.chainlinkFeed(ctx, chainHelper, definition)
+	case Erc4626VaultType:
+		return f.erc4626Vault(ctx, chainHelper, definition)
+	case LstExchangeRateType:
+		return f.lstExchangeRate(ctx, chainHelper, definition)
+	default:
+		return nil, errorSentinel.ErrFetcherInvalidType
+	}
+}
+
+func (f *Fetcher) chainlinkFeed(ctx context.Context, chainHelper ChainHelper, definition *Definition) (*big.Int, error) {
+	rawResult, err := chainHelper.ReadContract(ctx, *definition.Address, LatestRoundDataFuncSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := rawResult.([]interface{})
+	if !ok || len(result) < 5 {
+		return nil, errorSentinel.ErrFetcherInvalidRawResult
+	}
+
+	answer, ok := result[1].(*big.Int)
+	if !ok {
+		return nil, errorSentinel.ErrFetcherConvertToBigInt
+	}
+
+	updatedAt, ok := result[3].(*big.Int)
+	if !ok {
+		return nil, errorSentinel.ErrFetcherConvertToBigInt
+	}
+
+	err = checkStaleness(updatedAt, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	if answer.Sign() <= 0 {
+		return nil, errorSentinel.ErrFetcherInvalidOnchainAnswer
+	}
+
+	decimals, err := f.decimals(ctx, chainHelper, *definition.Address, definition.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	return scaleToDecimals(answer, decimals, types.DecimalsOrDefault(f.Decimals)), nil
+}
+
+// share price of the vault: assets returned for one whole share, in asset decimals
+func (f *Fetcher) erc4626Vault(ctx context.Context, chainHelper ChainHelper, definition *Definition) (*big.Int, error) {
+	shareDecimals, err := f.decimals(ctx, chainHelper, *definition.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	assetDecimals := definition.Decimals
+	if assetDecimals == nil {
+		asset, assetErr := readAddress(ctx, chainHelper, *definition.Address, AssetFuncSignature)
+		if assetErr != nil {
+			return nil, assetErr
+		}
+
+		decimals, decimalsErr := f.decimals(ctx, chainHelper, asset.Hex(), nil)
+		if decimalsErr != nil {
+			return nil, decimalsErr
+		}
+		assetDecimals = &decimals
+	}
+
+	oneShare := new(big.Int).Exp(big.NewInt(10), big.NewInt(shareDecimals), nil)
+	assets, err := readBigInt(ctx, chainHelper, *definition.Address, ConvertToAssetsFuncSignature, oneShare)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkOptionalStaleness(ctx, chainHelper, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	if assets.Sign() <= 0 {
+		return nil, errorSentinel.ErrFetcherInvalidOnchainAnswer
+	}
+
+	return scaleToDecimals(assets, *assetDecimals, types.DecimalsOrDefault(f.Decimals)), nil
+}
+
+func (f *Fetcher) lstExchangeRate(ctx context.Context, chainHelper ChainHelper, definition *Definition) (*big.Int, error) {
+	if definition.Function == nil {
+		return nil, errorSentinel.ErrFetcherInvalidInput
+	}
+
+	args := []interface{}{}
+	if definition.Amount != nil {
+		amount, ok := new(big.Int).SetString(*definition.Amount, 10)
+		if !ok {
+			return nil, errorSentinel.ErrFetcherInvalidInput
+		}
+		args = append(args, amount)
+	}
+
+	rate, err := readBigInt(ctx, chainHelper, *definition.Address, *definition.Function, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkOptionalStaleness(ctx, chainHelper, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	if rate.Sign() <= 0 {
+		return nil, errorSentinel.ErrFetcherInvalidOnchainAnswer
+	}
+
+	decimals := int64(DefaultExchangeRateDecimals)
+	if definition.Decimals != nil {
+		decimals = *definition.Decimals
+	}
+
+	return scaleToDecimals(rate, decimals, types.DecimalsOrDefault(f.Decimals)), nil
+}
+
+// decimals() of the contract, cached per address since it never changes
+func (f *Fetcher) decimals(ctx context.Context, chainHelper ChainHelper, address string, override *int64) (int64, error) {
+	if override != nil {
+		return *override, nil
+	}
+
+	key := chainHelper.ChainID().String() + ":" + address
+	if cached, ok := f.decimalsCache.Load(key); ok {
+		return cached.(int64), nil
+	}
+
+	rawResult, err := chainHelper.ReadContract(ctx, address, DecimalsFuncSignature)
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := rawResult.([]interface{})
+	if !ok || len(result) < 1 {
+		return 0, errorSentinel.ErrFetcherInvalidRawResult
+	}
+
+	decimals, ok := result[0].(uint8)
+	if !ok {
+		return 0, errorSentinel.ErrFetcherInvalidRawResult
+	}
+
+	f.decimalsCache.Store(key, int64(decimals))
+	return int64(decimals), nil
+}
+
+func readBigInt(ctx context.Context, chainHelper ChainHelper, address string, functionString string, args ...interface{}) (*big.Int, error) {
+	rawResult, err := chainHelper.ReadContract(ctx, address, functionString, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := rawResult.([]interface{})
+	if !ok || len(result) < 1 {
+		return nil, errorSentinel.ErrFetcherInvalidRawResult
+	}
+
+	value, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, errorSentinel.ErrFetcherConvertToBigInt
+	}
+	return value, nil
+}
+
+func readAddress(ctx context.Context, chainHelper ChainHelper, address string, functionString string) (klaytncommon.Address, error) {
+	rawResult, err := chainHelper.ReadContract(ctx, address, functionString)
+	if err != nil {
+		return klaytncommon.Address{}, err
+	}
+
+	result, ok := rawResult.([]interface{})
+	if !ok || len(result) < 1 {
+		return klaytncommon.Address{}, errorSentinel.ErrFetcherInvalidRawResult
+	}
+
+	value, ok := result[0].(klaytncommon.Address)
+	if !ok {
+		return klaytncommon.Address{}, errorSentinel.ErrFetcherInvalidRawResult
+	}
+	return value, nil
+}
+
+func checkOptionalStaleness(ctx context.Context, chainHelper ChainHelper, definition *Definition) error {
+	if definition.UpdatedAtFunction == nil {
+		return nil
+	}
+
+	updatedAt, err := readBigInt(ctx, chainHelper, *definition.Address, *definition.UpdatedAtFunction)
+	if err != nil {
+		return err
+	}
+	return checkStaleness(updatedAt, definition)
+}
+
+func checkStaleness(updatedAt *big.Int, definition *Definition) error {
+	maxStaleness := DefaultOnchainMaxStaleness
+	if definition.MaxStaleness != nil {
+		parsed, err := time.ParseDuration(*definition.MaxStaleness)
+		if err != nil {
+			return err
+		}
+		maxStaleness = parsed
+	}
+
+	if updatedAt == nil || !updatedAt.IsInt64() || updatedAt.Sign() <= 0 {
+		return errorSentinel.ErrFetcherStaleOnchainData
+	}
+
+	if time.Since(time.Unix(updatedAt.Int64(), 0)) > maxStaleness {
+		return errorSentinel.ErrFetcherStaleOnchainData
+	}
+	return nil
+}
+
+// converts an integer with the given decimals into a value with the target decimals of the config
+func scaleToDecimals(value *big.Int, decimals int64, targetDecimals int32) *big.Int {
+	return decimal.ScaleInt(value, int(targetDecimals)-int(decimals))
+}
+
+// creates read only chain helpers for every chain referenced by onchain feeds, using provider_urls
+func loadChainHelpers(ctx context.Context, feeds []Feed) map[string]ChainHelper {
+	chainHelpers := make(map[string]ChainHelper)
+	for _, feed := range feeds {
+		definition := new(Definition)
+		err := json.Unmarshal(feed.Definition, &definition)
+		if err != nil || definition.Type == nil || !isOnchainType(*definition.Type) || definition.ChainID == nil {
+			continue
+		}
+
+		chainId := *definition.ChainID
+		if _, exists := chainHelpers[chainId]; exists {
+			continue
+		}
+
+		chainHelper, err := newReadOnlyChainHelper(ctx, chainId)
+		if err != nil {
+			log.Error().Str("Player", "Fetcher").Err(err).Str("chainId", chainId).Msg("failed to create chain helper for onchain feed")
+			continue
+		}
+		chainHelpers[chainId] = chainHelper
+	}
+	return chainHelpers
+}
+
+func newReadOnlyChainHelper(ctx context.Context, chainId string) (ChainHelper, error) {
+	chainIdInt, err := strconv.Atoi(chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	providerUrls, err := utils.LoadProviderUrls(ctx, chainIdInt)
+	if err != nil {
+		return nil, err
+	}
+
+	// kaia chain ids are read through the klaytn client, every other chain through the eth client
+	blockchainType := helper.Ethereum
+	if slices.Contains(websocketchainreader.KaiaChainIds, chainId) {
+		blockchainType = helper.Kaia
+	}
+
+	// empty provider url falls back to KAIA_PROVIDER_URL or ETH_PROVIDER_URL
+	providerUrl := ""
+	if len(providerUrls) > 0 {
+		providerUrl = providerUrls[0]
+	}
+
+	chainHelper, err := helper.NewChainHelper(ctx,
+		helper.WithBlockchainType(blockchainType),
+		helper.WithProviderUrl(providerUrl),
+		helper.WithReadOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	if chainHelper.ChainID().String() != chainId {
+		chainHelper.Close()
+		return nil, errorSentinel.ErrFetcherNoMatchingChainID
+	}
+	return chainHelper, nil
+}
@@ -0,0 +1,229 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"bisonai.com/miko/node/pkg/db"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"github.com/rs/zerolog/log"
+)
+
+func NewQuarantine(maxDeviation float64, confirmTicks int) *Quarantine {
+	if maxDeviation <= 0 {
+		maxDeviation = DefaultQuarantineMaxDeviation
+	}
+	if confirmTicks <= 0 {
+		confirmTicks = DefaultQuarantineConfirmTicks
+	}
+	return &Quarantine{
+		MaxDeviation: maxDeviation,
+		ConfirmTicks: confirmTicks,
+		states:       make(map[int32]*feedQuarantineState),
+		feedConfigs:  make(map[int32]int32),
+		configFeeds:  make(map[int32][]int32),
+		logChannel:   make(chan QuarantineLog, QuarantineLogChannelSize),
+	}
+}
+
+// SetFeeds groups feeds by config, the sources of a config are checked against each other.
+// History of feeds no longer present is dropped.
+func (q *Quarantine) SetFeeds(configFeeds map[int32][]int32) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.configFeeds = configFeeds
+	q.feedConfigs = make(map[int32]int32)
+	for configID, feedIds := range configFeeds {
+		for _, feedID := range feedIds {
+			q.feedConfigs[feedID] = configID
+		}
+	}
+
+	for feedID := range q.states {
+		if _, exists := q.feedConfigs[feedID]; !exists {
+			delete(q.states, feedID)
+		}
+	}
+}
+
+// Validate implements types.FeedDataValidator, suspicious values are held back until confirmed
+func (q *Quarantine) Validate(feedData []*FeedData, latest map[int32]*FeedData) []*FeedData {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	accepted := make([]*FeedData, 0, len(feedData))
+	for _, data := range feedData {
+		if data == nil {
+			continue
+		}
+		if q.check(data, latest) {
+			accepted = append(accepted, data)
+		}
+	}
+	return accepted
+}
+
+func (q *Quarantine) check(data *FeedData, latest map[int32]*FeedData) bool {
+	state, exists := q.states[data.FeedID]
+	if !exists {
+		state = &feedQuarantineState{}
+		q.states[data.FeedID] = state
+	}
+
+	reference, reason, suspicious, confirmable := q.inspect(data, state, latest)
+	if !suspicious {
+		if state.pending != nil {
+			q.log(data, QuarantineDecisionReleased, reference, fmt.Sprintf("back within range, dropped %d quarantined ticks", state.pending.ticks))
+			state.pending = nil
+		}
+		state.push(data.Value)
+		return true
+	}
+
+	pending := state.pending
+	if pending == nil {
+		state.pending = &quarantinedValue{values: []*big.Int{data.Value}, ticks: 1, since: time.Now()}
+		q.log(data, QuarantineDecisionQuarantined, reference, reason)
+		return false
+	}
+
+	// held values are logged once per quarantine, a source stuck off the other sources stays held without flooding the logs
+	pending.ticks++
+	if !confirmable || deviation(data.Value, new(big.Rat).SetInt(pending.values[len(pending.values)-1])) > q.MaxDeviation {
+		pending.values = []*big.Int{data.Value}
+		return false
+	}
+
+	pending.values = append(pending.values, data.Value)
+	// a tick agreeing with the other sources followed by ConfirmTicks consistent ones
+	if len(pending.values) > q.ConfirmTicks {
+		q.log(data, QuarantineDecisionConfirmed, reference, fmt.Sprintf("confirmed by %d consistent ticks since %s", q.ConfirmTicks, pending.since.Format(time.RFC3339)))
+		state.history = append([]*big.Int{}, pending.values...)
+		state.pending = nil
+		return true
+	}
+	return false
+}
+
+// inspect checks the value against both the median of the other sources of its config, when there are enough
+// of them, and the feed's own recent history. Only values agreeing with the other sources are confirmable,
+// so a move away from the feed's history is confirmed by a market wide move but never by the source alone.
+func (q *Quarantine) inspect(data *FeedData, state *feedQuarantineState, latest map[int32]*FeedData) (*big.Rat, string, bool, bool) {
+	sources := []*big.Rat{}
+	for _, feedID := range q.configFeeds[q.feedConfigs[data.FeedID]] {
+		source, exists := latest[feedID]
+		if feedID == data.FeedID || !exists || source.Value == nil || source.Value.Sign() == 0 {
+			continue
+		}
+		sources = append(sources, new(big.Rat).SetInt(source.Value))
+	}
+
+	var reference *big.Rat
+	if len(sources) >= QuarantineMinSources {
+		median := decimal.Median(sources)
+		reference = median
+		if deviation(data.Value, median) > q.MaxDeviation {
+			return median, fmt.Sprintf("%.2f%% off the median of %d other sources", deviation(data.Value, median)*100, len(sources)), true, false
+		}
+	}
+
+	if len(state.history) >= QuarantineMinHistory {
+		history := make([]*big.Rat, len(state.history))
+		for i, value := range state.history {
+			history[i] = new(big.Rat).SetInt(value)
+		}
+		median := decimal.Median(history)
+		if deviation(data.Value, median) > q.MaxDeviation {
+			return median, fmt.Sprintf("%.2f%% off the median of the last %d ticks", deviation(data.Value, median)*100, len(state.history)), true, true
+		}
+		if reference == nil {
+			reference = median
+		}
+	}
+
+	return reference, "", false, true
+}
+
+func (s *feedQuarantineState) push(value *big.Int) {
+	s.history = append(s.history, value)
+	if len(s.history) > QuarantineHistorySize {
+		s.history = s.history[len(s.history)-QuarantineHistorySize:]
+	}
+}
+
+// deviation is computed exactly, only the resulting ratio is a float64
+func deviation(value *big.Int, reference *big.Rat) float64 {
+	if reference == nil || reference.Sign() == 0 {
+		return 0
+	}
+	difference := new(big.Rat).Sub(new(big.Rat).SetInt(value), reference)
+	ratio, _ := difference.Quo(difference.Abs(difference), new(big.Rat).Abs(reference)).Float64()
+	return ratio
+}
+
+func (q *Quarantine) log(data *FeedData, decision string, reference *big.Rat, reason string) {
+	entry := QuarantineLog{
+		FeedID:    data.FeedID,
+		Decision:  decision,
+		Value:     data.Value,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+	if reference != nil && reference.Sign() != 0 {
+		entry.Reference = decimal.Round(reference)
+	}
+
+	log.Warn().
+		Str("Player", "Quarantine").
+		Int32("feedId", data.FeedID).
+		Str("decision", decision).
+		Stringer("value", data.Value).
+		Stringer("reference", entry.Reference).
+		Str("reason", reason).
+		Msg("feed quarantine decision")
+
+	select {
+	case q.logChannel <- entry:
+	default:
+		log.Warn().Str("Player", "Quarantine").Int32("feedId", data.FeedID).Msg("quarantine log channel full, decision not stored")
+	}
+}
+
+// Run stores quarantine decisions into feed_quarantine_logs every interval until ctx is done
+func (q *Quarantine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := q.storeLogs(ctx)
+			if err != nil {
+				log.Error().Str("Player", "Quarantine").Err(err).Msg("error in storing quarantine logs")
+			}
+		}
+	}
+}
+
+func (q *Quarantine) storeLogs(ctx context.Context) error {
+	rows := [][]any{}
+loop:
+	for {
+		select {
+		case entry := <-q.logChannel:
+			rows = append(rows, []any{entry.FeedID, entry.Decision, numeric(entry.Value), numeric(entry.Reference), entry.Reason, entry.Timestamp})
+		default:
+			break loop
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	_, err := db.BulkCopy(ctx, "feed_quarantine_logs", []string{"feed_id", "decision", "value", "reference", "reason", "timestamp"}, rows)
+	return err
+}
@@ -0,0 +1,73 @@
+//nolint:all
+package fetcher
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantine(t *testing.T) {
+	now := time.Now()
+	feedData := func(feedID int32, value int64) *FeedData {
+		return &FeedData{FeedID: feedID, Value: big.NewInt(value), Timestamp: &now}
+	}
+
+	t.Run("cross source outlier is never confirmed", func(t *testing.T) {
+		quarantine := NewQuarantine(0.1, 2)
+		quarantine.SetFeeds(map[int32][]int32{1: {1, 2, 3}})
+		latest := map[int32]*FeedData{1: feedData(1, 100), 2: feedData(2, 101), 3: feedData(3, 99)}
+
+		// wrong decimals, consistent but off the other sources however long it is reported
+		for i := 0; i < 10; i++ {
+			assert.Empty(t, quarantine.Validate([]*FeedData{feedData(1, 10000+int64(i))}, latest))
+		}
+
+		decisions := []string{}
+		for len(quarantine.logChannel) > 0 {
+			decisions = append(decisions, (<-quarantine.logChannel).Decision)
+		}
+		assert.Equal(t, []string{QuarantineDecisionQuarantined}, decisions)
+
+		assert.Len(t, quarantine.Validate([]*FeedData{feedData(1, 100)}, latest), 1)
+		released := <-quarantine.logChannel
+		assert.Equal(t, QuarantineDecisionReleased, released.Decision)
+		assert.Contains(t, released.Reason, "dropped 10 quarantined ticks")
+	})
+
+	t.Run("single tick spike is released", func(t *testing.T) {
+		quarantine := NewQuarantine(0.1, 2)
+		quarantine.SetFeeds(map[int32][]int32{1: {1}})
+		latest := map[int32]*FeedData{}
+
+		for i := 0; i < QuarantineMinHistory; i++ {
+			assert.Len(t, quarantine.Validate([]*FeedData{feedData(1, 100)}, latest), 1)
+		}
+		assert.Empty(t, quarantine.Validate([]*FeedData{feedData(1, 200)}, latest))
+		assert.Len(t, quarantine.Validate([]*FeedData{feedData(1, 102)}, latest), 1)
+
+		reference := (<-quarantine.logChannel).Reference
+		assert.Equal(t, big.NewInt(100), reference)
+		assert.Equal(t, QuarantineDecisionReleased, (<-quarantine.logChannel).Decision)
+	})
+
+	t.Run("market wide move is confirmed by the other sources", func(t *testing.T) {
+		quarantine := NewQuarantine(0.1, 2)
+		quarantine.SetFeeds(map[int32][]int32{1: {1, 2, 3}})
+		for i := 0; i < QuarantineMinHistory; i++ {
+			quarantine.Validate([]*FeedData{feedData(1, 100)}, map[int32]*FeedData{})
+		}
+
+		latest := map[int32]*FeedData{2: feedData(2, 80), 3: feedData(3, 81)}
+		assert.Empty(t, quarantine.Validate([]*FeedData{feedData(1, 80), nil}, latest))
+		assert.Empty(t, quarantine.Validate([]*FeedData{feedData(1, 80)}, latest))
+		assert.Len(t, quarantine.Validate([]*FeedData{feedData(1, 81)}, latest), 1)
+		assert.Len(t, quarantine.Validate([]*FeedData{feedData(1, 80)}, latest), 1)
+
+		assert.Equal(t, QuarantineDecisionQuarantined, (<-quarantine.logChannel).Decision)
+		assert.Equal(t, QuarantineDecisionConfirmed, (<-quarantine.logChannel).Decision)
+		assert.Empty(t, quarantine.logChannel)
+	})
+}
@@ -2,29 +2,60 @@ package fetcher
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
+	"sync"
 	"time"
 
 	"bisonai.com/miko/node/pkg/bus"
 	"bisonai.com/miko/node/pkg/common/types"
+	"bisonai.com/miko/node/pkg/utils/fxcalendar"
 	"bisonai.com/miko/node/pkg/utils/reducer"
+	"bisonai.com/miko/node/pkg/utils/request"
 	"bisonai.com/miko/node/pkg/websocketfetcher"
 )
 
 const (
 	SelectAllProxiesQuery                 = `SELECT * FROM proxies`
-	SelectConfigsQuery                    = `SELECT id, name, fetch_interval FROM configs`
-	SelectHttpRequestFeedsByConfigIdQuery = `SELECT * FROM feeds WHERE config_id = @config_id AND NOT (definition::jsonb ? 'type')`
+	SelectConfigsQuery                    = `SELECT id, name, fetch_interval, decimals FROM configs`
+	SelectHttpRequestFeedsByConfigIdQuery = `SELECT * FROM feeds WHERE config_id = @config_id AND (NOT (definition::jsonb ? 'type') OR definition->>'type' IN ('` + ChainlinkFeedType + `', '` + Erc4626VaultType + `', '` + LstExchangeRateType + `'))`
 	SelectFeedsByConfigIdQuery            = `SELECT * FROM feeds WHERE config_id = @config_id`
 	InsertLocalAggregateQuery             = `INSERT INTO local_aggregates (config_id, value) VALUES (@config_id, @value)`
 	DECIMALS                              = 8
 	DefaultFeedDataDumpInterval           = time.Second * 10
-	ForeignExchangePricePairs             = "GBP-USD,EUR-USD,KRW-USD,JPY-USD,CHF-USD"
+	ForeignExchangePricePairs             = fxcalendar.PricePairs
 	DefaultMedianRatio                    = 0.05
 	LocalAggregatesChannelSize            = 2_000
 	DefaultLocalAggregateInterval         = 200 * time.Millisecond
 	DefaultFeedDataDumpChannelSize        = 20000
 	MaxOutlierRemovalRatio                = 0.25
+	// order book feeds quoting a spread wider than this multiple of the median spread of the config are left out
+	DefaultMaxSpreadRatio = 5.0
+
+	// feed values deviating more than this ratio from their reference are quarantined
+	DefaultQuarantineMaxDeviation = 0.1
+	DefaultQuarantineConfirmTicks = 3
+	QuarantineHistorySize         = 20
+	QuarantineMinHistory          = 5
+	QuarantineMinSources          = 2
+	QuarantineLogChannelSize      = 1000
+	DefaultQuarantineLogInterval  = 10 * time.Second
+
+	QuarantineDecisionQuarantined = "quarantined"
+	QuarantineDecisionConfirmed   = "confirmed"
+	QuarantineDecisionReleased    = "released"
+
+	ChainlinkFeedType           = "ChainlinkFeed"
+	Erc4626VaultType            = "Erc4626Vault"
+	LstExchangeRateType         = "LstExchangeRate"
+	DefaultOnchainMaxStaleness  = 25 * time.Hour
+	DefaultOnchainReadTimeout   = 5 * time.Second
+	DefaultExchangeRateDecimals = 18
+
+	LatestRoundDataFuncSignature = "function latestRoundData() external view returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)"
+	DecimalsFuncSignature        = "function decimals() external view returns (uint8)"
+	AssetFuncSignature           = "function asset() external view returns (address)"
+	ConvertToAssetsFuncSignature = "function convertToAssets(uint256 shares) external view returns (uint256 assets)"
 )
 
 type Feed = types.Feed
@@ -33,10 +64,49 @@ type LocalAggregate = types.LocalAggregate
 type Proxy = types.Proxy
 type LatestFeedDataMap = types.LatestFeedDataMap
 
+// FeedHealth reports feeds whose source stopped streaming, they are left out of local aggregation
+type FeedHealth interface {
+	IsStale(feedID int32) bool
+}
+
+type QuarantineLog struct {
+	FeedID    int32     `db:"feed_id"`
+	Decision  string    `db:"decision"`
+	Value     *big.Int  `db:"value"`
+	Reference *big.Int  `db:"reference"`
+	Reason    string    `db:"reason"`
+	Timestamp time.Time `db:"timestamp"`
+}
+
+type quarantinedValue struct {
+	values []*big.Int // consistent run of the latest held ticks
+	ticks  int
+	since  time.Time
+}
+
+type feedQuarantineState struct {
+	history []*big.Int
+	pending *quarantinedValue
+}
+
+// Quarantine holds back feed values which deviate from the feed's own history or from the other
+// sources of the same config, accepting them only after ConfirmTicks consistent ticks agreeing with the other sources
+type Quarantine struct {
+	MaxDeviation float64
+	ConfirmTicks int
+
+	mu          sync.Mutex
+	states      map[int32]*feedQuarantineState
+	feedConfigs map[int32]int32
+	configFeeds map[int32][]int32
+	logChannel  chan QuarantineLog
+}
+
 type Config struct {
 	ID            int32  `db:"id"`
 	Name          string `db:"name"`
 	FetchInterval int32  `db:"fetch_interval"`
+	Decimals      int32  `db:"decimals"`
 }
 
 type Fetcher struct {
@@ -48,6 +118,8 @@ type Fetcher struct {
 	isRunning           bool
 	latestFeedDataMap   *LatestFeedDataMap
 	FeedDataDumpChannel chan *FeedData
+	chainHelpers        map[string]ChainHelper
+	decimalsCache       sync.Map
 }
 
 type LocalAggregator struct {
@@ -61,8 +133,30 @@ type LocalAggregator struct {
 
 	localAggregatesChannel chan *LocalAggregate
 	latestFeedDataMap      *LatestFeedDataMap
+	feedHealth             FeedHealth
+	algorithm              AggregationAlgorithm
+
+	// fx configs keep publishing their last close while the market is closed
+	calendar  *fxcalendar.Calendar
+	lastClose *big.Int
+	closeMu   sync.Mutex
+}
+
+type AggregationParams struct {
+	MaxOutlierRemovalRatio float64 // share of feeds which may be dropped as outliers
+	MedianRatio            float64 // weight of the median of feeds without volume against the vwap
+	MaxSpreadRatio         float64 // multiple of the median spread above which order book feeds are dropped, 0 disables
+}
+
+type AggregationResult struct {
+	Value *big.Int
+	// feeds dropped for a wide spread or as outliers
+	OutliersRemoved int
 }
 
+// AggregationAlgorithm turns the latest feed data of a config into its local aggregate
+type AggregationAlgorithm func(name string, feeds []*FeedData) (AggregationResult, error)
+
 type FeedDataBulkWriter struct {
 	Interval time.Duration
 
@@ -91,14 +185,27 @@ type App struct {
 	LatestFeedDataMap        *LatestFeedDataMap
 	Proxies                  []Proxy
 	FeedDataDumpChannel      chan *FeedData
+	ChainHelpers             map[string]ChainHelper
+	Quarantine               *Quarantine
+}
+
+// GraphQLQuery is posted as {"query": ..., "variables": ...}
+type GraphQLQuery struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
 }
 
 type Definition struct {
-	Url      *string           `json:"url"`
-	Headers  map[string]string `json:"headers"`
-	Method   *string           `json:"method"`
-	Reducers []reducer.Reducer `json:"reducers"`
-	Location *string           `json:"location"`
+	Url         *string           `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	Method      *string           `json:"method"`      // defaults to GET, or POST when a body is set
+	Body        json.RawMessage   `json:"body"`        // json body, a string is sent as is with a non json content type
+	GraphQL     *GraphQLQuery     `json:"graphql"`     // sent as a json body, exclusive with body
+	ContentType *string           `json:"contentType"` // defaults to application/json
+	Reducers    []reducer.Reducer `json:"reducers"`
+	Location    *string           `json:"location"`
+	Auth        *request.Auth     `json:"auth"` // holds secret names, credentials are resolved per request
 
 	// dex specific
 	Type           *string `json:"type"`
@@ -107,6 +214,13 @@ type Definition struct {
 	Token0Decimals *int64  `json:"token0Decimals"`
 	Token1Decimals *int64  `json:"token1Decimals"`
 	Reciprocal     *bool   `json:"reciprocal"`
+
+	// onchain feed specific, ChainID and Address are shared with dex definitions
+	Decimals          *int64  `json:"decimals"`          // decimals of the returned value, read from the contract when omitted
+	Function          *string `json:"function"`          // exchange rate getter signature for LstExchangeRate
+	Amount            *string `json:"amount"`            // uint256 argument passed to the getter, e.g. shares for getPooledEthByShares
+	UpdatedAtFunction *string `json:"updatedAtFunction"` // optional timestamp getter used for the staleness check of vaults and lsts
+	MaxStaleness      *string `json:"maxStaleness"`      // duration string, defaults to DefaultOnchainMaxStaleness
 }
 
 type ChainHelper interface {
@@ -1,28 +1,159 @@
 package fetcher
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"bisonai.com/miko/node/pkg/db"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
-	"bisonai.com/miko/node/pkg/utils/calculator"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"bisonai.com/miko/node/pkg/utils/fxcalendar"
 	"bisonai.com/miko/node/pkg/utils/reducer"
 	"bisonai.com/miko/node/pkg/utils/request"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/rs/zerolog/log"
 )
 
-func FetchSingle(ctx context.Context, definition *Definition) (float64, error) {
-	rawResult, err := request.Request[interface{}](
-		request.WithEndpoint(*definition.Url),
-		request.WithHeaders(definition.Headers),
-		request.WithTimeout(10*time.Second),
-	)
+func FetchSingle(ctx context.Context, definition *Definition) (*big.Int, error) {
+	opts, err := definition.requestOptions(time.Now())
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	rawResult, err := request.Request[interface{}](append(opts, request.WithTimeout(10*time.Second))...)
+	if err != nil {
+		return nil, err
+	}
+	return reduceValue(rawResult, definition.Reducers)
+}
+
+// reduceValue reduces a response exactly and rounds the result once into an integer feed value
+func reduceValue(rawResult interface{}, reducers []reducer.Reducer) (*big.Int, error) {
+	value, err := reducer.ReduceDecimal(rawResult, reducers)
+	if err != nil {
+		return nil, err
+	}
+	return decimal.Round(value), nil
+}
+
+// dryRun fetches a http definition once without storing anything, used to check definitions before adding them
+func dryRun(ctx context.Context, rawDefinition string) (*big.Int, error) {
+	definition := new(Definition)
+	err := json.Unmarshal([]byte(rawDefinition), definition)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Type != nil {
+		return nil, errorSentinel.ErrFetcherInvalidType
+	}
+	return FetchSingle(ctx, definition)
+}
+
+// requestOptions turns the http part of a definition into request options, placeholders in
+// body strings and graphql variables are expanded on every request:
+// {timestamp} and {timestampMs} unix time, {date} as 2006-01-02 and {datetime} as RFC3339, both in UTC.
+// A string holding nothing but {timestamp} or {timestampMs} is replaced with a number.
+func (d *Definition) requestOptions(now time.Time) ([]request.RequestOption, error) {
+	if d.Url == nil {
+		return nil, errorSentinel.ErrFetcherInvalidInput
+	}
+
+	opts := []request.RequestOption{
+		request.WithEndpoint(*d.Url),
+		request.WithHeaders(d.Headers),
+		request.WithAuth(d.Auth),
+		// prices are reduced as exact decimals, float64 would already round them while decoding
+		request.WithUseNumber(),
+	}
+
+	hasBody := len(d.Body) > 0 && string(d.Body) != "null"
+	if hasBody && d.GraphQL != nil {
+		log.Error().Str("Player", "Fetcher").Str("url", *d.Url).Msg("definition sets both body and graphql")
+		return nil, errorSentinel.ErrFetcherInvalidRequestBody
+	}
+
+	method := http.MethodGet
+	if hasBody || d.GraphQL != nil {
+		method = http.MethodPost
+	}
+	if d.Method != nil && *d.Method != "" {
+		method = strings.ToUpper(*d.Method)
+	}
+	opts = append(opts, request.WithMethod(method))
+
+	contentType := request.DefaultContentType
+	if d.ContentType != nil && *d.ContentType != "" {
+		contentType = *d.ContentType
+		opts = append(opts, request.WithContentType(contentType))
+	}
+
+	placeholders := map[string]string{
+		"timestamp":   strconv.FormatInt(now.Unix(), 10),
+		"timestampMs": strconv.FormatInt(now.UnixMilli(), 10),
+		"date":        now.UTC().Format(time.DateOnly),
+		"datetime":    now.UTC().Format(time.RFC3339),
+	}
+
+	switch {
+	case d.GraphQL != nil:
+		query := GraphQLQuery{Query: d.GraphQL.Query, OperationName: d.GraphQL.OperationName}
+		if d.GraphQL.Variables != nil {
+			query.Variables = expandBodyTemplate(d.GraphQL.Variables, placeholders).(map[string]any)
+		}
+		opts = append(opts, request.WithBody(query))
+	case hasBody:
+		decoder := json.NewDecoder(bytes.NewReader(d.Body))
+		decoder.UseNumber()
+		var body any
+		err := decoder.Decode(&body)
+		if err != nil {
+			log.Error().Str("Player", "Fetcher").Err(err).Str("url", *d.Url).Msg("failed to decode request body")
+			return nil, errorSentinel.ErrFetcherInvalidRequestBody
+		}
+
+		body = expandBodyTemplate(body, placeholders)
+		if raw, ok := body.(string); ok && !strings.Contains(strings.ToLower(contentType), "json") {
+			opts = append(opts, request.WithRawBody([]byte(raw)))
+		} else {
+			opts = append(opts, request.WithBody(body))
+		}
+	}
+
+	return opts, nil
+}
+
+// expandBodyTemplate returns a copy of value with placeholders replaced, definitions are reused across requests
+func expandBodyTemplate(value any, placeholders map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		if v == "{timestamp}" || v == "{timestampMs}" {
+			return json.Number(placeholders[strings.Trim(v, "{}")])
+		}
+		for key, placeholder := range placeholders {
+			v = strings.ReplaceAll(v, "{"+key+"}", placeholder)
+		}
+		return v
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = expandBodyTemplate(item, placeholders)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = expandBodyTemplate(item, placeholders)
+		}
+		return result
+	default:
+		return v
 	}
-	return reducer.Reduce(rawResult, definition.Reducers)
 }
 
 func copyFeedData(ctx context.Context, feedData []*FeedData) error {
@@ -31,47 +162,58 @@ func copyFeedData(ctx context.Context, feedData []*FeedData) error {
 	}
 	insertRows := make([][]any, len(feedData))
 	for i, data := range feedData {
-		insertRows[i] = []any{data.FeedID, data.Value, data.Timestamp, data.Volume}
+		insertRows[i] = []any{data.FeedID, numeric(data.Value), data.Timestamp, data.Volume, data.Spread}
 	}
-	_, err := db.BulkCopy(ctx, "feed_data", []string{"feed_id", "value", "timestamp", "volume"}, insertRows)
+	_, err := db.BulkCopy(ctx, "feed_data", []string{"feed_id", "value", "timestamp", "volume", "spread"}, insertRows)
 	return err
 }
 
-func calculateVWAP(feedData []*FeedData) (float64, error) {
+// numeric stores exact values into NUMERIC columns, pgx does not encode *big.Int itself
+func numeric(value *big.Int) pgtype.Numeric {
+	return pgtype.Numeric{Int: value, Valid: value != nil}
+}
+
+// calculateVWAP sums exactly, so the result does not depend on the order feeds were collected in
+func calculateVWAP(feedData []*FeedData) (*big.Rat, error) {
 	if len(feedData) == 0 {
 		log.Debug().Str("Player", "Fetcher").Msg("no feed data to calculate VWAP")
-		return 0, nil
+		return new(big.Rat), nil
 	}
 
-	totalPrice := 0.0
-	totalVolume := 0.0
+	totalPrice := new(big.Rat)
+	totalVolume := new(big.Rat)
 	for _, data := range feedData {
-		totalPrice += data.Value * data.Volume
-		totalVolume += data.Volume
+		value := new(big.Rat).SetInt(data.Value)
+		volume, err := decimal.FromFloat64(data.Volume)
+		if err != nil {
+			return nil, err
+		}
+		totalPrice.Add(totalPrice, value.Mul(value, volume))
+		totalVolume.Add(totalVolume, volume)
 	}
 
-	if totalVolume == 0 {
+	if totalVolume.Sign() == 0 {
 		log.Debug().Str("Player", "Fetcher").Msg("total volume is zero to calculate VWAP")
-		return 0, errorSentinel.ErrLocalAggregatorZeroVolume
+		return nil, errorSentinel.ErrLocalAggregatorZeroVolume
 	}
 
-	return totalPrice / totalVolume, nil
+	return totalPrice.Quo(totalPrice, totalVolume), nil
 }
 
-func calculateMedian(feedData []*FeedData) (float64, error) {
+func calculateMedian(feedData []*FeedData) (*big.Rat, error) {
 	if len(feedData) == 0 {
 		log.Debug().Str("Player", "Fetcher").Msg("no feed data to calculate median, probably because there are only vwap feeds")
-		return 0, nil
+		return new(big.Rat), nil
 	}
 
-	prices := []float64{}
-	for _, data := range feedData {
-		prices = append(prices, data.Value)
+	prices := make([]*big.Rat, len(feedData))
+	for i, data := range feedData {
+		prices[i] = new(big.Rat).SetInt(data.Value)
 	}
 
-	return calculator.GetFloatMed(prices)
+	return decimal.Median(prices), nil
 }
 
 func isFXPricePair(name string) bool {
-	return strings.Contains(ForeignExchangePricePairs, name)
+	return fxcalendar.IsFXPricePair(name)
 }
@@ -4,6 +4,7 @@ package fetcher
 import (
 	"context"
 	"encoding/json"
+	"math/big"
 	"testing"
 	"time"
 
@@ -102,7 +103,62 @@ func TestFetchSingle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error fetching single: %v", err)
 	}
-	assert.Greater(t, result, float64(0))
+	assert.Equal(t, 1, result.Sign())
+}
+
+func TestFetchSingleWithBody(t *testing.T) {
+	ctx := context.Background()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil || req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case body["query"] != nil:
+			variables := body["variables"].(map[string]any)
+			if _, ok := variables["since"].(float64); !ok || variables["pair"] != "ADA-USDT" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			rw.Write([]byte(`{"data": {"price": "0.4676"}}`))
+		case body["method"] == "getPrice":
+			rw.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0.4676"}`))
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer mockServer.Close()
+
+	for name, rawDefinition := range map[string]string{
+		"graphql": `{
+			"url": "` + mockServer.URL + `",
+			"graphql": {"query": "query ($pair: String!, $since: Int!) { price(pair: $pair, since: $since) }", "variables": {"pair": "ADA-USDT", "since": "{timestamp}"}},
+			"reducers": [{"function": "PARSE", "args": ["data", "price"]}, {"function": "POW10", "args": 8}, {"function": "ROUND"}]
+		}`,
+		"json rpc": `{
+			"url": "` + mockServer.URL + `",
+			"method": "post",
+			"body": {"jsonrpc": "2.0", "id": 1, "method": "getPrice", "params": ["ADA-USDT", "{date}"]},
+			"reducers": [{"function": "PARSE", "args": ["result"]}, {"function": "POW10", "args": 8}, {"function": "ROUND"}]
+		}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			definition := new(Definition)
+			err := json.Unmarshal([]byte(rawDefinition), &definition)
+			if err != nil {
+				t.Fatalf("error unmarshalling definition: %v", err)
+			}
+
+			result, err := FetchSingle(ctx, definition)
+			if err != nil {
+				t.Fatalf("error fetching single: %v", err)
+			}
+			assert.Equal(t, big.NewInt(46760000), result)
+		})
+	}
 }
 
 func TestCopyFeedData(t *testing.T) {
@@ -124,20 +180,28 @@ func TestCopyFeedData(t *testing.T) {
 		now := time.Now().Round(time.Second)
 		feedData = append(feedData, &FeedData{
 			FeedID:    int32(*feed.ID),
-			Value:     float64(i) + 5,
+			Value:     big.NewInt(int64(i) + 5),
 			Timestamp: &now,
 		})
 	}
 
+	spread := 0.001
+	feedData[0].Spread = &spread
+
 	err = copyFeedData(ctx, feedData)
 	if err != nil {
 		t.Fatalf("error copying feed data: %v", err)
 	}
 
 	defer db.QueryWithoutResult(ctx, "DELETE FROM feed_data", nil)
-	result, err := db.QueryRows[FeedData](ctx, "SELECT * FROM feed_data", nil)
+	type storedFeedData struct {
+		FeedID int32    `db:"feed_id"`
+		Value  string   `db:"value"`
+		Spread *float64 `db:"spread"`
+	}
+	result, err := db.QueryRows[storedFeedData](ctx, "SELECT feed_id, value::TEXT AS value, spread FROM feed_data", nil)
 	if err != nil {
 		t.Fatalf("error getting feed data: %v", err)
 	}
-	assert.Contains(t, result, *feedData[0])
+	assert.Contains(t, result, storedFeedData{FeedID: feedData[0].FeedID, Value: feedData[0].Value.String(), Spread: &spread})
 }
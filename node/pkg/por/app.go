@@ -148,6 +148,7 @@ func (a *App) Execute(ctx context.Context) error {
 	value, err := a.Fetch(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("error in fetch")
+		return err
 	}
 	log.Debug().Msg("fetched value")
 
@@ -178,11 +179,11 @@ func (a *App) Execute(ctx context.Context) error {
 	return nil
 }
 
-func (a *App) Fetch(ctx context.Context) (float64, error) {
+func (a *App) Fetch(ctx context.Context) (*big.Int, error) {
 	return fetcher.FetchSingle(ctx, a.Definition)
 }
 
-func (a *App) ShouldReport(lastInfo *LastInfo, value float64, fetchedTime time.Time) bool {
+func (a *App) ShouldReport(lastInfo *LastInfo, value *big.Int, fetchedTime time.Time) bool {
 	if lastInfo.UpdatedAt.Sign() == 0 && lastInfo.Answer.Sign() == 0 {
 		return true
 	}
@@ -197,20 +198,16 @@ func (a *App) ShouldReport(lastInfo *LastInfo, value float64, fetchedTime time.T
 		return true
 	}
 
-	lastSubmittedValue := new(big.Float).SetInt(lastInfo.Answer)
-	float64Value, _ := lastSubmittedValue.Float64()
+	lastSubmittedValue, _ := new(big.Float).SetInt(lastInfo.Answer).Float64()
+	fetchedValue, _ := new(big.Float).SetInt(value).Float64()
 
-	return a.DeviationCheck(float64Value, value)
+	return a.DeviationCheck(lastSubmittedValue, fetchedValue)
 }
 
-func (a *App) report(ctx context.Context, submissionValue float64, latestRoundId uint32) error {
-	tmp := new(big.Float).SetFloat64(submissionValue)
-	submissionValueParam := new(big.Int)
-	tmp.Int(submissionValueParam)
-
+func (a *App) report(ctx context.Context, submissionValue *big.Int, latestRoundId uint32) error {
 	latestRoundIdParam := new(big.Int).SetUint64(uint64(latestRoundId))
 
-	tx, err := a.KaiaHelper.MakeDirectTx(ctx, a.ContractAddress, SUBMIT_FUNCTION_STRING, latestRoundIdParam, submissionValueParam)
+	tx, err := a.KaiaHelper.MakeDirectTx(ctx, a.ContractAddress, SUBMIT_FUNCTION_STRING, latestRoundIdParam, submissionValue)
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,9 @@ import (
 )
 
 const (
+	// DECIMALS is deliberately not taken from per config decimals: por reads its adapter from the config repository
+	// rather than the configs table, its reducers scale into 4 decimals and the aggregator contract it submits to
+	// has its decimals fixed at deployment. Changing it needs a new aggregator, not a config update.
 	DECIMALS            = 4
 	DEVIATION_THRESHOLD = 0.0001
 	ABSOLUTE_THRESHOLD  = 0.1
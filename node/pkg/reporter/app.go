@@ -7,18 +7,18 @@ import (
 	"sync"
 
 	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/secrets"
 	"bisonai.com/miko/node/pkg/utils/request"
-	"github.com/klaytn/klaytn/common"
 	"github.com/rs/zerolog/log"
 )
 
 func New() *App {
 	return &App{
-		Reporters:              []*Reporter{},
-		LatestDataMap:          new(sync.Map),
-		LatestSubmittedDataMap: new(sync.Map),
+		Reporters:     []*Reporter{},
+		Targets:       []*Target{},
+		LatestDataMap: new(sync.Map),
 	}
 }
 
@@ -45,23 +45,12 @@ func (a *App) setReporters(ctx context.Context) error {
 		dalWsEndpoint = "ws://orakl-dal.orakl.svc.cluster.local/ws"
 	}
 
-	contractAddress := os.Getenv("SUBMISSION_PROXY_CONTRACT")
-	if contractAddress == "" {
-		return errorSentinel.ErrReporterSubmissionProxyContractNotFound
-	}
-
-	chainHelper, err := helper.NewChainHelper(ctx)
+	targetConfigs, err := LoadTargetConfigs()
 	if err != nil {
-		log.Error().Str("Player", "Reporter").Err(err).Msg("failed to create chain helper")
+		log.Error().Str("Player", "Reporter").Err(err).Msg("failed to load reporter targets")
 		return err
 	}
 
-	cachedWhitelist, err := ReadOnchainWhitelist(ctx, chainHelper, contractAddress, GET_ONCHAIN_WHITELIST)
-	if err != nil {
-		log.Error().Str("Player", "Reporter").Err(err).Msg("failed to get whitelist, starting with empty whitelist")
-		cachedWhitelist = []common.Address{}
-	}
-
 	configs, err := fetchConfigs()
 	if err != nil {
 		log.Error().Str("Player", "Reporter").Err(err).Msg("failed to get reporter configs")
@@ -74,48 +63,113 @@ func (a *App) setReporters(ctx context.Context) error {
 	}
 	a.WsHelper = dalWsHelper
 
-	groupedConfigs := groupConfigsBySubmitIntervals(configs)
+	// a target failing to set up is left out, the other chains keep reporting
+	for _, targetConfig := range targetConfigs {
+		target, errNewTarget := a.newTarget(ctx, targetConfig, configs)
+		if errNewTarget != nil {
+			log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(errNewTarget).Msg("failed to set target, skipping")
+			continue
+		}
+		a.Targets = append(a.Targets, target)
+		a.Reporters = append(a.Reporters, target.Reporters...)
+	}
+	if len(a.Targets) == 0 {
+		log.Error().Str("Player", "Reporter").Msg("no targets set")
+		return errorSentinel.ErrReporterNoTargetsSet
+	}
+
+	log.Info().Str("Player", "Reporter").Msgf("%d reporters set for %d targets", len(a.Reporters), len(a.Targets))
+	return nil
+}
+
+func (a *App) newTarget(ctx context.Context, targetConfig TargetConfig, configs []Config) (*Target, error) {
+	chainHelperOptions, err := targetConfig.ChainHelperOptions(secrets.GetSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	chainHelper, err := helper.NewChainHelper(ctx, chainHelperOptions...)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(err).Msg("failed to create chain helper")
+		return nil, err
+	}
+
+	targetConfigs := targetConfig.ApplyTo(configs)
+	if len(targetConfigs) == 0 {
+		chainHelper.Close()
+		return nil, errorSentinel.ErrReporterEmptyConfigs
+	}
+
+	target := &Target{
+		Config:                 targetConfig,
+		ChainHelper:            chainHelper,
+		ProofVerifier:          NewProofVerifier(targetConfig.Name, chainHelper, targetConfig.ContractAddress, nil),
+		LatestSubmittedDataMap: new(sync.Map),
+	}
+	err = target.ProofVerifier.Refresh(ctx)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(err).Msg("failed to load whitelist, submitting unverified proofs until it loads")
+	}
+	trackOracleAdded(ctx, targetConfig, target.ProofVerifier)
+
+	commonOptions := []ReporterOption{
+		WithTarget(targetConfig.Name),
+		WithContractAddress(targetConfig.ContractAddress),
+		WithProofVerifier(target.ProofVerifier),
+		WithKaiaHelper(chainHelper),
+		WithLatestDataMap(a.LatestDataMap),
+		WithLatestSubmittedDataMap(target.LatestSubmittedDataMap),
+		WithBatchGasBudget(targetConfig.BatchGasBudget),
+		WithDeviationThreshold(targetConfig.DeviationThreshold),
+	}
+
+	groupedConfigs := groupConfigsBySubmitIntervals(targetConfigs)
 	for groupInterval, configs := range groupedConfigs {
 		reporter, errNewReporter := NewReporter(
 			ctx,
-			WithConfigs(configs),
-			WithInterval(groupInterval),
-			WithContractAddress(contractAddress),
-			WithCachedWhitelist(cachedWhitelist),
-			WithKaiaHelper(chainHelper),
-			WithLatestDataMap(a.LatestDataMap),
-			WithLatestSubmittedDataMap(a.LatestSubmittedDataMap),
+			append([]ReporterOption{WithConfigs(configs), WithInterval(groupInterval)}, commonOptions...)...,
 		)
 		if errNewReporter != nil {
-			log.Error().Str("Player", "Reporter").Err(errNewReporter).Msg("failed to set reporter")
-			return errNewReporter
+			log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(errNewReporter).Msg("failed to set reporter")
+			chainHelper.Close()
+			return nil, errNewReporter
 		}
-		a.Reporters = append(a.Reporters, reporter)
-	}
-	if len(a.Reporters) == 0 {
-		log.Error().Str("Player", "Reporter").Msg("no reporters set")
-		return errorSentinel.ErrReporterNotFound
+		target.Reporters = append(target.Reporters, reporter)
 	}
 
 	deviationReporter, errNewDeviationReporter := NewReporter(
 		ctx,
-		WithConfigs(configs),
-		WithInterval(DEVIATION_INTERVAL),
-		WithContractAddress(contractAddress),
-		WithCachedWhitelist(cachedWhitelist),
-		WithJobType(DeviationJob),
-		WithKaiaHelper(chainHelper),
-		WithLatestDataMap(a.LatestDataMap),
-		WithLatestSubmittedDataMap(a.LatestSubmittedDataMap),
+		append([]ReporterOption{WithConfigs(targetConfigs), WithInterval(DEVIATION_INTERVAL), WithJobType(DeviationJob)}, commonOptions...)...,
 	)
 	if errNewDeviationReporter != nil {
-		log.Error().Str("Player", "Reporter").Err(errNewDeviationReporter).Msg("failed to set deviation reporter")
-		return errNewDeviationReporter
+		log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(errNewDeviationReporter).Msg("failed to set deviation reporter")
+		chainHelper.Close()
+		return nil, errNewDeviationReporter
 	}
-	a.Reporters = append(a.Reporters, deviationReporter)
+	target.Reporters = append(target.Reporters, deviationReporter)
 
-	log.Info().Str("Player", "Reporter").Msgf("%d reporters set", len(a.Reporters))
-	return nil
+	return target, nil
+}
+
+// trackOracleAdded watches the submission proxy of the target for new oracles, targets without a websocket url
+// only refresh their whitelist when proofs are signed by unknown or expired signers
+func trackOracleAdded(ctx context.Context, targetConfig TargetConfig, verifier *ProofVerifier) {
+	websocketUrl, chainType, opt := targetConfig.WebsocketChainReader()
+	if websocketUrl == "" {
+		log.Warn().Str("Player", "Reporter").Str("Target", targetConfig.Name).Msg("no websocket url, OracleAdded events are not tracked")
+		return
+	}
+
+	chainReader, err := websocketchainreader.New(opt)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(err).Msg("failed to create chain reader")
+		return
+	}
+
+	err = verifier.TrackOracleAdded(ctx, chainReader, chainType)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Str("Target", targetConfig.Name).Err(err).Msg("failed to subscribe to OracleAdded events")
+	}
 }
 
 func (a *App) startReporters(ctx context.Context) {
@@ -143,7 +197,7 @@ func fetchConfigs() ([]Config, error) {
 func groupConfigsBySubmitIntervals(reporterConfigs []Config) map[int][]Config {
 	grouped := make(map[int][]Config)
 	for _, sa := range reporterConfigs {
-		var interval = 5000
+		var interval = DEFAULT_SUBMIT_INTERVAL
 		if sa.SubmitInterval != nil && *sa.SubmitInterval > 0 {
 			interval = *sa.SubmitInterval
 		}
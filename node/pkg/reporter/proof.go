@@ -0,0 +1,311 @@
+package reporter
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/helper"
+	chainutils "bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	klaytntypes "github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/rs/zerolog/log"
+)
+
+// NewProofVerifier starts from the given whitelist without expirations until it is refreshed, the
+// default threshold of the contract is assumed until then
+func NewProofVerifier(target string, chainHelper *helper.ChainHelper, contractAddress string, whitelist []common.Address) *ProofVerifier {
+	return &ProofVerifier{
+		target:           target,
+		chainHelper:      chainHelper,
+		contractAddress:  contractAddress,
+		loaded:           len(whitelist) > 0,
+		oracles:          whitelist,
+		expirations:      map[common.Address]time.Time{},
+		defaultThreshold: DEFAULT_PROOF_THRESHOLD,
+		thresholds:       map[[32]byte]uint8{},
+	}
+}
+
+// Refresh reads oracles, their expirations and the default threshold from the contract, feed thresholds are read again on demand
+func (v *ProofVerifier) Refresh(ctx context.Context) error {
+	if v.chainHelper == nil {
+		return errorSentinel.ErrReporterKaiaHelperNotFound
+	}
+
+	oracles, err := ReadOnchainWhitelist(ctx, v.chainHelper, v.contractAddress, GET_ONCHAIN_WHITELIST)
+	if err != nil {
+		return err
+	}
+
+	expirations := make(map[common.Address]time.Time, len(oracles))
+	for _, oracle := range oracles {
+		expiration, readErr := v.readExpiration(ctx, oracle)
+		if readErr != nil {
+			return readErr
+		}
+		expirations[oracle] = expiration
+	}
+
+	defaultThreshold, err := v.readThreshold(ctx, GET_DEFAULT_THRESHOLD)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	v.loaded = true
+	v.oracles = oracles
+	v.expirations = expirations
+	v.defaultThreshold = defaultThreshold
+	v.thresholds = map[[32]byte]uint8{}
+	v.lastRefresh = time.Now()
+	v.mu.Unlock()
+
+	log.Info().Str("Player", "Reporter").Str("Target", v.target).Int("oracles", len(oracles)).Uint8("defaultThreshold", defaultThreshold).Msg("whitelist refreshed")
+	return nil
+}
+
+// Verify returns the pairs whose proofs the contract would accept. Dropped pairs are not recorded as submitted, they are
+// reported again once a valid proof comes in. Unknown or expired signers refresh the whitelist in the background.
+func (v *ProofVerifier) Verify(ctx context.Context, pairs map[string]SubmissionData) map[string]SubmissionData {
+	v.mu.RLock()
+	loaded, oracles, expirations := v.loaded, v.oracles, v.expirations
+	v.mu.RUnlock()
+
+	if !loaded {
+		log.Warn().Str("Player", "Reporter").Str("Target", v.target).Msg("whitelist not loaded, submitting unverified proofs")
+		v.refreshInBackground(ctx)
+		return pairs
+	}
+
+	now := time.Now()
+	staleWhitelist := false
+	verified := make(map[string]SubmissionData, len(pairs))
+	for pair, submissionData := range pairs {
+		check, err := CheckProof(submissionData, oracles, expirations, v.threshold(ctx, submissionData.FeedHash), now)
+		if check.Unknown > 0 || check.Expired > 0 {
+			staleWhitelist = true
+		}
+		if err != nil {
+			log.Warn().Str("Player", "Reporter").Str("Target", v.target).Str("pair", pair).Err(err).
+				Int("valid", check.Valid).Int("required", check.Required).Int("unknown", check.Unknown).Int("expired", check.Expired).
+				Msg("dropping submission with invalid proof")
+			continue
+		}
+		verified[pair] = submissionData
+	}
+
+	if staleWhitelist {
+		v.refreshInBackground(ctx)
+	}
+	log.Debug().Str("Player", "Reporter").Str("Target", v.target).Int("verified", len(verified)).Int("dropped", len(pairs)-len(verified)).Msg("proofs verified")
+	return verified
+}
+
+// TrackOracleAdded refreshes the whitelist whenever the contract emits OracleAdded
+func (v *ProofVerifier) TrackOracleAdded(ctx context.Context, chainReader *websocketchainreader.ChainReader, chainType websocketchainreader.BlockchainType) error {
+	eventName, input, _, err := chainutils.ParseMethodSignature(ORACLE_ADDED_EVENT)
+	if err != nil {
+		return err
+	}
+	oracleAddedEventABI, err := chainutils.GenerateEventABI(eventName, input)
+	if err != nil {
+		return err
+	}
+	eventID := oracleAddedEventABI.Events[eventName].ID
+
+	logChannel := make(chan klaytntypes.Log)
+	err = chainReader.Subscribe(
+		ctx,
+		websocketchainreader.WithAddress(v.contractAddress),
+		websocketchainreader.WithChainType(chainType),
+		websocketchainreader.WithChannel(logChannel),
+	)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case eventLog := <-logChannel:
+				if len(eventLog.Topics) == 0 || eventLog.Topics[0] != eventID {
+					continue
+				}
+				result, unpackErr := oracleAddedEventABI.Unpack(eventName, eventLog.Data)
+				if unpackErr != nil || len(result) == 0 {
+					log.Error().Str("Player", "Reporter").Str("Target", v.target).Err(unpackErr).Msg("failed to unpack OracleAdded event")
+					continue
+				}
+				oracle, _ := result[0].(common.Address)
+				log.Info().Str("Player", "Reporter").Str("Target", v.target).Str("oracle", oracle.Hex()).Msg("oracle added, refreshing whitelist")
+
+				refreshErr := v.Refresh(ctx)
+				if refreshErr != nil {
+					log.Error().Str("Player", "Reporter").Str("Target", v.target).Err(refreshErr).Msg("failed to refresh whitelist")
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// refreshInBackground refreshes the whitelist unless a refresh is running or happened recently
+func (v *ProofVerifier) refreshInBackground(ctx context.Context) {
+	v.mu.RLock()
+	recent := time.Since(v.lastRefresh) < MIN_WHITELIST_REFRESH_INTERVAL
+	v.mu.RUnlock()
+	if recent || v.chainHelper == nil || !v.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer v.refreshing.Store(false)
+		err := v.Refresh(ctx)
+		if err != nil {
+			log.Error().Str("Player", "Reporter").Str("Target", v.target).Err(err).Msg("failed to refresh whitelist")
+			v.mu.Lock()
+			v.lastRefresh = time.Now()
+			v.mu.Unlock()
+		}
+	}()
+}
+
+// threshold of the feed, the default threshold applies to feeds without their own or when it can not be read
+func (v *ProofVerifier) threshold(ctx context.Context, feedHash [32]byte) uint8 {
+	v.mu.RLock()
+	threshold, ok := v.thresholds[feedHash]
+	defaultThreshold := v.defaultThreshold
+	v.mu.RUnlock()
+	if ok {
+		return threshold
+	}
+	if v.chainHelper == nil {
+		return defaultThreshold
+	}
+
+	threshold, err := v.readThreshold(ctx, GET_THRESHOLD, feedHash)
+	if err != nil {
+		log.Debug().Str("Player", "Reporter").Str("Target", v.target).Err(err).Msg("failed to read feed threshold, using default")
+		return defaultThreshold
+	}
+	if threshold == 0 {
+		threshold = defaultThreshold
+	}
+
+	v.mu.Lock()
+	v.thresholds[feedHash] = threshold
+	v.mu.Unlock()
+	return threshold
+}
+
+func (v *ProofVerifier) readThreshold(ctx context.Context, functionString string, args ...interface{}) (uint8, error) {
+	result, err := v.chainHelper.ReadContract(ctx, v.contractAddress, functionString, args...)
+	if err != nil {
+		return 0, err
+	}
+	values, ok := result.([]interface{})
+	if !ok || len(values) == 0 {
+		return 0, errorSentinel.ErrChainFailedToParseContractResult
+	}
+	threshold, ok := values[0].(uint8)
+	if !ok {
+		return 0, errorSentinel.ErrChainFailedToParseContractResult
+	}
+	return threshold, nil
+}
+
+func (v *ProofVerifier) readExpiration(ctx context.Context, oracle common.Address) (time.Time, error) {
+	result, err := v.chainHelper.ReadContract(ctx, v.contractAddress, helper.SignerDetailFuncSignature, oracle)
+	if err != nil {
+		return time.Time{}, err
+	}
+	values, ok := result.([]interface{})
+	if !ok || len(values) < 2 {
+		return time.Time{}, errorSentinel.ErrChainFailedToParseContractResult
+	}
+	expiration, ok := values[1].(*big.Int)
+	if !ok {
+		return time.Time{}, errorSentinel.ErrChainFailedToParseContractResult
+	}
+	return time.Unix(expiration.Int64(), 0), nil
+}
+
+// RecoverProofSigners recovers the signer of every signature concatenated in the proof, the zero address for
+// signatures which can not be recovered
+func RecoverProofSigners(submissionData SubmissionData) ([]common.Address, error) {
+	if len(submissionData.Proof) == 0 || len(submissionData.Proof)%PROOF_LENGTH != 0 {
+		return nil, errorSentinel.ErrReporterInvalidProofLength
+	}
+
+	hash := chainutils.Value2HashForSignWithDecimals(submissionData.Value, submissionData.AggregateTime, submissionData.Symbol, submissionData.Decimals)
+	signers := make([]common.Address, 0, len(submissionData.Proof)/PROOF_LENGTH)
+	for i := 0; i < len(submissionData.Proof); i += PROOF_LENGTH {
+		signer, err := chainutils.RecoverSigner(hash, submissionData.Proof[i:i+PROOF_LENGTH])
+		if err != nil {
+			signer = common.Address{}
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// CheckProof mirrors validateProof of the submission proxy: signers have to follow the order of the oracles, which
+// reverts the whole submission otherwise, and the whitelisted, unexpired ones have to reach the quorum of the threshold.
+// Oracles without a known expiration count as unexpired.
+func CheckProof(submissionData SubmissionData, oracles []common.Address, expirations map[common.Address]time.Time, threshold uint8, now time.Time) (ProofCheck, error) {
+	check := ProofCheck{Required: Quorum(len(oracles), threshold)}
+	signers, err := RecoverProofSigners(submissionData)
+	if err != nil {
+		return check, err
+	}
+	if len(oracles) == 0 {
+		return check, errorSentinel.ErrReporterProofBelowThreshold
+	}
+
+	lastIndex := 0
+	for i, signer := range signers {
+		if signer == (common.Address{}) {
+			continue
+		}
+
+		index := slices.Index(oracles, signer)
+		whitelisted := index >= 0
+		if !whitelisted {
+			check.Unknown++
+			index = 0
+		}
+		if i != 0 && index <= lastIndex {
+			return check, errorSentinel.ErrReporterProofSignersNotAscending
+		}
+		lastIndex = index
+
+		if !whitelisted {
+			continue
+		}
+		if expiration, ok := expirations[signer]; ok && !expiration.After(now) {
+			check.Expired++
+			continue
+		}
+		check.Valid++
+	}
+
+	if check.Valid < check.Required {
+		return check, errorSentinel.ErrReporterProofBelowThreshold
+	}
+	return check, nil
+}
+
+// Quorum is the number of signatures a threshold percentage of the oracles requires, rounded up
+func Quorum(oracles int, threshold uint8) int {
+	nominator := oracles * int(threshold)
+	if nominator%100 == 0 {
+		return nominator / 100
+	}
+	return nominator/100 + 1
+}
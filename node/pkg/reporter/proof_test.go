@@ -0,0 +1,141 @@
+//nolint:all
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	chainutils "bisonai.com/miko/node/pkg/chain/utils"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testOracles(t *testing.T, count int) ([]*ecdsa.PrivateKey, []common.Address) {
+	pks := make([]*ecdsa.PrivateKey, count)
+	addresses := make([]common.Address, count)
+	for i := range pks {
+		pk, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("error generating key: %v", err)
+		}
+		pks[i] = pk
+		addresses[i] = crypto.PubkeyToAddress(pk.PublicKey)
+	}
+	return pks, addresses
+}
+
+func signedSubmissionData(t *testing.T, decimals int32, pks ...*ecdsa.PrivateKey) SubmissionData {
+	submissionData := SubmissionData{Symbol: "BTC-USDT", Value: 6500000000000, AggregateTime: 1717200000000, Decimals: decimals}
+	proofs := [][]byte{}
+	for _, pk := range pks {
+		signature, err := chainutils.MakeValueSignatureWithDecimals(submissionData.Value, submissionData.AggregateTime, submissionData.Symbol, decimals, pk)
+		if err != nil {
+			t.Fatalf("error signing value: %v", err)
+		}
+		proofs = append(proofs, signature)
+	}
+	submissionData.Proof = bytes.Join(proofs, nil)
+	return submissionData
+}
+
+func TestQuorum(t *testing.T) {
+	assert.Equal(t, 2, Quorum(3, 50))
+	assert.Equal(t, 2, Quorum(4, 50))
+	assert.Equal(t, 1, Quorum(1, 1))
+	assert.Equal(t, 4, Quorum(4, 100))
+	assert.Equal(t, 0, Quorum(0, 50))
+}
+
+func TestCheckProof(t *testing.T) {
+	pks, oracles := testOracles(t, 4)
+	now := time.Now()
+	expirations := map[common.Address]time.Time{
+		oracles[0]: now.Add(time.Hour),
+		oracles[1]: now.Add(time.Hour),
+		oracles[2]: now.Add(-time.Hour),
+		// oracles[3] seeded without an expiration
+	}
+
+	t.Run("quorum of whitelisted signers", func(t *testing.T) {
+		check, err := CheckProof(signedSubmissionData(t, 8, pks[0], pks[1]), oracles, expirations, 50, now)
+		assert.NoError(t, err)
+		assert.Equal(t, ProofCheck{Valid: 2, Required: 2}, check)
+
+		check, err = CheckProof(signedSubmissionData(t, 8, pks[1], pks[3]), oracles, expirations, 50, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, check.Valid)
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		check, err := CheckProof(signedSubmissionData(t, 8, pks[0]), oracles, expirations, 50, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterProofBelowThreshold)
+		assert.Equal(t, 1, check.Valid)
+
+		_, err = CheckProof(signedSubmissionData(t, 8, pks[0], pks[1]), oracles, expirations, 75, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterProofBelowThreshold)
+	})
+
+	t.Run("expired signer", func(t *testing.T) {
+		check, err := CheckProof(signedSubmissionData(t, 8, pks[0], pks[2]), oracles, expirations, 50, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterProofBelowThreshold)
+		assert.Equal(t, ProofCheck{Valid: 1, Required: 2, Expired: 1}, check)
+	})
+
+	t.Run("unknown signer", func(t *testing.T) {
+		unknownPks, _ := testOracles(t, 1)
+		check, err := CheckProof(signedSubmissionData(t, 8, unknownPks[0], pks[1], pks[3]), oracles, expirations, 50, now)
+		assert.NoError(t, err)
+		assert.Equal(t, ProofCheck{Valid: 2, Required: 2, Unknown: 1}, check)
+	})
+
+	t.Run("signers out of oracle order", func(t *testing.T) {
+		_, err := CheckProof(signedSubmissionData(t, 8, pks[1], pks[0]), oracles, expirations, 50, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterProofSignersNotAscending)
+	})
+
+	t.Run("proof signed for other decimals", func(t *testing.T) {
+		submissionData := signedSubmissionData(t, 6, pks[0], pks[1])
+		submissionData.Decimals = 8
+		check, err := CheckProof(submissionData, oracles, expirations, 50, now)
+		assert.Error(t, err)
+		assert.Zero(t, check.Valid)
+	})
+
+	t.Run("invalid proof length", func(t *testing.T) {
+		submissionData := signedSubmissionData(t, 8, pks[0], pks[1])
+		submissionData.Proof = submissionData.Proof[:100]
+		_, err := CheckProof(submissionData, oracles, expirations, 50, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterInvalidProofLength)
+	})
+
+	t.Run("empty whitelist", func(t *testing.T) {
+		_, err := CheckProof(signedSubmissionData(t, 8, pks[0], pks[1]), nil, nil, 50, now)
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterProofBelowThreshold)
+	})
+}
+
+func TestProofVerifierVerify(t *testing.T) {
+	ctx := context.Background()
+	pks, oracles := testOracles(t, 3)
+
+	pairs := map[string]SubmissionData{
+		"BTC-USDT": signedSubmissionData(t, 8, pks[0], pks[1]),
+		"ETH-USDT": signedSubmissionData(t, 8, pks[2]),
+	}
+
+	t.Run("drops proofs the contract would reject", func(t *testing.T) {
+		verified := NewProofVerifier("kaia", nil, "", oracles).Verify(ctx, pairs)
+		assert.Len(t, verified, 1)
+		assert.Contains(t, verified, "BTC-USDT")
+	})
+
+	t.Run("submits unverified until the whitelist loads", func(t *testing.T) {
+		verified := NewProofVerifier("kaia", nil, "", nil).Verify(ctx, pairs)
+		assert.Len(t, verified, 2)
+	})
+}
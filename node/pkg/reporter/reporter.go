@@ -3,7 +3,6 @@ package reporter
 import (
 	"context"
 	"errors"
-	"math/big"
 	"sync"
 	"time"
 
@@ -31,17 +30,33 @@ func NewReporter(ctx context.Context, opts ...ReporterOption) (*Reporter, error)
 
 	groupInterval := time.Duration(config.Interval) * time.Millisecond
 
-	deviationThreshold := GetDeviationThreshold(groupInterval)
+	deviationThreshold := config.DeviationThreshold
+	if deviationThreshold <= 0 {
+		deviationThreshold = GetDeviationThreshold(groupInterval)
+	}
+
+	verifier := config.ProofVerifier
+	if verifier == nil {
+		verifier = NewProofVerifier(config.Target, config.KaiaHelper, config.ContractAddress, config.CachedWhitelist)
+	}
+
+	batchGasBudget := config.BatchGasBudget
+	if batchGasBudget == 0 {
+		batchGasBudget = DEFAULT_BATCH_GAS_BUDGET
+	}
 
 	reporter := &Reporter{
+		target:                 config.Target,
 		contractAddress:        config.ContractAddress,
 		SubmissionInterval:     groupInterval,
-		CachedWhitelist:        config.CachedWhitelist,
 		deviationThreshold:     deviationThreshold,
+		verifier:               verifier,
 		KaiaHelper:             config.KaiaHelper,
 		LatestDataMap:          config.LatestDataMap,
 		LatestSubmittedDataMap: config.LatestSubmittedDataMap,
+		batchGasBudget:         batchGasBudget,
 	}
+	reporter.gasBudget.Store(batchGasBudget)
 
 	reporter.Pairs = make([]string, 0, len(config.Configs))
 	for _, sa := range config.Configs {
@@ -62,19 +77,19 @@ func NewReporter(ctx context.Context, opts ...ReporterOption) (*Reporter, error)
 }
 
 func (r *Reporter) Run(ctx context.Context) {
-	log.Info().Msgf("Reporter ticker starting with interval: %v", r.SubmissionInterval)
+	log.Info().Str("Target", r.target).Msgf("Reporter ticker starting with interval: %v", r.SubmissionInterval)
 	ticker := time.NewTicker(r.SubmissionInterval)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Debug().Str("Player", "Reporter").Msg("context done, stopping reporter")
+			log.Debug().Str("Player", "Reporter").Str("Target", r.target).Msg("context done, stopping reporter")
 			return
 		case <-ticker.C:
 			go func() {
 				err := r.Job()
 				if err != nil {
-					log.Error().Str("Player", "Reporter").Err(err).Msg("ReporterJob")
+					log.Error().Str("Player", "Reporter").Str("Target", r.target).Err(err).Msg("ReporterJob")
 				}
 			}()
 		}
@@ -99,7 +114,7 @@ func (r *Reporter) deviationJob(ctx context.Context) error {
 	if len(deviatingAggregates) == 0 {
 		return nil
 	}
-	log.Debug().Str("Player", "Reporter").Msgf("deviating aggregates found: %v", deviatingAggregates)
+	log.Debug().Str("Player", "Reporter").Str("Target", r.target).Msgf("deviating aggregates found: %v", deviatingAggregates)
 
 	err := r.report(ctx, deviatingAggregates)
 	if err != nil {
@@ -109,35 +124,23 @@ func (r *Reporter) deviationJob(ctx context.Context) error {
 }
 
 func (r *Reporter) report(ctx context.Context, pairs map[string]SubmissionData) error {
-	var feedHashes [][32]byte
-	var values []*big.Int
-	var timestamps []*big.Int
-	var proofs [][]byte
-	var submittedPairs []string
-
-	for pair, submissionData := range pairs {
-		feedHashes = append(feedHashes, submissionData.FeedHash)
-		values = append(values, big.NewInt(submissionData.Value))
-		timestamps = append(timestamps, big.NewInt(submissionData.AggregateTime))
-		proofs = append(proofs, submissionData.Proof)
-		submittedPairs = append(submittedPairs, pair)
+	pairs = r.verifier.Verify(ctx, pairs)
+	if len(pairs) == 0 {
+		log.Debug().Str("Player", "Reporter").Str("Target", r.target).Msg("no verified submissions to report")
+		return nil
 	}
 
-	dataLen := len(feedHashes)
-	wg := sync.WaitGroup{}
+	order := PrioritizeDeviating(pairs, r.LatestSubmittedDataMap, r.deviationThreshold)
+	batches := PackSubmissionBatches(order, pairs, r.gasBudget.Load())
 
-	errorsChan := make(chan error, 5)
-	for start := 0; start < dataLen; start += MAX_REPORT_BATCH_SIZE {
-		end := min(start+MAX_REPORT_BATCH_SIZE, dataLen)
+	wg := sync.WaitGroup{}
 
-		batchFeedHashes := feedHashes[start:end]
-		batchValues := values[start:end]
-		batchTimestamps := timestamps[start:end]
-		batchProofs := proofs[start:end]
+	errorsChan := make(chan error, len(batches))
+	for _, batch := range batches {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := r.KaiaHelper.SubmitDelegatedFallbackDirect(ctx, r.contractAddress, SUBMIT_WITH_PROOFS, batchFeedHashes, batchValues, batchTimestamps, batchProofs)
+			err := r.submitBatch(ctx, batch, pairs)
 			if err != nil {
 				errorsChan <- err
 			}
@@ -151,18 +154,14 @@ func (r *Reporter) report(ctx context.Context, pairs map[string]SubmissionData)
 	tmp := []error{}
 	for err := range errorsChan {
 		tmp = append(tmp, err)
-		if utils.IsNonceError(err) || errors.Is(err, context.DeadlineExceeded) {
-			log.Debug().Err(err).Str("Player", "Reporter").Msg("should refresh nonce")
+		if utils.IsNonceError(err) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errorSentinel.ErrChainTransactionDropped) {
+			log.Debug().Err(err).Str("Player", "Reporter").Str("Target", r.target).Msg("should refresh nonce")
 			shouldRefreshNonce = true
 		}
 	}
 
-	for i, pair := range submittedPairs {
-		r.LatestSubmittedDataMap.Store(pair, values[i].Int64())
-	}
-
 	if shouldRefreshNonce {
-		log.Debug().Str("Player", "Reporter").Msg("refreshing nonce pool")
+		log.Debug().Str("Player", "Reporter").Str("Target", r.target).Msg("refreshing nonce pool")
 		return r.KaiaHelper.FlushNoncePool(ctx)
 	}
 
@@ -170,11 +169,65 @@ func (r *Reporter) report(ctx context.Context, pairs map[string]SubmissionData)
 		return mergeErrors(tmp)
 	}
 
-	log.Debug().Str("Player", "Reporter").Msgf("reporting done for reporter with interval: %v", r.SubmissionInterval)
+	r.raiseGasBudget()
+	log.Debug().Str("Player", "Reporter").Str("Target", r.target).Msgf("reporting done for reporter with interval: %v", r.SubmissionInterval)
+
+	return nil
+}
+
+// submitBatch sends the batch and stores its values once confirmed. A batch running out of gas lowers the gas budget
+// below its estimate and is sent again, split into batches fitting the lowered budget.
+func (r *Reporter) submitBatch(ctx context.Context, batch SubmissionBatch, pairs map[string]SubmissionData) error {
+	err := r.KaiaHelper.SubmitDelegatedFallbackDirect(ctx, r.contractAddress, SUBMIT_WITH_PROOFS, batch.FeedHashes, batch.Values, batch.Timestamps, batch.Proofs)
+	if errors.Is(err, errorSentinel.ErrChainTransactionOutOfGas) && len(batch.Pairs) > 1 {
+		gasBudget := r.lowerGasBudget(batch.Gas)
+		log.Warn().Str("Player", "Reporter").Str("Target", r.target).Int("feeds", len(batch.Pairs)).Uint64("estimatedGas", batch.Gas).Uint64("gasBudget", gasBudget).Msg("batch ran out of gas, splitting")
+
+		errs := []error{}
+		for _, split := range PackSubmissionBatches(batch.Pairs, pairs, gasBudget) {
+			splitErr := r.submitBatch(ctx, split, pairs)
+			if splitErr != nil {
+				errs = append(errs, splitErr)
+			}
+		}
+		return mergeErrors(errs)
+	}
+	if err != nil {
+		return err
+	}
 
+	// only confirmed values count as submitted, reverted or dropped ones are retried on the next deviation check
+	for i, pair := range batch.Pairs {
+		r.LatestSubmittedDataMap.Store(pair, batch.Values[i].Int64())
+	}
 	return nil
 }
 
+// lowerGasBudget drops the gas budget to three quarters of a batch which ran out of gas, at least one feed always fits
+func (r *Reporter) lowerGasBudget(batchGas uint64) uint64 {
+	lowered := batchGas / 4 * 3
+	for {
+		current := r.gasBudget.Load()
+		if current <= lowered {
+			return current
+		}
+		if r.gasBudget.CompareAndSwap(current, lowered) {
+			return lowered
+		}
+	}
+}
+
+// raiseGasBudget recovers an eighth of the gas budget after a successful report, up to the configured budget
+func (r *Reporter) raiseGasBudget() {
+	for {
+		current := r.gasBudget.Load()
+		raised := min(current+current/8, r.batchGasBudget)
+		if raised == current || r.gasBudget.CompareAndSwap(current, raised) {
+			return
+		}
+	}
+}
+
 func mergeErrors(errs []error) error {
 	if len(errs) == 0 {
 		return nil
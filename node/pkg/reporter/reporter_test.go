@@ -4,6 +4,7 @@ package reporter
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 
 	"bisonai.com/miko/node/pkg/chain/helper"
@@ -14,6 +15,7 @@ func TestNewReporter(t *testing.T) {
 	ctx := context.Background()
 
 	app := New()
+	latestSubmittedDataMap := new(sync.Map)
 
 	configs, err := fetchConfigs()
 	if err != nil {
@@ -46,7 +48,7 @@ func TestNewReporter(t *testing.T) {
 			WithCachedWhitelist(whitelist),
 			WithKaiaHelper(tmpHelper),
 			WithLatestDataMap(app.LatestDataMap),
-			WithLatestSubmittedDataMap(app.LatestSubmittedDataMap),
+			WithLatestSubmittedDataMap(latestSubmittedDataMap),
 		)
 		if reporterErr != nil {
 			t.Fatalf("error creating new reporter: %v", reporterErr)
@@ -63,7 +65,7 @@ func TestNewReporter(t *testing.T) {
 		WithJobType(DeviationJob),
 		WithKaiaHelper(tmpHelper),
 		WithLatestDataMap(app.LatestDataMap),
-		WithLatestSubmittedDataMap(app.LatestSubmittedDataMap),
+		WithLatestSubmittedDataMap(latestSubmittedDataMap),
 	)
 	if errNewDeviationReporter != nil {
 		if err != nil {
@@ -83,17 +85,22 @@ func TestShouldReportDeviation(t *testing.T) {
 		t.Fatalf("error setting reporters: %v", err)
 	}
 
-	assert.False(t, ShouldReportDeviation(0, 0, 0.05))
-	assert.True(t, ShouldReportDeviation(0, 100000000, 0.05))
-	assert.False(t, ShouldReportDeviation(100000000000, 100100000000, 0.05))
-	assert.True(t, ShouldReportDeviation(100000000000, 105100000000, 0.05))
-	assert.False(t, ShouldReportDeviation(100000000000, 0, 0.05))
+	assert.False(t, ShouldReportDeviation(0, 0, DECIMALS, 0.05))
+	assert.True(t, ShouldReportDeviation(0, 100000000, DECIMALS, 0.05))
+	assert.False(t, ShouldReportDeviation(100000000000, 100100000000, DECIMALS, 0.05))
+	assert.True(t, ShouldReportDeviation(100000000000, 105100000000, DECIMALS, 0.05))
+	assert.False(t, ShouldReportDeviation(100000000000, 0, DECIMALS, 0.05))
+
+	// the absolute threshold from zero follows the decimals of the config
+	assert.False(t, ShouldReportDeviation(0, 200000, DECIMALS, 0.05))
+	assert.True(t, ShouldReportDeviation(0, 200000, 6, 0.05))
 }
 
 func TestGetDeviatingAggregates(t *testing.T) {
 	ctx := context.Background()
 
 	app := New()
+	latestSubmittedDataMap := new(sync.Map)
 
 	configs, err := fetchConfigs()
 	if err != nil {
@@ -124,14 +131,14 @@ func TestGetDeviatingAggregates(t *testing.T) {
 		WithJobType(DeviationJob),
 		WithKaiaHelper(tmpHelper),
 		WithLatestDataMap(app.LatestDataMap),
-		WithLatestSubmittedDataMap(app.LatestSubmittedDataMap),
+		WithLatestSubmittedDataMap(latestSubmittedDataMap),
 	)
 	if err != nil {
 		t.Fatalf("error creating new deviation reporter: %v", err)
 	}
 
 	for _, config := range configs {
-		app.LatestSubmittedDataMap.Store(config.Name, int64(1))
+		latestSubmittedDataMap.Store(config.Name, int64(1))
 		app.LatestDataMap.Store(config.Name, SubmissionData{
 			Value: int64(2),
 		})
@@ -0,0 +1,173 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/rs/zerolog/log"
+)
+
+// LoadTargetConfigs reads the targets from the file set in REPORTER_TARGETS_FILE. Without it, the kaia submission
+// proxy set in SUBMISSION_PROXY_CONTRACT is the only target. REPORTER_BATCH_GAS_BUDGET applies to targets without a budget.
+func LoadTargetConfigs() ([]TargetConfig, error) {
+	batchGasBudget := uint64(DEFAULT_BATCH_GAS_BUDGET)
+	if rawBatchGasBudget := os.Getenv("REPORTER_BATCH_GAS_BUDGET"); rawBatchGasBudget != "" {
+		parsed, parseErr := strconv.ParseUint(rawBatchGasBudget, 10, 64)
+		if parseErr != nil || parsed == 0 {
+			log.Warn().Str("Player", "Reporter").Str("budget", rawBatchGasBudget).Msg("invalid REPORTER_BATCH_GAS_BUDGET, using default")
+		} else {
+			batchGasBudget = parsed
+		}
+	}
+
+	targetsFile := os.Getenv(TARGETS_FILE_ENV)
+	if targetsFile == "" {
+		contractAddress := os.Getenv("SUBMISSION_PROXY_CONTRACT")
+		if contractAddress == "" {
+			return nil, errorSentinel.ErrReporterSubmissionProxyContractNotFound
+		}
+		return []TargetConfig{{
+			Name:            DEFAULT_TARGET_NAME,
+			Chain:           "kaia",
+			ContractAddress: contractAddress,
+			BatchGasBudget:  batchGasBudget,
+		}}, nil
+	}
+
+	raw, err := os.ReadFile(targetsFile)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Err(err).Str("file", targetsFile).Msg("failed to read targets file")
+		return nil, err
+	}
+
+	targets, err := ParseTargetConfigs(raw)
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		if targets[i].BatchGasBudget == 0 {
+			targets[i].BatchGasBudget = batchGasBudget
+		}
+	}
+	return targets, nil
+}
+
+// ParseTargetConfigs decodes and validates a json array of targets
+func ParseTargetConfigs(raw []byte) ([]TargetConfig, error) {
+	targets := []TargetConfig{}
+	err := json.Unmarshal(raw, &targets)
+	if err != nil {
+		log.Error().Str("Player", "Reporter").Err(err).Msg("failed to parse targets")
+		return nil, errorSentinel.ErrReporterInvalidTargetConfig
+	}
+	if len(targets) == 0 {
+		return nil, errorSentinel.ErrReporterNoTargetsSet
+	}
+
+	names := map[string]struct{}{}
+	for _, target := range targets {
+		if target.Name == "" || target.ContractAddress == "" {
+			log.Error().Str("Player", "Reporter").Str("target", target.Name).Msg("target without name or contract address")
+			return nil, errorSentinel.ErrReporterInvalidTargetConfig
+		}
+		if _, exists := names[target.Name]; exists {
+			log.Error().Str("Player", "Reporter").Str("target", target.Name).Msg("duplicate target name")
+			return nil, errorSentinel.ErrReporterInvalidTargetConfig
+		}
+		names[target.Name] = struct{}{}
+
+		if _, err := target.BlockchainType(); err != nil {
+			log.Error().Str("Player", "Reporter").Str("target", target.Name).Str("chain", target.Chain).Msg("unsupported target chain")
+			return nil, err
+		}
+		if target.SubmitInterval < 0 || target.DeviationThreshold < 0 {
+			log.Error().Str("Player", "Reporter").Str("target", target.Name).Msg("negative submit interval or deviation threshold")
+			return nil, errorSentinel.ErrReporterInvalidTargetConfig
+		}
+	}
+	return targets, nil
+}
+
+func (c TargetConfig) BlockchainType() (helper.BlockchainType, error) {
+	switch strings.ToLower(c.Chain) {
+	case "", "kaia":
+		return helper.Kaia, nil
+	case "ethereum":
+		return helper.Ethereum, nil
+	default:
+		return 0, errorSentinel.ErrChainReporterUnsupportedChain
+	}
+}
+
+// ChainHelperOptions points the chain helper of the target at its provider and reporter pk, unset ones fall back to the chain defaults
+func (c TargetConfig) ChainHelperOptions(getSecret func(string) string) ([]helper.ChainHelperOption, error) {
+	blockchainType, err := c.BlockchainType()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []helper.ChainHelperOption{helper.WithBlockchainType(blockchainType)}
+	if c.ProviderUrl != "" {
+		opts = append(opts, helper.WithProviderUrl(c.ProviderUrl))
+	}
+	if c.ReporterPkSecret != "" {
+		reporterPk := getSecret(c.ReporterPkSecret)
+		if reporterPk == "" {
+			log.Error().Str("Player", "Reporter").Str("target", c.Name).Str("secret", c.ReporterPkSecret).Msg("reporter pk secret not found")
+			return nil, errorSentinel.ErrReporterInvalidTargetConfig
+		}
+		opts = append(opts, helper.WithReporterPk(reporterPk))
+	}
+	return opts, nil
+}
+
+// WebsocketChainReader returns the websocket url of the target along with the chain reader option and chain type
+// to watch it with, an empty url when neither the target nor the env of its chain sets one
+func (c TargetConfig) WebsocketChainReader() (string, websocketchainreader.BlockchainType, websocketchainreader.ChainReaderOption) {
+	blockchainType, _ := c.BlockchainType()
+	if blockchainType == helper.Ethereum {
+		websocketUrl := c.WebsocketUrl
+		if websocketUrl == "" {
+			websocketUrl = os.Getenv("ETH_WEBSOCKET_URL")
+		}
+		return websocketUrl, websocketchainreader.Ethereum, websocketchainreader.WithEthWebsocketUrl(websocketUrl)
+	}
+
+	websocketUrl := c.WebsocketUrl
+	if websocketUrl == "" {
+		websocketUrl = os.Getenv("KAIA_WEBSOCKET_URL")
+	}
+	return websocketUrl, websocketchainreader.Kaia, websocketchainreader.WithKaiaWebsocketUrl(websocketUrl)
+}
+
+// ApplyTo narrows dal configs down to the feeds of the target and applies its submit intervals
+func (c TargetConfig) ApplyTo(configs []Config) []Config {
+	result := []Config{}
+	for _, config := range configs {
+		if len(c.Feeds) > 0 && !slices.Contains(c.Feeds, config.Name) {
+			continue
+		}
+
+		interval := c.SubmitIntervals[config.Name]
+		if interval <= 0 {
+			interval = c.SubmitInterval
+		}
+		if interval > 0 {
+			config.SubmitInterval = &interval
+		}
+		result = append(result, config)
+	}
+
+	for _, feed := range c.Feeds {
+		if !slices.ContainsFunc(result, func(config Config) bool { return config.Name == feed }) {
+			log.Warn().Str("Player", "Reporter").Str("target", c.Name).Str("feed", feed).Msg("target feed not found in configs")
+		}
+	}
+	return result
+}
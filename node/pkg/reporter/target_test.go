@@ -0,0 +1,127 @@
+//nolint:all
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bisonai.com/miko/node/pkg/chain/helper"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTargetConfigs(t *testing.T) {
+	targets, err := ParseTargetConfigs([]byte(`[
+		{"name": "kaia", "contractAddress": "0x1"},
+		{"name": "ethereum", "chain": "Ethereum", "contractAddress": "0x2", "submitIntervals": {"BTC-USDT": 30000}, "deviationThreshold": 0.01}
+	]`))
+	assert.NoError(t, err)
+	assert.Len(t, targets, 2)
+	assert.Equal(t, 30000, targets[1].SubmitIntervals["BTC-USDT"])
+
+	blockchainType, err := targets[0].BlockchainType()
+	assert.NoError(t, err)
+	assert.Equal(t, helper.Kaia, blockchainType)
+	blockchainType, err = targets[1].BlockchainType()
+	assert.NoError(t, err)
+	assert.Equal(t, helper.Ethereum, blockchainType)
+
+	invalid := map[string]string{
+		"malformed":         `{"name": "kaia"}`,
+		"empty":             `[]`,
+		"missing contract":  `[{"name": "kaia"}]`,
+		"duplicate name":    `[{"name": "kaia", "contractAddress": "0x1"}, {"name": "kaia", "contractAddress": "0x2"}]`,
+		"unsupported chain": `[{"name": "sol", "chain": "solana", "contractAddress": "0x1"}]`,
+		"negative interval": `[{"name": "kaia", "contractAddress": "0x1", "submitInterval": -1}]`,
+	}
+	for name, raw := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseTargetConfigs([]byte(raw))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadTargetConfigs(t *testing.T) {
+	t.Run("defaults to the kaia submission proxy", func(t *testing.T) {
+		t.Setenv(TARGETS_FILE_ENV, "")
+		t.Setenv("SUBMISSION_PROXY_CONTRACT", "0x1")
+		t.Setenv("REPORTER_BATCH_GAS_BUDGET", "")
+
+		targets, err := LoadTargetConfigs()
+		assert.NoError(t, err)
+		assert.Equal(t, []TargetConfig{{Name: DEFAULT_TARGET_NAME, Chain: "kaia", ContractAddress: "0x1", BatchGasBudget: DEFAULT_BATCH_GAS_BUDGET}}, targets)
+
+		t.Setenv("SUBMISSION_PROXY_CONTRACT", "")
+		_, err = LoadTargetConfigs()
+		assert.ErrorIs(t, err, errorSentinel.ErrReporterSubmissionProxyContractNotFound)
+	})
+
+	t.Run("reads the targets file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "targets.json")
+		err := os.WriteFile(file, []byte(`[{"name": "kaia", "contractAddress": "0x1"}, {"name": "ethereum", "chain": "ethereum", "contractAddress": "0x2", "batchGasBudget": 1000000}]`), 0644)
+		assert.NoError(t, err)
+		t.Setenv(TARGETS_FILE_ENV, file)
+		t.Setenv("REPORTER_BATCH_GAS_BUDGET", "2000000")
+
+		targets, err := LoadTargetConfigs()
+		assert.NoError(t, err)
+		assert.Len(t, targets, 2)
+		assert.Equal(t, uint64(2000000), targets[0].BatchGasBudget)
+		assert.Equal(t, uint64(1000000), targets[1].BatchGasBudget)
+	})
+}
+
+func TestTargetConfigApplyTo(t *testing.T) {
+	configInterval := 15000
+	configs := []Config{
+		{Name: "BTC-USDT", SubmitInterval: &configInterval},
+		{Name: "ETH-USDT", SubmitInterval: &configInterval},
+		{Name: "KAIA-USDT"},
+	}
+
+	t.Run("keeps dal configs without overrides", func(t *testing.T) {
+		result := TargetConfig{Name: "kaia"}.ApplyTo(configs)
+		assert.Equal(t, configs, result)
+	})
+
+	t.Run("narrows feeds and overrides intervals", func(t *testing.T) {
+		target := TargetConfig{
+			Name:            "ethereum",
+			SubmitInterval:  60000,
+			SubmitIntervals: map[string]int{"BTC-USDT": 30000},
+			Feeds:           []string{"BTC-USDT", "KAIA-USDT", "MISSING-USDT"},
+		}
+		result := target.ApplyTo(configs)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 30000, *result[0].SubmitInterval)
+		assert.Equal(t, 60000, *result[1].SubmitInterval)
+
+		grouped := groupConfigsBySubmitIntervals(result)
+		assert.Len(t, grouped[30000], 1)
+		assert.Len(t, grouped[60000], 1)
+	})
+
+	// dal configs are shared between targets
+	assert.Equal(t, 15000, *configs[0].SubmitInterval)
+	assert.Nil(t, configs[2].SubmitInterval)
+}
+
+func TestTargetConfigChainHelperOptions(t *testing.T) {
+	secrets := map[string]string{"ETH_TARGET_PK": "0xabc"}
+	getSecret := func(name string) string { return secrets[name] }
+
+	opts, err := TargetConfig{Name: "ethereum", Chain: "ethereum", ProviderUrl: "http://localhost:8545", ReporterPkSecret: "ETH_TARGET_PK"}.ChainHelperOptions(getSecret)
+	assert.NoError(t, err)
+	config := helper.ChainHelperConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	assert.Equal(t, helper.Ethereum, config.BlockchainType)
+	assert.Equal(t, "http://localhost:8545", config.ProviderUrl)
+	assert.Equal(t, "0xabc", config.ReporterPk)
+
+	_, err = TargetConfig{Name: "ethereum", Chain: "ethereum", ReporterPkSecret: "MISSING_PK"}.ChainHelperOptions(getSecret)
+	assert.ErrorIs(t, err, errorSentinel.ErrReporterInvalidTargetConfig)
+}
@@ -1,7 +1,9 @@
 package reporter
 
 import (
+	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/helper"
@@ -13,14 +15,35 @@ import (
 const (
 	SUBMIT_WITH_PROOFS    = "submit(bytes32[] calldata _feedHashes, int256[] calldata _answers, uint256[] calldata _timestamps, bytes[] calldata _proofs)"
 	GET_ONCHAIN_WHITELIST = "getAllOracles() public view returns (address[] memory)"
+	GET_DEFAULT_THRESHOLD = "defaultThreshold() public view returns (uint8)"
+	GET_THRESHOLD         = "thresholds(bytes32) public view returns (uint8)"
+	ORACLE_ADDED_EVENT    = "OracleAdded(address oracle, uint256 expirationTime)"
+	// percentage of oracles signing a proof, the contract default until thresholds are read
+	DEFAULT_PROOF_THRESHOLD = 50
+
+	// proofs signed by unknown or expired signers refresh the whitelist at most this often, OracleAdded events always do
+	MIN_WHITELIST_REFRESH_INTERVAL = 30 * time.Second
 
 	GET_REPORTER_CONFIGS = `SELECT name, id, submit_interval, aggregate_interval FROM configs;`
 
-	MAX_REPORT_BATCH_SIZE = 50
-	DEVIATION_INTERVAL    = 2000
+	MAX_REPORT_BATCH_SIZE   = 50
+	DEVIATION_INTERVAL      = 2000
+	DEFAULT_SUBMIT_INTERVAL = 5000
+
+	// json file of the chains reports are fanned out to, the kaia submission proxy alone when unset
+	TARGETS_FILE_ENV    = "REPORTER_TARGETS_FILE"
+	DEFAULT_TARGET_NAME = "kaia"
+
+	// gas estimation of submit with proofs, each feed pays for its proof calldata and one signature check per signer
+	SUBMISSION_BASE_GAS        = 50_000
+	FEED_SUBMISSION_GAS        = 50_000
+	SIGNATURE_VERIFICATION_GAS = 10_000
+	CALLDATA_GAS_PER_BYTE      = 16
+	PROOF_LENGTH               = 65
+	DEFAULT_BATCH_GAS_BUDGET   = 5_000_000
 
 	DEVIATION_ABSOLUTE_THRESHOLD = 0.1
-	DECIMALS                     = 8
+	DECIMALS                     = types.DefaultDecimals
 
 	MAX_DEVIATION_THRESHOLD = 0.01
 	MIN_DEVIATION_THRESHOLD = 0.05
@@ -36,10 +59,35 @@ type Config struct {
 }
 
 type App struct {
-	Reporters []*Reporter
+	Reporters []*Reporter // reporters of every target
+	Targets   []*Target
 
-	WsHelper               *wss.WebsocketHelper
-	LatestDataMap          *sync.Map // map[symbol]SubmissionData
+	WsHelper      *wss.WebsocketHelper
+	LatestDataMap *sync.Map // map[symbol]SubmissionData, shared by all targets
+}
+
+// TargetConfig declares a chain the dal feeds are submitted to
+type TargetConfig struct {
+	Name             string `json:"name"`
+	Chain            string `json:"chain"`            // kaia or ethereum
+	ProviderUrl      string `json:"providerUrl"`      // defaults to the provider url env of the chain
+	ContractAddress  string `json:"contractAddress"`  // submission proxy
+	ReporterPkSecret string `json:"reporterPkSecret"` // name of the secret holding the reporter pk, defaults to the reporter pk of the chain
+	WebsocketUrl     string `json:"websocketUrl"`     // watched for OracleAdded events, defaults to the websocket url env of the chain
+	// submit intervals in milliseconds, per feed first, then for the whole target, then the dal config
+	SubmitInterval     int            `json:"submitInterval"`
+	SubmitIntervals    map[string]int `json:"submitIntervals"`
+	DeviationThreshold float64        `json:"deviationThreshold"` // derived from the submit interval when unset
+	BatchGasBudget     uint64         `json:"batchGasBudget"`
+	Feeds              []string       `json:"feeds"` // every dal feed when empty
+}
+
+// Target holds the reporters of a single chain, with its own chain helper, nonce manager and submitted values
+type Target struct {
+	Config                 TargetConfig
+	ChainHelper            *helper.ChainHelper
+	ProofVerifier          *ProofVerifier
+	Reporters              []*Reporter
 	LatestSubmittedDataMap *sync.Map // map[symbol]int64
 }
 
@@ -54,13 +102,17 @@ type ReporterConfig struct {
 	Configs                []Config
 	Interval               int
 	ContractAddress        string
-	CachedWhitelist        []common.Address
+	CachedWhitelist        []common.Address // seeds the proof verifier when none is given
+	ProofVerifier          *ProofVerifier
 	JobType                JobType
 	DalApiKey              string
 	DalWsEndpoint          string
 	KaiaHelper             *helper.ChainHelper
 	LatestDataMap          *sync.Map // map[symbol]SubmissionData
 	LatestSubmittedDataMap *sync.Map // map[symbol]int64
+	BatchGasBudget         uint64
+	DeviationThreshold     float64
+	Target                 string
 }
 
 type ReporterOption func(*ReporterConfig)
@@ -118,26 +170,95 @@ func WithLatestSubmittedDataMap(latestSubmittedDataMap *sync.Map) ReporterOption
 	}
 }
 
+// WithBatchGasBudget sets the estimated gas a single submission may use, feeds are packed into batches up to it
+func WithBatchGasBudget(budget uint64) ReporterOption {
+	return func(c *ReporterConfig) {
+		c.BatchGasBudget = budget
+	}
+}
+
+// WithProofVerifier shares the proof verifier, and with it the cached whitelist, between reporters of a target
+func WithProofVerifier(verifier *ProofVerifier) ReporterOption {
+	return func(c *ReporterConfig) {
+		c.ProofVerifier = verifier
+	}
+}
+
+// WithDeviationThreshold overrides the deviation threshold derived from the interval
+func WithDeviationThreshold(threshold float64) ReporterOption {
+	return func(c *ReporterConfig) {
+		c.DeviationThreshold = threshold
+	}
+}
+
+// WithTarget sets the name of the target chain the reporter submits to
+func WithTarget(target string) ReporterOption {
+	return func(c *ReporterConfig) {
+		c.Target = target
+	}
+}
+
 type Reporter struct {
 	KaiaHelper         *helper.ChainHelper
 	Pairs              []string
 	SubmissionInterval time.Duration
-	CachedWhitelist    []common.Address
 
+	target             string
 	contractAddress    string
 	deviationThreshold float64
+	verifier           *ProofVerifier
+	batchGasBudget     uint64
+	gasBudget          atomic.Uint64 // lowered below batches which ran out of gas, recovers towards batchGasBudget
 
 	LatestDataMap          *sync.Map
 	LatestSubmittedDataMap *sync.Map
 	Job                    func() error
 }
 
+// ProofVerifier checks proofs the way the submission proxy does before they are submitted, so proofs it would
+// reject are not paid for. Oracles, their expirations and proof thresholds are read from the contract and cached.
+type ProofVerifier struct {
+	target          string
+	chainHelper     *helper.ChainHelper
+	contractAddress string
+
+	mu               sync.RWMutex
+	loaded           bool
+	oracles          []common.Address // in contract order, proof signers have to follow it
+	expirations      map[common.Address]time.Time
+	defaultThreshold uint8
+	thresholds       map[[32]byte]uint8 // percentage of oracles which have to sign, per feed hash
+	lastRefresh      time.Time
+
+	refreshing atomic.Bool
+}
+
+// ProofCheck is the outcome of checking the signers of a single proof
+type ProofCheck struct {
+	Valid    int
+	Required int
+	Unknown  int // signers not in the whitelist
+	Expired  int
+}
+
+// SubmissionBatch is a set of feeds sent in a single submission along with its estimated gas
+type SubmissionBatch struct {
+	Pairs      []string
+	FeedHashes [][32]byte
+	Values     []*big.Int
+	Timestamps []*big.Int
+	Proofs     [][]byte
+	Gas        uint64
+}
+
 type RawSubmissionData struct {
 	Symbol        string `json:"symbol"`
 	Value         string `json:"value"`
 	AggregateTime string `json:"aggregateTime"`
 	Proof         string `json:"proof"`
 	FeedHash      string `json:"feedHash"`
+	Decimals      string `json:"decimals"`
+	MarketClosed  bool   `json:"marketClosed"`
 }
 type SubmissionData struct {
 	Symbol        string   `json:"symbol"`
@@ -145,4 +266,6 @@ type SubmissionData struct {
 	AggregateTime int64    `json:"aggregateTime"`
 	Proof         []byte   `json:"proof"`
 	FeedHash      [32]byte `json:"feedHash"`
+	Decimals      int32    `json:"decimals"`
+	MarketClosed  bool     `json:"marketClosed"` // only reported on heartbeat, never on deviation
 }
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/helper"
+	"bisonai.com/miko/node/pkg/common/types"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/secrets"
 	"bisonai.com/miko/node/pkg/utils/request"
@@ -32,7 +35,12 @@ func GetDeviatingAggregates(latestSubmittedData *sync.Map, latestData *sync.Map,
 			return true
 		}
 
-		if ShouldReportDeviation(oldValue, newValue.Value, threshold) {
+		// the last close of a closed fx market is left to the heartbeat
+		if newValue.MarketClosed {
+			return true
+		}
+
+		if ShouldReportDeviation(oldValue, newValue.Value, newValue.Decimals, threshold) {
 			deviatingSubmissionPairs[pair] = newValue
 		}
 		return true
@@ -79,8 +87,8 @@ func GetLatestData(latestDataMap *sync.Map, name string) (SubmissionData, bool)
 	return convertedLatestData, true
 }
 
-func ShouldReportDeviation(oldValue int64, newValue int64, threshold float64) bool {
-	denominator := math.Pow10(DECIMALS)
+func ShouldReportDeviation(oldValue int64, newValue int64, decimals int32, threshold float64) bool {
+	denominator := math.Pow10(int(types.DecimalsOrDefault(decimals)))
 	oldValueInFLoat := float64(oldValue) / denominator
 	newValueInFLoat := float64(newValue) / denominator
 
@@ -96,6 +104,63 @@ func ShouldReportDeviation(oldValue int64, newValue int64, threshold float64) bo
 	}
 }
 
+// PrioritizeDeviating orders pairs deviating from their last submission, or never submitted, before the rest.
+// Pairs are sorted by name within both groups.
+func PrioritizeDeviating(pairs map[string]SubmissionData, latestSubmittedData *sync.Map, threshold float64) []string {
+	deviating := []string{}
+	rest := []string{}
+	for pair, submissionData := range pairs {
+		oldValue, ok := latestSubmittedData.Load(pair)
+		if !submissionData.MarketClosed && (!ok || ShouldReportDeviation(oldValue.(int64), submissionData.Value, submissionData.Decimals, threshold)) {
+			deviating = append(deviating, pair)
+		} else {
+			rest = append(rest, pair)
+		}
+	}
+
+	slices.Sort(deviating)
+	slices.Sort(rest)
+	return append(deviating, rest...)
+}
+
+// EstimateSubmissionGas estimates the gas a feed adds to a submission, its proof calldata and the signature check of every signer
+func EstimateSubmissionGas(submissionData SubmissionData) uint64 {
+	proofLength := uint64(len(submissionData.Proof))
+	signers := proofLength / PROOF_LENGTH
+	return FEED_SUBMISSION_GAS + proofLength*CALLDATA_GAS_PER_BYTE + signers*SIGNATURE_VERIFICATION_GAS
+}
+
+// PackSubmissionBatches fills batches in the given order up to the gas budget and MAX_REPORT_BATCH_SIZE feeds.
+// A feed exceeding the budget on its own is sent alone.
+func PackSubmissionBatches(order []string, pairs map[string]SubmissionData, gasBudget uint64) []SubmissionBatch {
+	batches := []SubmissionBatch{}
+	batch := SubmissionBatch{Gas: SUBMISSION_BASE_GAS}
+	for _, pair := range order {
+		submissionData, ok := pairs[pair]
+		if !ok {
+			continue
+		}
+
+		gas := EstimateSubmissionGas(submissionData)
+		if len(batch.Pairs) > 0 && (batch.Gas+gas > gasBudget || len(batch.Pairs) >= MAX_REPORT_BATCH_SIZE) {
+			batches = append(batches, batch)
+			batch = SubmissionBatch{Gas: SUBMISSION_BASE_GAS}
+		}
+
+		batch.Pairs = append(batch.Pairs, pair)
+		batch.FeedHashes = append(batch.FeedHashes, submissionData.FeedHash)
+		batch.Values = append(batch.Values, big.NewInt(submissionData.Value))
+		batch.Timestamps = append(batch.Timestamps, big.NewInt(submissionData.AggregateTime))
+		batch.Proofs = append(batch.Proofs, submissionData.Proof)
+		batch.Gas += gas
+	}
+
+	if len(batch.Pairs) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
 func ReadOnchainWhitelist(ctx context.Context, chainHelper *helper.ChainHelper, contractAddress string, contractFunction string) ([]klaytncommon.Address, error) {
 	result, err := chainHelper.ReadContract(ctx, contractAddress, contractFunction)
 	if err != nil {
@@ -196,7 +261,20 @@ func RawSubmissionData2SubmissionData(rawSubmissionData RawSubmissionData) (Subm
 		return SubmissionData{}, errorSentinel.ErrReporterDalWsDataProcessingFailed
 	}
 	submissionData.AggregateTime = timestampValue
+
+	// dal versions before per config decimals leave it out
+	submissionData.Decimals = types.DefaultDecimals
+	if rawSubmissionData.Decimals != "" {
+		decimals, decimalsErr := strconv.ParseInt(rawSubmissionData.Decimals, 10, 32)
+		if decimalsErr != nil {
+			log.Error().Str("Player", "Reporter").Err(decimalsErr).Msg("failed to parse decimals: " + rawSubmissionData.Decimals)
+			return SubmissionData{}, errorSentinel.ErrReporterDalWsDataProcessingFailed
+		}
+		submissionData.Decimals = types.DecimalsOrDefault(int32(decimals))
+	}
+
 	submissionData.Symbol = rawSubmissionData.Symbol
+	submissionData.MarketClosed = rawSubmissionData.MarketClosed
 
 	return submissionData, nil
 }
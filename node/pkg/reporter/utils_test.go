@@ -3,7 +3,9 @@
 package reporter
 
 import (
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -30,9 +32,40 @@ func TestProcessDalWsRawData(t *testing.T) {
 				AggregateTime: 1609459200,
 				Proof:         []byte{0xab, 0xcd, 0xef},
 				FeedHash:      [32]byte{0x12, 0x34, 0x56},
+				Decimals:      DECIMALS,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid input with decimals",
+			input: RawSubmissionData{
+				Value:         "123",
+				AggregateTime: "1609459200",
+				Proof:         "0xabcdef",
+				FeedHash:      "0x123456",
+				Decimals:      "6",
+			},
+			expected: SubmissionData{
+				Value:         123,
+				AggregateTime: 1609459200,
+				Proof:         []byte{0xab, 0xcd, 0xef},
+				FeedHash:      [32]byte{0x12, 0x34, 0x56},
+				Decimals:      6,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid decimals",
+			input: RawSubmissionData{
+				Value:         "123",
+				AggregateTime: "1609459200",
+				Proof:         "0xabcdef",
+				FeedHash:      "0x123456",
+				Decimals:      "six",
+			},
+			expected: SubmissionData{},
+			wantErr:  true,
+		},
 		{
 			name: "Invalid value",
 			input: RawSubmissionData{
@@ -122,3 +155,77 @@ func TestGetDeviationThreshold(t *testing.T) {
 	assert.Equal(t, 0.01, GetDeviationThreshold(2*time.Hour))
 	assert.Less(t, GetDeviationThreshold(30*time.Second), 0.05)
 }
+
+func TestGetDeviatingAggregatesSkipsClosedMarkets(t *testing.T) {
+	latestSubmittedData := &sync.Map{}
+	latestData := &sync.Map{}
+	latestSubmittedData.Store("BTC-USDT", int64(100))
+	latestSubmittedData.Store("EUR-USD", int64(100))
+	latestData.Store("BTC-USDT", SubmissionData{Symbol: "BTC-USDT", Value: 110})
+	latestData.Store("EUR-USD", SubmissionData{Symbol: "EUR-USD", Value: 110, MarketClosed: true})
+
+	deviating := GetDeviatingAggregates(latestSubmittedData, latestData, 0.05)
+	assert.Len(t, deviating, 1)
+	assert.Contains(t, deviating, "BTC-USDT")
+}
+
+func TestPrioritizeDeviating(t *testing.T) {
+	latestSubmittedData := &sync.Map{}
+	latestSubmittedData.Store("ADA-USDT", int64(100))
+	latestSubmittedData.Store("BTC-USDT", int64(100))
+	latestSubmittedData.Store("ETH-USDT", int64(100))
+	latestSubmittedData.Store("EUR-USD", int64(100))
+
+	pairs := map[string]SubmissionData{
+		"ADA-USDT": {Value: 101},
+		"BTC-USDT": {Value: 110},
+		"ETH-USDT": {Value: 100},
+		"EUR-USD":  {Value: 110, MarketClosed: true},
+		"SOL-USDT": {Value: 100},
+	}
+
+	// deviating and never submitted pairs first, closed markets are left to the heartbeat
+	assert.Equal(t, []string{"BTC-USDT", "SOL-USDT", "ADA-USDT", "ETH-USDT", "EUR-USD"}, PrioritizeDeviating(pairs, latestSubmittedData, 0.05))
+}
+
+func TestPackSubmissionBatches(t *testing.T) {
+	proof := func(signers int) []byte { return make([]byte, signers*PROOF_LENGTH) }
+	pairs := map[string]SubmissionData{
+		"A": {Value: 1, Proof: proof(4)},
+		"B": {Value: 2, Proof: proof(4)},
+		"C": {Value: 3, Proof: proof(8)},
+		"D": {Value: 4, Proof: proof(1)},
+	}
+
+	feedGas := EstimateSubmissionGas(pairs["A"])
+	assert.Equal(t, uint64(FEED_SUBMISSION_GAS+4*PROOF_LENGTH*CALLDATA_GAS_PER_BYTE+4*SIGNATURE_VERIFICATION_GAS), feedGas)
+	assert.Greater(t, EstimateSubmissionGas(pairs["C"]), feedGas)
+
+	t.Run("packs up to the budget in order", func(t *testing.T) {
+		batches := PackSubmissionBatches([]string{"A", "B", "C", "D"}, pairs, SUBMISSION_BASE_GAS+2*feedGas)
+		assert.Len(t, batches, 3)
+		assert.Equal(t, []string{"A", "B"}, batches[0].Pairs)
+		assert.Equal(t, uint64(SUBMISSION_BASE_GAS+2*feedGas), batches[0].Gas)
+		assert.Equal(t, []string{"C"}, batches[1].Pairs)
+		assert.Equal(t, []string{"D"}, batches[2].Pairs)
+		assert.Equal(t, int64(4), batches[2].Values[0].Int64())
+	})
+
+	t.Run("oversized feed is sent alone", func(t *testing.T) {
+		batches := PackSubmissionBatches([]string{"C", "D"}, pairs, 1)
+		assert.Len(t, batches, 2)
+	})
+
+	t.Run("caps feeds per batch", func(t *testing.T) {
+		many := map[string]SubmissionData{}
+		order := []string{}
+		for i := 0; i < MAX_REPORT_BATCH_SIZE+1; i++ {
+			pair := fmt.Sprintf("PAIR-%d", i)
+			many[pair] = SubmissionData{Value: int64(i)}
+			order = append(order, pair)
+		}
+		batches := PackSubmissionBatches(order, many, DEFAULT_BATCH_GAS_BUDGET*100)
+		assert.Len(t, batches, 2)
+		assert.Len(t, batches[0].Pairs, MAX_REPORT_BATCH_SIZE)
+	})
+}
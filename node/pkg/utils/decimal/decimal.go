@@ -0,0 +1,167 @@
+// Package decimal is the exact arithmetic of the price pipeline. Prices travel between components as
+// big.Int scaled by 10^decimals, every step in between works on big.Rat and is rounded exactly once, half away
+// from zero, so nodes fed the same inputs agree on every unit regardless of the order they summed them in.
+package decimal
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+)
+
+// MaxExactFloat is the largest integer below which every scaled value is exactly representable as float64
+const MaxExactFloat = 1 << 53
+
+var (
+	one = big.NewInt(1)
+	ten = big.NewInt(10)
+)
+
+// Parse reads numbers as they come out of json, decoded either with or without UseNumber, and numeric strings.
+// Thousands separators are ignored.
+func Parse(raw any) (*big.Rat, error) {
+	switch v := raw.(type) {
+	case *big.Rat:
+		return new(big.Rat).Set(v), nil
+	case *big.Int:
+		return new(big.Rat).SetInt(v), nil
+	case float64:
+		return FromFloat64(v)
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case json.Number:
+		return parseString(string(v))
+	case string:
+		return parseString(strings.ReplaceAll(v, ",", ""))
+	default:
+		return nil, errorSentinel.ErrDecimalInvalidValue
+	}
+}
+
+// FromFloat64 takes the shortest decimal which parses back into f, e.g. 0.1 instead of
+// 0.1000000000000000055511151231257827, which is the number the source actually sent
+func FromFloat64(f float64) (*big.Rat, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, errorSentinel.ErrDecimalInvalidValue
+	}
+	return parseString(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+func parseString(raw string) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(raw))
+	if !ok {
+		return nil, errorSentinel.ErrDecimalInvalidValue
+	}
+	return value, nil
+}
+
+// Pow10 returns 10^exp, negative exponents included
+func Pow10(exp int) *big.Rat {
+	power := new(big.Int).Exp(ten, big.NewInt(int64(abs(exp))), nil)
+	if exp < 0 {
+		return new(big.Rat).SetFrac(one, power)
+	}
+	return new(big.Rat).SetInt(power)
+}
+
+// Scale multiplies value by 10^decimals
+func Scale(value *big.Rat, decimals int) *big.Rat {
+	return new(big.Rat).Mul(value, Pow10(decimals))
+}
+
+// Round rounds to the nearest integer, halves away from zero like math.Round
+func Round(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// denominators are always positive, so comparing 2|r| against it decides the half
+	doubled := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+	if doubled.Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, one)
+		} else {
+			quotient.Add(quotient, one)
+		}
+	}
+	return quotient
+}
+
+// ToFloat64 rounds value into an integer held by a float64, exact as long as it stays below MaxExactFloat
+func ToFloat64(value *big.Rat) float64 {
+	result, _ := new(big.Float).SetInt(Round(value)).Float64()
+	return result
+}
+
+// ScaleInt multiplies value by 10^decimals and rounds it, negative decimals drop digits
+func ScaleInt(value *big.Int, decimals int) *big.Int {
+	return Round(Scale(new(big.Rat).SetInt(value), decimals))
+}
+
+// Median of the values, the mean of both middle values for even counts
+func Median(values []*big.Rat) *big.Rat {
+	if len(values) == 0 {
+		return new(big.Rat)
+	}
+
+	sorted := make([]*big.Rat, len(values))
+	copy(sorted, values)
+	slices.SortFunc(sorted, func(a, b *big.Rat) int { return a.Cmp(b) })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Rat).Set(sorted[middle])
+	}
+	sum := new(big.Rat).Add(sorted[middle-1], sorted[middle])
+	return sum.Quo(sum, big.NewRat(2, 1))
+}
+
+// QuartileOutliers returns the values beyond 1.5 (mild) and 3 (extreme) interquartile ranges outside of the
+// quartiles, which are the medians of the lower and upper half with the middle of odd counts left out of both
+func QuartileOutliers(values []*big.Rat) ([]*big.Rat, []*big.Rat) {
+	if len(values) < 2 {
+		return nil, nil
+	}
+
+	sorted := make([]*big.Rat, len(values))
+	copy(sorted, values)
+	slices.SortFunc(sorted, func(a, b *big.Rat) int { return a.Cmp(b) })
+
+	lower, upper := len(sorted)/2, len(sorted)/2
+	if len(sorted)%2 == 1 {
+		upper++
+	}
+	q1 := Median(sorted[:lower])
+	q3 := Median(sorted[upper:])
+	iqr := new(big.Rat).Sub(q3, q1)
+
+	innerRange := new(big.Rat).Mul(iqr, big.NewRat(3, 2))
+	outerRange := new(big.Rat).Mul(iqr, big.NewRat(3, 1))
+	lowerInner, upperInner := new(big.Rat).Sub(q1, innerRange), new(big.Rat).Add(q3, innerRange)
+	lowerOuter, upperOuter := new(big.Rat).Sub(q1, outerRange), new(big.Rat).Add(q3, outerRange)
+
+	var mild, extreme []*big.Rat
+	for _, value := range sorted {
+		if value.Cmp(lowerOuter) < 0 || value.Cmp(upperOuter) > 0 {
+			extreme = append(extreme, value)
+		} else if value.Cmp(lowerInner) < 0 || value.Cmp(upperInner) > 0 {
+			mild = append(mild, value)
+		}
+	}
+	return mild, extreme
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,209 @@
+package fxcalendar
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // sessions are defined in new york time, images may ship without zoneinfo
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	PricePairs = "GBP-USD,EUR-USD,KRW-USD,JPY-USD,CHF-USD"
+
+	DefaultTimezone = "America/New_York"
+	// the fx week runs from the sydney open on sunday to the new york close on friday
+	DefaultSessions = "Sun 17:00-Fri 17:00"
+)
+
+// WeeklyTime is a point in the trading week in the calendar's timezone
+type WeeklyTime struct {
+	Day    time.Weekday
+	Hour   int
+	Minute int
+}
+
+type Session struct {
+	Open  WeeklyTime
+	Close WeeklyTime
+}
+
+// Calendar tells whether fx markets trade at a given time, they are open during any of the weekly sessions
+// except on holidays, which close the whole calendar day
+type Calendar struct {
+	Location *time.Location
+	Sessions []Session
+	Holidays map[string]bool // dates as 2006-01-02 in Location
+}
+
+type CalendarConfig struct {
+	Timezone string
+	Sessions string
+	Holidays string
+}
+
+type CalendarOption func(*CalendarConfig)
+
+// WithTimezone sets the IANA timezone sessions and holidays are defined in
+func WithTimezone(timezone string) CalendarOption {
+	return func(config *CalendarConfig) {
+		config.Timezone = timezone
+	}
+}
+
+// WithSessions sets comma separated weekly sessions, e.g. "Sun 17:00-Fri 17:00"
+func WithSessions(sessions string) CalendarOption {
+	return func(config *CalendarConfig) {
+		config.Sessions = sessions
+	}
+}
+
+// WithHolidays sets comma separated closed dates, e.g. "2024-12-25,2025-01-01"
+func WithHolidays(holidays string) CalendarOption {
+	return func(config *CalendarConfig) {
+		config.Holidays = holidays
+	}
+}
+
+func New(opts ...CalendarOption) (*Calendar, error) {
+	config := &CalendarConfig{
+		Timezone: DefaultTimezone,
+		Sessions: DefaultSessions,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{Location: location, Holidays: map[string]bool{}}
+	for _, rawSession := range strings.Split(config.Sessions, ",") {
+		session, err := parseSession(strings.TrimSpace(rawSession))
+		if err != nil {
+			return nil, err
+		}
+		calendar.Sessions = append(calendar.Sessions, session)
+	}
+
+	for _, rawHoliday := range strings.Split(config.Holidays, ",") {
+		rawHoliday = strings.TrimSpace(rawHoliday)
+		if rawHoliday == "" {
+			continue
+		}
+		holiday, err := time.Parse(time.DateOnly, rawHoliday)
+		if err != nil {
+			return nil, err
+		}
+		calendar.Holidays[holiday.Format(time.DateOnly)] = true
+	}
+
+	return calendar, nil
+}
+
+var (
+	defaultCalendar     *Calendar
+	defaultCalendarOnce sync.Once
+)
+
+// Default returns the calendar configured through FX_MARKET_TIMEZONE, FX_MARKET_SESSIONS and FX_HOLIDAYS,
+// invalid settings are logged and replaced with the defaults
+func Default() *Calendar {
+	defaultCalendarOnce.Do(func() {
+		opts := []CalendarOption{WithHolidays(os.Getenv("FX_HOLIDAYS"))}
+		if timezone := os.Getenv("FX_MARKET_TIMEZONE"); timezone != "" {
+			opts = append(opts, WithTimezone(timezone))
+		}
+		if sessions := os.Getenv("FX_MARKET_SESSIONS"); sessions != "" {
+			opts = append(opts, WithSessions(sessions))
+		}
+
+		calendar, err := New(opts...)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "FxCalendar").Msg("invalid fx calendar settings, using defaults")
+			calendar, _ = New()
+		}
+		defaultCalendar = calendar
+	})
+	return defaultCalendar
+}
+
+// IsFXPricePair matches whole pair names, so neither "USD" nor "EUR-USDT" counts as an fx pair
+func IsFXPricePair(name string) bool {
+	return slices.Contains(strings.Split(PricePairs, ","), name)
+}
+
+// MarketClosed reports fx configs outside of trading hours of the default calendar, other configs trade around the clock
+func MarketClosed(name string, t time.Time) bool {
+	return IsFXPricePair(name) && !Default().IsOpen(t)
+}
+
+func (c *Calendar) IsOpen(t time.Time) bool {
+	local := t.In(c.Location)
+	if c.Holidays[local.Format(time.DateOnly)] {
+		return false
+	}
+
+	now := WeeklyTime{Day: local.Weekday(), Hour: local.Hour(), Minute: local.Minute()}.minutes()
+	for _, session := range c.Sessions {
+		open, close := session.Open.minutes(), session.Close.minutes()
+		if open <= close && now >= open && now < close {
+			return true
+		}
+		// sessions spanning the end of the week, e.g. Fri 22:00-Mon 02:00
+		if open > close && (now >= open || now < close) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w WeeklyTime) minutes() int {
+	return int(w.Day)*24*60 + w.Hour*60 + w.Minute
+}
+
+func parseSession(raw string) (Session, error) {
+	open, close, found := strings.Cut(raw, "-")
+	if !found {
+		return Session{}, fmt.Errorf("invalid fx session %q", raw)
+	}
+
+	openTime, err := parseWeeklyTime(strings.TrimSpace(open))
+	if err != nil {
+		return Session{}, err
+	}
+	closeTime, err := parseWeeklyTime(strings.TrimSpace(close))
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{Open: openTime, Close: closeTime}, nil
+}
+
+func parseWeeklyTime(raw string) (WeeklyTime, error) {
+	day, clock, found := strings.Cut(raw, " ")
+	if !found {
+		return WeeklyTime{}, fmt.Errorf("invalid fx session time %q", raw)
+	}
+
+	weekday := -1
+	for i := time.Sunday; i <= time.Saturday; i++ {
+		if strings.EqualFold(day, i.String()[:3]) {
+			weekday = int(i)
+		}
+	}
+	if weekday < 0 {
+		return WeeklyTime{}, fmt.Errorf("invalid fx session day %q", day)
+	}
+
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return WeeklyTime{}, err
+	}
+	return WeeklyTime{Day: time.Weekday(weekday), Hour: parsed.Hour(), Minute: parsed.Minute()}, nil
+}
@@ -1,11 +1,14 @@
 package reducer
 
 import (
-	"math"
+	"encoding/json"
+	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 
 	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
 )
 
 type Reducer struct {
@@ -14,20 +17,60 @@ type Reducer struct {
 }
 
 func Reduce(raw interface{}, reducers []Reducer) (float64, error) {
-	var result float64
+	result, err := ReduceDecimal(raw, reducers)
+	if err != nil {
+		return 0, err
+	}
+	value, _ := result.Float64()
+	return value, nil
+}
+
+// ReduceDecimal runs the reducers on exact decimals, numbers only lose digits through ROUND
+func ReduceDecimal(raw interface{}, reducers []Reducer) (*big.Rat, error) {
 	var err error
 	for _, reducer := range reducers {
 		raw, err = reduce(raw, reducer)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	}
 
-	result, ok := raw.(float64)
-	if !ok {
-		return 0, errorSentinel.ErrReducerCastToFloatFail
+	switch raw.(type) {
+	case *big.Rat, float64, json.Number:
+		return tryParseDecimal(raw)
+	default:
+		return nil, errorSentinel.ErrReducerCastToFloatFail
+	}
+}
+
+// Rescale moves reducers scaling into fromDecimals through POW10 over to toDecimals. The last POW10 absorbs the
+// difference so scaling still happens before any ROUND, reducers scaling into anything else are rejected.
+func Rescale(reducers []Reducer, fromDecimals, toDecimals int) ([]Reducer, error) {
+	if fromDecimals == toDecimals {
+		return reducers, nil
 	}
-	return result, nil
+
+	last := -1
+	total := 0.0
+	for i, reducer := range reducers {
+		if reducer.Function != "POW10" {
+			continue
+		}
+		arg, err := tryParseFloat(reducer.Args)
+		if err != nil {
+			return nil, err
+		}
+		total += arg
+		last = i
+	}
+	if last < 0 || total != float64(fromDecimals) {
+		return nil, errorSentinel.ErrReducerDecimalsMismatch
+	}
+
+	lastArg, _ := tryParseFloat(reducers[last].Args)
+	rescaled := slices.Clone(reducers)
+	rescaled[last] = Reducer{Function: "POW10", Args: lastArg + float64(toDecimals-fromDecimals)}
+	return rescaled, nil
 }
 
 func reduce(raw interface{}, reducer Reducer) (interface{}, error) {
@@ -69,18 +112,18 @@ func reduce(raw interface{}, reducer Reducer) (interface{}, error) {
 		}
 		return raw, nil
 	case "MUL":
-		castedRaw, err := tryParseFloat(raw)
+		castedRaw, err := tryParseDecimal(raw)
 		if err != nil {
 			return nil, err
 		}
-		arg, ok := reducer.Args.(float64)
-		if !ok {
-			return nil, errorSentinel.ErrReducerMulCastToFloatFail
+		arg, err := decimalArg(reducer.Args, errorSentinel.ErrReducerMulCastToFloatFail)
+		if err != nil {
+			return nil, err
 		}
 
-		return castedRaw * arg, nil
+		return castedRaw.Mul(castedRaw, arg), nil
 	case "POW10":
-		castedRaw, err := tryParseFloat(raw)
+		castedRaw, err := tryParseDecimal(raw)
 		if err != nil {
 			return nil, err
 		}
@@ -89,36 +132,40 @@ func reduce(raw interface{}, reducer Reducer) (interface{}, error) {
 			return nil, err
 		}
 
-		return float64(math.Pow10(int(arg))) * castedRaw, nil
+		return decimal.Scale(castedRaw, int(arg)), nil
 	case "ROUND":
-		castedRaw, err := tryParseFloat(raw)
+		castedRaw, err := tryParseDecimal(raw)
 		if err != nil {
 			return nil, err
 		}
-		return math.Round(castedRaw), nil
+		// half away from zero, the same on every node
+		return new(big.Rat).SetInt(decimal.Round(castedRaw)), nil
 	case "DIV":
-		castedRaw, err := tryParseFloat(raw)
+		castedRaw, err := tryParseDecimal(raw)
 		if err != nil {
 			return nil, err
 		}
-		arg, ok := reducer.Args.(float64)
-		if !ok {
-			return nil, errorSentinel.ErrReducerDivCastToFloatFail
+		arg, err := decimalArg(reducer.Args, errorSentinel.ErrReducerDivCastToFloatFail)
+		if err != nil {
+			return nil, err
 		}
-		if arg == 0 {
+		if arg.Sign() == 0 {
 			return nil, errorSentinel.ErrReducerDivDivsionByZero
 		}
-		return castedRaw / arg, nil
+		return castedRaw.Quo(castedRaw, arg), nil
 	case "DIVFROM":
-		castedRaw, err := tryParseFloat(raw)
+		castedRaw, err := tryParseDecimal(raw)
 		if err != nil {
 			return nil, err
 		}
-		arg, ok := reducer.Args.(float64)
-		if !ok {
-			return nil, errorSentinel.ErrReducerDivFromCastToFloatFail
+		arg, err := decimalArg(reducer.Args, errorSentinel.ErrReducerDivFromCastToFloatFail)
+		if err != nil {
+			return nil, err
 		}
-		return arg / castedRaw, nil
+		if castedRaw.Sign() == 0 {
+			return nil, errorSentinel.ErrReducerDivFromDivisionByZero
+		}
+		return arg.Quo(arg, castedRaw), nil
 	default:
 		return nil, errorSentinel.ErrReducerUnknownReducerFunc
 	}
@@ -141,3 +188,25 @@ func tryParseFloat(raw interface{}) (float64, error) {
 	}
 	return 0, errorSentinel.ErrReducerCastToFloatFail
 }
+
+// numbers in the response may be float64, json.Number when decoded with UseNumber, or strings
+func tryParseDecimal(raw interface{}) (*big.Rat, error) {
+	value, err := decimal.Parse(raw)
+	if err != nil {
+		return nil, errorSentinel.ErrReducerCastToFloatFail
+	}
+	return value, nil
+}
+
+// reducer args come from the definition json and have to be numbers
+func decimalArg(arg interface{}, castErr error) (*big.Rat, error) {
+	f, ok := arg.(float64)
+	if !ok {
+		return nil, castErr
+	}
+	value, err := decimal.FromFloat64(f)
+	if err != nil {
+		return nil, castErr
+	}
+	return value, nil
+}
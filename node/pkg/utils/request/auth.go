@@ -0,0 +1,378 @@
+package request
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/secrets"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	AuthTypeApiKey = "apiKey"
+	AuthTypeBearer = "bearer"
+	AuthTypeHmac   = "hmac"
+	AuthTypeOAuth2 = "oauth2"
+
+	HmacEncodingHex    = "hex"
+	HmacEncodingBase64 = "base64"
+
+	HmacTimestampMs  = "ms"
+	HmacTimestampS   = "s"
+	HmacTimestampIso = "iso"
+
+	DefaultApiKeyHeader = "X-API-Key"
+	// tokens are refreshed this long before they expire
+	TokenRefreshMargin = 30 * time.Second
+	// used when the token endpoint omits expires_in
+	DefaultTokenLifetime = 5 * time.Minute
+)
+
+// secret names only, so a definition can't carry the secret itself
+var secretNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Auth describes how a request is authenticated. Fields referring to credentials hold secret names
+// resolved through secrets.GetSecret at request time, never the credentials themselves.
+//
+//	{"type": "apiKey", "apiKey": "BINANCE_API_KEY", "header": "X-MBX-APIKEY"}
+//	{"type": "bearer", "apiKey": "CMC_TOKEN"}
+//	{"type": "hmac", "apiKey": "BINANCE_API_KEY", "apiSecret": "BINANCE_API_SECRET", "header": "X-MBX-APIKEY",
+//	 "hmac": {"payload": "{query}", "query": {"timestamp": "{timestamp}", "signature": "{signature}"}}}
+//	{"type": "oauth2", "token": {"url": "https://auth.example.com/token", "clientId": "EX_CLIENT_ID", "clientSecret": "EX_CLIENT_SECRET"}}
+type Auth struct {
+	Type      string     `json:"type"`
+	ApiKey    string     `json:"apiKey,omitempty"`    // secret name of the api key, or of the token for bearer
+	ApiSecret string     `json:"apiSecret,omitempty"` // secret name of the hmac signing key
+	Header    string     `json:"header,omitempty"`    // header carrying the api key, defaults to DefaultApiKeyHeader
+	Query     string     `json:"query,omitempty"`     // query parameter carrying the api key instead of a header
+	Hmac      *HmacAuth  `json:"hmac,omitempty"`
+	Token     *TokenAuth `json:"token,omitempty"`
+}
+
+// HmacAuth signs Payload with HMAC-SHA256, templates may use the placeholders
+// {timestamp} {nonce} {method} {path} {query} {body} {apiKey}, Headers and Query also {signature}.
+// Query parameters without {signature} are added before signing, so they are part of {query}.
+type HmacAuth struct {
+	Payload   string            `json:"payload"`
+	Encoding  string            `json:"encoding,omitempty"`  // hex or base64, defaults to hex
+	Timestamp string            `json:"timestamp,omitempty"` // ms, s or iso, defaults to ms
+	Headers   map[string]string `json:"headers,omitempty"`
+	Query     map[string]string `json:"query,omitempty"`
+}
+
+// TokenAuth fetches bearer tokens with the OAuth2 client credentials grant
+type TokenAuth struct {
+	Url          string `json:"url"`
+	ClientId     string `json:"clientId"`     // secret name
+	ClientSecret string `json:"clientSecret"` // secret name
+	Scope        string `json:"scope,omitempty"`
+}
+
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokens are shared by every request using the same token endpoint and client
+var tokenCache = struct {
+	sync.Mutex
+	tokens map[string]cachedToken
+}{tokens: make(map[string]cachedToken)}
+
+func WithAuth(auth *Auth) RequestOption {
+	return func(config *RequestConfig) {
+		config.Auth = auth
+	}
+}
+
+// Validate checks the auth is complete and refers to secrets by name only
+func (a *Auth) Validate() error {
+	if a == nil {
+		return nil
+	}
+
+	names := []string{}
+	switch a.Type {
+	case AuthTypeApiKey, AuthTypeBearer:
+		names = append(names, a.ApiKey)
+	case AuthTypeHmac:
+		if a.Hmac == nil || a.Hmac.Payload == "" {
+			log.Error().Str("type", a.Type).Msg("missing hmac payload")
+			return errorSentinel.ErrRequestInvalidAuth
+		}
+		if a.Hmac.Encoding != "" && a.Hmac.Encoding != HmacEncodingHex && a.Hmac.Encoding != HmacEncodingBase64 {
+			log.Error().Str("encoding", a.Hmac.Encoding).Msg("invalid hmac encoding")
+			return errorSentinel.ErrRequestInvalidAuth
+		}
+		if a.Hmac.Timestamp != "" && a.Hmac.Timestamp != HmacTimestampMs && a.Hmac.Timestamp != HmacTimestampS && a.Hmac.Timestamp != HmacTimestampIso {
+			log.Error().Str("timestamp", a.Hmac.Timestamp).Msg("invalid hmac timestamp")
+			return errorSentinel.ErrRequestInvalidAuth
+		}
+		names = append(names, a.ApiSecret)
+		if a.ApiKey != "" {
+			names = append(names, a.ApiKey)
+		}
+	case AuthTypeOAuth2:
+		if a.Token == nil || a.Token.Url == "" {
+			log.Error().Str("type", a.Type).Msg("missing token url")
+			return errorSentinel.ErrRequestInvalidAuth
+		}
+		names = append(names, a.Token.ClientId, a.Token.ClientSecret)
+	default:
+		log.Error().Str("type", a.Type).Msg("invalid auth type")
+		return errorSentinel.ErrRequestInvalidAuth
+	}
+
+	for _, name := range names {
+		// the value is not logged, it might be a secret pasted by mistake
+		if !secretNameRegex.MatchString(name) {
+			log.Error().Str("type", a.Type).Msg("auth credentials must be secret names")
+			return errorSentinel.ErrRequestInvalidAuth
+		}
+	}
+	return nil
+}
+
+// apply authenticates req in place, the endpoint kept in the config stays free of credentials
+func (a *Auth) apply(client *http.Client, req *http.Request, body []byte) error {
+	err := a.Validate()
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case AuthTypeApiKey:
+		apiKey, err := resolveSecret(a.ApiKey)
+		if err != nil {
+			return err
+		}
+		a.setApiKey(req, apiKey)
+	case AuthTypeBearer:
+		token, err := resolveSecret(a.ApiKey)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case AuthTypeHmac:
+		return a.sign(req, body)
+	case AuthTypeOAuth2:
+		token, err := a.Token.get(client)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func (a *Auth) setApiKey(req *http.Request, apiKey string) {
+	if a.Query != "" {
+		query := req.URL.Query()
+		query.Set(a.Query, apiKey)
+		req.URL.RawQuery = query.Encode()
+		return
+	}
+
+	header := a.Header
+	if header == "" {
+		header = DefaultApiKeyHeader
+	}
+	req.Header.Set(header, apiKey)
+}
+
+func (a *Auth) sign(req *http.Request, body []byte) error {
+	secret, err := resolveSecret(a.ApiSecret)
+	if err != nil {
+		return err
+	}
+
+	apiKey := ""
+	if a.ApiKey != "" {
+		apiKey, err = resolveSecret(a.ApiKey)
+		if err != nil {
+			return err
+		}
+		a.setApiKey(req, apiKey)
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		"timestamp": hmacTimestamp(a.Hmac.Timestamp, time.Now()),
+		"nonce":     nonce,
+		"method":    req.Method,
+		"path":      req.URL.EscapedPath(),
+		"body":      string(body),
+		"apiKey":    apiKey,
+	}
+
+	query := req.URL.Query()
+	for key, template := range a.Hmac.Query {
+		if !strings.Contains(template, "{signature}") {
+			query.Set(key, expandTemplate(template, values))
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+	values["query"] = req.URL.RawQuery
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(expandTemplate(a.Hmac.Payload, values)))
+	if a.Hmac.Encoding == HmacEncodingBase64 {
+		values["signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	} else {
+		values["signature"] = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	// appended after the signed parameters, as the signature can't be part of its own payload
+	for key, template := range a.Hmac.Query {
+		if strings.Contains(template, "{signature}") {
+			if req.URL.RawQuery != "" {
+				req.URL.RawQuery += "&"
+			}
+			req.URL.RawQuery += url.QueryEscape(key) + "=" + url.QueryEscape(expandTemplate(template, values))
+		}
+	}
+	for key, template := range a.Hmac.Headers {
+		req.Header.Set(key, expandTemplate(template, values))
+	}
+	return nil
+}
+
+func (t *TokenAuth) cacheKey() string {
+	return t.Url + "|" + t.ClientId + "|" + t.Scope
+}
+
+// get returns the cached token, requesting a new one when it is missing or about to expire
+func (t *TokenAuth) get(client *http.Client) (string, error) {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+
+	cached, exists := tokenCache.tokens[t.cacheKey()]
+	if exists && time.Until(cached.expiresAt) > TokenRefreshMargin {
+		return cached.accessToken, nil
+	}
+
+	clientId, err := resolveSecret(t.ClientId)
+	if err != nil {
+		return "", err
+	}
+	clientSecret, err := resolveSecret(t.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if t.Scope != "" {
+		form.Set("scope", t.Scope)
+	}
+	req, err := http.NewRequest(http.MethodPost, t.Url, strings.NewReader(form.Encode()))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create token request")
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientId, clientSecret)
+
+	response, err := client.Do(req)
+	if err != nil {
+		err = redactUrlError(err)
+		log.Error().Err(err).Msg("failed to request token")
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		log.Error().Int("status", response.StatusCode).Str("url", t.Url).Msg("failed to request token")
+		return "", errorSentinel.ErrRequestStatusNotOk
+	}
+
+	resultBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read token response body")
+		return "", err
+	}
+
+	var result tokenResponse
+	err = json.Unmarshal(resultBody, &result)
+	if err != nil {
+		log.Error().Err(err).Str("url", t.Url).Msg("failed to unmarshal token response")
+		return "", err
+	}
+	if result.AccessToken == "" {
+		log.Error().Str("url", t.Url).Msg("empty access token in token response")
+		return "", errorSentinel.ErrRequestAuthTokenNotFound
+	}
+
+	lifetime := DefaultTokenLifetime
+	if result.ExpiresIn > 0 {
+		lifetime = time.Duration(result.ExpiresIn) * time.Second
+	}
+	tokenCache.tokens[t.cacheKey()] = cachedToken{accessToken: result.AccessToken, expiresAt: time.Now().Add(lifetime)}
+	return result.AccessToken, nil
+}
+
+// invalidate drops the cached token, e.g. when it was revoked before expiring
+func (t *TokenAuth) invalidate() {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	delete(tokenCache.tokens, t.cacheKey())
+}
+
+func resolveSecret(name string) (string, error) {
+	value := secrets.GetSecret(name)
+	if value == "" {
+		log.Error().Str("secret", name).Msg("auth secret not set")
+		return "", errorSentinel.ErrRequestAuthSecretNotFound
+	}
+	return value, nil
+}
+
+func hmacTimestamp(format string, now time.Time) string {
+	switch format {
+	case HmacTimestampS:
+		return strconv.FormatInt(now.Unix(), 10)
+	case HmacTimestampIso:
+		return now.UTC().Format("2006-01-02T15:04:05.000Z")
+	default:
+		return strconv.FormatInt(now.UnixMilli(), 10)
+	}
+}
+
+func newNonce() (string, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate nonce")
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+func expandTemplate(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for key, value := range values {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -3,6 +3,7 @@ package request
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -12,15 +13,22 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-const DefaultTimeout = 2 * time.Second
+const (
+	DefaultTimeout     = 2 * time.Second
+	DefaultContentType = "application/json"
+)
 
 type RequestConfig struct {
-	Timeout  time.Duration
-	Endpoint string
-	Body     interface{}
-	Headers  map[string]string
-	Proxy    string
-	Method   string
+	Timeout     time.Duration
+	Endpoint    string
+	Body        interface{}
+	RawBody     []byte
+	ContentType string
+	Headers     map[string]string
+	Proxy       string
+	Method      string
+	Auth        *Auth
+	UseNumber   bool
 }
 
 type RequestOption func(*RequestConfig)
@@ -43,6 +51,19 @@ func WithBody(body interface{}) RequestOption {
 	}
 }
 
+// WithRawBody sends body as is instead of marshalling Body to json, e.g. form encoded payloads
+func WithRawBody(body []byte) RequestOption {
+	return func(config *RequestConfig) {
+		config.RawBody = body
+	}
+}
+
+func WithContentType(contentType string) RequestOption {
+	return func(config *RequestConfig) {
+		config.ContentType = contentType
+	}
+}
+
 func WithHeaders(headers map[string]string) RequestOption {
 	return func(config *RequestConfig) {
 		config.Headers = headers
@@ -61,6 +82,13 @@ func WithMethod(method string) RequestOption {
 	}
 }
 
+// WithUseNumber decodes numbers in the response into json.Number instead of float64, keeping every digit
+func WithUseNumber() RequestOption {
+	return func(config *RequestConfig) {
+		config.UseNumber = true
+	}
+}
+
 func Request[T any](opts ...RequestOption) (T, error) {
 	var result T
 
@@ -92,7 +120,11 @@ func Request[T any](opts ...RequestOption) (T, error) {
 		return result, err
 	}
 
-	err = json.Unmarshal(resultBody, &result)
+	decoder := json.NewDecoder(bytes.NewReader(resultBody))
+	if config.UseNumber {
+		decoder.UseNumber()
+	}
+	err = decoder.Decode(&result)
 	if err != nil {
 		log.Error().Err(err).Str("resultBody", string(resultBody)).Msg("failed to unmarshal response body")
 		return result, err
@@ -113,15 +145,15 @@ func RequestRaw(opts ...RequestOption) (*http.Response, error) {
 }
 
 func requestRaw(config RequestConfig) (*http.Response, error) {
-	var body io.Reader
+	payload := config.RawBody
 
-	if config.Body != nil {
+	if payload == nil && config.Body != nil {
 		marshalledData, err := json.Marshal(config.Body)
 		if err != nil {
 			log.Error().Err(err).Msg("failed to marshal request body")
 			return nil, err
 		}
-		body = bytes.NewReader(marshalledData)
+		payload = marshalledData
 	}
 
 	url, err := url.Parse(config.Endpoint)
@@ -136,9 +168,70 @@ func requestRaw(config RequestConfig) (*http.Response, error) {
 		return nil, errorSentinel.ErrRequestInvalidMethod
 	}
 
+	client := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	if config.Proxy != "" {
+		err := setProxy(client, config.Proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := newRequest(client, config, url.String(), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, redactUrlError(err)
+	}
+	if response.StatusCode != http.StatusUnauthorized || config.Auth == nil || config.Auth.Type != AuthTypeOAuth2 {
+		return response, err
+	}
+
+	// the cached token may have been revoked before expiring, retry once with a fresh one
+	response.Body.Close()
+	config.Auth.Token.invalidate()
+	req, err = newRequest(client, config, url.String(), payload)
+	if err != nil {
+		return nil, err
+	}
+	response, err = client.Do(req)
+	if err != nil {
+		return nil, redactUrlError(err)
+	}
+	return response, nil
+}
+
+// redactUrlError drops the query from the url of transport errors, it may carry api keys, signatures and timestamps
+func redactUrlError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	parsed, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return &url.Error{Op: urlErr.Op, URL: "", Err: urlErr.Err}
+	}
+	redacted := url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: parsed.Path}
+	return &url.Error{Op: urlErr.Op, URL: redacted.String(), Err: urlErr.Err}
+}
+
+// newRequest builds an authenticated request, signatures and tokens are applied to the request only
+// so the endpoint in the config, which is logged, never carries credentials
+func newRequest(client *http.Client, config RequestConfig, endpoint string, payload []byte) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
 	req, err := http.NewRequest(
 		config.Method,
-		url.String(),
+		endpoint,
 		body,
 	)
 	if err != nil {
@@ -146,30 +239,30 @@ func requestRaw(config RequestConfig) (*http.Response, error) {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = DefaultContentType
+	}
+	req.Header.Set("Content-Type", contentType)
 	if len(config.Headers) > 0 {
 		for key, value := range config.Headers {
 			req.Header.Set(key, value)
 		}
 	}
 
-	client := &http.Client{
-		Timeout: config.Timeout,
-	}
-
-	if config.Proxy != "" {
-		err := setProxy(client, config.Proxy)
+	if config.Auth != nil {
+		err = config.Auth.apply(client, req, payload)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if url.Scheme == "https" {
-			url.Scheme = "http"
-			req.URL = url
-		}
+	// authenticated requests stay on https, the transport tunnels them through the proxy so credentials never reach it in plaintext
+	if config.Proxy != "" && config.Auth == nil && req.URL.Scheme == "https" {
+		req.URL.Scheme = "http"
 	}
 
-	return client.Do(req)
+	return req, nil
 }
 
 func setProxy(client *http.Client, proxyURL string) error {
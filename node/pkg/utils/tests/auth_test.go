@@ -0,0 +1,185 @@
+//nolint:all
+package tests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"bisonai.com/miko/node/pkg/utils/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestAuthApiKey(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "key-value")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test-Key") != "key-value" && r.URL.Query().Get("apikey") != "key-value" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	for _, auth := range []*request.Auth{
+		{Type: request.AuthTypeApiKey, ApiKey: "TEST_API_KEY", Header: "X-Test-Key"},
+		{Type: request.AuthTypeApiKey, ApiKey: "TEST_API_KEY", Query: "apikey"},
+	} {
+		result, err := request.Request[TestResponse](request.WithEndpoint(server.URL+"?symbol=BTC"), request.WithAuth(auth))
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Message)
+	}
+
+	_, err := request.Request[TestResponse](request.WithEndpoint(server.URL), request.WithAuth(&request.Auth{Type: request.AuthTypeApiKey, ApiKey: "TEST_MISSING_KEY"}))
+	assert.Error(t, err)
+}
+
+func TestRequestAuthHmac(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "key-value")
+	t.Setenv("TEST_API_SECRET", "secret-value")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery := r.URL.RawQuery
+		index := strings.Index(rawQuery, "&signature=")
+		if index < 0 || r.URL.Query().Get("timestamp") == "" || r.Header.Get("X-Test-Key") != "key-value" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte("secret-value"))
+		mac.Write([]byte(rawQuery[:index]))
+		if r.URL.Query().Get("signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	auth := &request.Auth{
+		Type:      request.AuthTypeHmac,
+		ApiKey:    "TEST_API_KEY",
+		ApiSecret: "TEST_API_SECRET",
+		Header:    "X-Test-Key",
+		Hmac: &request.HmacAuth{
+			Payload: "{query}",
+			Query:   map[string]string{"timestamp": "{timestamp}", "signature": "{signature}"},
+		},
+	}
+	result, err := request.Request[TestResponse](request.WithEndpoint(server.URL+"/api/v3/account?recvWindow=5000"), request.WithAuth(auth))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Message)
+}
+
+func TestRequestAuthOAuth2(t *testing.T) {
+	t.Setenv("TEST_CLIENT_ID", "client")
+	t.Setenv("TEST_CLIENT_SECRET", "client-secret")
+
+	var issued atomic.Int32
+	var revoked atomic.Bool
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+		if !ok || clientId != "client" || clientSecret != "client-secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		issued.Add(1)
+		if issued.Load() == 1 {
+			w.Write([]byte(`{"access_token":"first","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"second","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "Bearer second" || (authorization == "Bearer first" && !revoked.Load()) {
+			w.Write([]byte(`{"message":"ok"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	auth := &request.Auth{
+		Type:  request.AuthTypeOAuth2,
+		Token: &request.TokenAuth{Url: tokenServer.URL, ClientId: "TEST_CLIENT_ID", ClientSecret: "TEST_CLIENT_SECRET"},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := request.Request[TestResponse](request.WithEndpoint(server.URL), request.WithAuth(auth))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), issued.Load())
+
+	// a revoked token is refreshed once
+	revoked.Store(true)
+	result, err := request.Request[TestResponse](request.WithEndpoint(server.URL), request.WithAuth(auth))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result.Message)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestAuthValidate(t *testing.T) {
+	assert.NoError(t, (*request.Auth)(nil).Validate())
+	assert.NoError(t, (&request.Auth{Type: request.AuthTypeBearer, ApiKey: "CMC_TOKEN"}).Validate())
+
+	invalid := []*request.Auth{
+		{Type: "basic", ApiKey: "API_KEY"},
+		{Type: request.AuthTypeApiKey, ApiKey: "sk-live-1234"},
+		{Type: request.AuthTypeHmac, ApiSecret: "API_SECRET"},
+		{Type: request.AuthTypeHmac, ApiSecret: "API_SECRET", Hmac: &request.HmacAuth{Payload: "{query}", Encoding: "base32"}},
+		{Type: request.AuthTypeOAuth2, Token: &request.TokenAuth{Url: "https://auth.example.com", ClientId: "CLIENT_ID", ClientSecret: "raw secret"}},
+	}
+	for _, auth := range invalid {
+		assert.Error(t, auth.Validate())
+	}
+}
+
+func TestRequestAuthSecretsNotInErrors(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "key-value")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddress := listener.Addr().String()
+	listener.Close()
+
+	auth := &request.Auth{Type: request.AuthTypeApiKey, ApiKey: "TEST_API_KEY", Query: "apikey"}
+	_, err = request.Request[TestResponse](request.WithEndpoint("http://"+closedAddress+"/prices?symbol=BTC"), request.WithAuth(auth))
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "key-value")
+	assert.NotContains(t, err.Error(), "symbol=BTC")
+	assert.Contains(t, err.Error(), closedAddress+"/prices")
+}
+
+func TestRequestAuthKeepsHttpsThroughProxy(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "key-value")
+
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.Method + " " + r.URL.String())
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	auth := &request.Auth{Type: request.AuthTypeApiKey, ApiKey: "TEST_API_KEY", Query: "apikey"}
+	_, err := request.Request[TestResponse](
+		request.WithEndpoint("https://example.invalid/prices"),
+		request.WithProxy(proxy.URL),
+		request.WithAuth(auth),
+	)
+	assert.Error(t, err)
+
+	// the proxy only sees the tunnel, never the authenticated request
+	seen, _ := proxied.Load().(string)
+	assert.True(t, strings.HasPrefix(seen, "CONNECT "), seen)
+	assert.NotContains(t, seen, "key-value")
+}
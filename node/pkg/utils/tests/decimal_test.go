@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalRound(t *testing.T) {
+	cases := map[string]int64{
+		"2.5":      3,
+		"-2.5":     -3,
+		"2.4999":   2,
+		"-2.4999":  -2,
+		"0.5":      1,
+		"7":        7,
+		"1/3":      0,
+		"-5/3":     -2,
+		"99999.51": 100000,
+	}
+	for raw, expected := range cases {
+		value, ok := new(big.Rat).SetString(raw)
+		assert.True(t, ok)
+		assert.Equal(t, expected, decimal.Round(value).Int64(), raw)
+	}
+}
+
+func TestDecimalParse(t *testing.T) {
+	expected := big.NewRat(1, 10)
+	for _, raw := range []any{0.1, "0.1", json.Number("0.1"), " 0.1 ", big.NewRat(1, 10)} {
+		value, err := decimal.Parse(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expected.Cmp(value), raw)
+	}
+
+	value, err := decimal.Parse("1,234.5")
+	assert.NoError(t, err)
+	assert.Equal(t, "2469/2", value.RatString())
+
+	_, err = decimal.Parse("abc")
+	assert.Error(t, err)
+	_, err = decimal.Parse(true)
+	assert.Error(t, err)
+}
+
+func TestDecimalScale(t *testing.T) {
+	// multiplying in float64 gives 1.4999999999999998 which rounds to 1
+	value, err := decimal.FromFloat64(1.5e-8)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), decimal.Round(decimal.Scale(value, 8)))
+	assert.Equal(t, big.NewInt(6412312345679), decimal.ScaleInt(big.NewInt(64123123456785), -1))
+	assert.Equal(t, big.NewInt(-3), decimal.ScaleInt(big.NewInt(-25), -1))
+	assert.Equal(t, big.NewInt(10000000000), decimal.ScaleInt(big.NewInt(1), 10))
+	assert.Equal(t, "1/1000", decimal.Pow10(-3).RatString())
+
+	median := decimal.Median([]*big.Rat{big.NewRat(3, 1), big.NewRat(1, 1), big.NewRat(2, 1), big.NewRat(4, 1)})
+	assert.Equal(t, "5/2", median.RatString())
+}
@@ -0,0 +1,53 @@
+//nolint:all
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/utils/fxcalendar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFxCalendar(t *testing.T) {
+	calendar, err := fxcalendar.New(fxcalendar.WithHolidays("2024-12-25"))
+	assert.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	at := func(date string, clock string) time.Time {
+		result, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, newYork)
+		assert.NoError(t, err)
+		return result
+	}
+
+	// 2024-06-07 is a friday
+	assert.True(t, calendar.IsOpen(at("2024-06-07", "16:59")))
+	assert.False(t, calendar.IsOpen(at("2024-06-07", "17:00")))
+	assert.False(t, calendar.IsOpen(at("2024-06-08", "12:00")))
+	assert.False(t, calendar.IsOpen(at("2024-06-09", "16:59")))
+	assert.True(t, calendar.IsOpen(at("2024-06-09", "17:00")))
+	assert.True(t, calendar.IsOpen(at("2024-06-12", "03:00")))
+	assert.True(t, calendar.IsOpen(at("2024-06-12", "03:00").UTC()))
+	assert.False(t, calendar.IsOpen(at("2024-12-25", "10:00")))
+
+	wrapping, err := fxcalendar.New(fxcalendar.WithTimezone("UTC"), fxcalendar.WithSessions("Mon 09:00-Mon 17:00, Fri 22:00-Mon 02:00"))
+	assert.NoError(t, err)
+	assert.True(t, wrapping.IsOpen(time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, wrapping.IsOpen(time.Date(2024, 6, 10, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, wrapping.IsOpen(time.Date(2024, 6, 10, 5, 0, 0, 0, time.UTC)))
+	assert.True(t, wrapping.IsOpen(time.Date(2024, 6, 10, 10, 0, 0, 0, time.UTC)))
+	assert.False(t, wrapping.IsOpen(time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC)))
+
+	_, err = fxcalendar.New(fxcalendar.WithSessions("Sun 17:00"))
+	assert.Error(t, err)
+	_, err = fxcalendar.New(fxcalendar.WithSessions("Sunday 17:00-Fri 17:00"))
+	assert.Error(t, err)
+
+	assert.True(t, fxcalendar.IsFXPricePair("EUR-USD"))
+	assert.False(t, fxcalendar.IsFXPricePair("BTC-USDT"))
+	assert.False(t, fxcalendar.IsFXPricePair("USD"))
+	assert.False(t, fxcalendar.IsFXPricePair("EUR-USDT"))
+	assert.False(t, fxcalendar.IsFXPricePair("D-USD"))
+	assert.False(t, fxcalendar.IsFXPricePair(""))
+}
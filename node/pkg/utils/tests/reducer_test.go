@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/utils/reducer"
 	"github.com/stretchr/testify/assert"
 )
@@ -90,3 +91,52 @@ func TestReduceAll(t *testing.T) {
 	}
 	assert.NotEqual(t, result, 0)
 }
+
+func TestReduceExact(t *testing.T) {
+	var red []reducer.Reducer
+	err := json.Unmarshal([]byte(`[{"function": "PARSE", "args": ["price"]}, {"function": "POW10", "args": 2}, {"function": "ROUND"}]`), &red)
+	assert.NoError(t, err)
+
+	// 1.005 * 100 is 100.49999999999999 in float64, every node has to arrive at 101 however the price was decoded
+	for _, price := range []interface{}{1.005, json.Number("1.005"), "1.005"} {
+		result, err := reducer.ReduceDecimal(map[string]interface{}{"price": price}, red)
+		assert.NoError(t, err)
+		assert.Equal(t, "101", result.RatString())
+	}
+
+	result, err := reducer.Reduce(map[string]interface{}{"price": json.Number("1.005")}, red)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(101), result)
+
+	_, err = reducer.Reduce(0.0, []reducer.Reducer{{Function: "DIVFROM", Args: 1.0}})
+	assert.Error(t, err)
+}
+
+func TestRescale(t *testing.T) {
+	var red []reducer.Reducer
+	err := json.Unmarshal([]byte(`[{"function": "PARSE", "args": ["price"]}, {"function": "POW10", "args": 8}, {"function": "ROUND"}]`), &red)
+	assert.NoError(t, err)
+
+	unchanged, err := reducer.Rescale(red, 8, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, red, unchanged)
+
+	// scaling into 6 decimals happens before ROUND, the original reducers are kept for other configs
+	rescaled, err := reducer.Rescale(red, 8, 6)
+	assert.NoError(t, err)
+	result, err := reducer.ReduceDecimal(map[string]interface{}{"price": "65000.1234567"}, rescaled)
+	assert.NoError(t, err)
+	assert.Equal(t, "65000123457", result.RatString())
+	assert.Equal(t, float64(8), red[1].Args)
+
+	rescaled, err = reducer.Rescale(red, 8, 9)
+	assert.NoError(t, err)
+	result, err = reducer.ReduceDecimal(map[string]interface{}{"price": "0.0000012345"}, rescaled)
+	assert.NoError(t, err)
+	assert.Equal(t, "1235", result.RatString())
+
+	_, err = reducer.Rescale([]reducer.Reducer{{Function: "PARSE", Args: []interface{}{"price"}}}, 8, 6)
+	assert.ErrorIs(t, err, errorSentinel.ErrReducerDecimalsMismatch)
+	_, err = reducer.Rescale([]reducer.Reducer{{Function: "POW10", Args: 4.0}}, 8, 6)
+	assert.ErrorIs(t, err, errorSentinel.ErrReducerDecimalsMismatch)
+}
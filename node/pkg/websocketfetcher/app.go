@@ -1,15 +1,22 @@
 package websocketfetcher
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
 	"bisonai.com/miko/node/pkg/common/types"
 	"bisonai.com/miko/node/pkg/db"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/binance"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/bingx"
@@ -25,6 +32,7 @@ import (
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/crypto"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/gateio"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/gemini"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/generic"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/gopax"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/huobi"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/korbit"
@@ -35,8 +43,10 @@ import (
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/okx"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/orangex"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/uniswap"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/uniswapv2"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/upbit"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/xt"
+	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
 )
 
@@ -45,15 +55,22 @@ const (
 	DefaultBufferSize    = 3000
 )
 
+// providers able to serve mid and depth price sources
+var bookProviders = []string{"binance", "okx", "bybit", "coinbase", "upbit", "kraken"}
+
 type AppConfig struct {
 	SetFromDB           bool
 	Feeds               []common.Feed
 	CexFactories        map[string]func(context.Context, ...common.FetcherOption) (common.FetcherInterface, error)
+	GenericProviders    map[string]json.RawMessage
+	StreamLimits        map[string]int
 	DexFactories        map[string]func(...common.DexFetcherOption) common.FetcherInterface
 	BufferSize          int
 	StoreInterval       time.Duration
+	StaleThreshold      time.Duration
 	LatestFeedDataMap   *types.LatestFeedDataMap
 	FeedDataDumpChannel chan *types.FeedData
+	FeedDecimals        map[int32]int32
 }
 
 type AppOption func(*AppConfig)
@@ -76,6 +93,19 @@ func WithCexFactories(factories map[string]func(context.Context, ...common.Fetch
 	}
 }
 
+func WithGenericProviders(providers map[string]json.RawMessage) AppOption {
+	return func(c *AppConfig) {
+		c.GenericProviders = providers
+	}
+}
+
+// WithStreamLimits caps streams per connection by provider, feeds beyond the cap are sharded across connections
+func WithStreamLimits(limits map[string]int) AppOption {
+	return func(c *AppConfig) {
+		c.StreamLimits = limits
+	}
+}
+
 func WithBufferSize(size int) AppOption {
 	return func(c *AppConfig) {
 		c.BufferSize = size
@@ -88,6 +118,13 @@ func WithStoreInterval(interval time.Duration) AppOption {
 	}
 }
 
+// WithStaleThreshold sets how long a feed may go without data before it is reported stale
+func WithStaleThreshold(threshold time.Duration) AppOption {
+	return func(c *AppConfig) {
+		c.StaleThreshold = threshold
+	}
+}
+
 func WithLatestFeedDataMap(latestFeedDataMap *types.LatestFeedDataMap) AppOption {
 	return func(c *AppConfig) {
 		c.LatestFeedDataMap = latestFeedDataMap
@@ -100,14 +137,46 @@ func WithFeedDataDumpChannel(feedDataDumpChannel chan *types.FeedData) AppOption
 	}
 }
 
+// WithFeedDecimals sets the decimals prices of each feed are stored in, feeds without an entry use types.DefaultDecimals.
+// Loaded from the configs of the feeds when set from db.
+func WithFeedDecimals(feedDecimals map[int32]int32) AppOption {
+	return func(c *AppConfig) {
+		c.FeedDecimals = feedDecimals
+	}
+}
+
+// fetcherEntry is a running provider along with the feeds it was configured with,
+// kept so a refresh only touches providers whose feeds changed
+type fetcherEntry struct {
+	fetcher  common.FetcherInterface
+	feedMaps common.FeedMaps
+	feeds    []common.Feed
+	config   json.RawMessage
+	cancel   context.CancelFunc
+}
+
+type cexPlan struct {
+	factory  common.Factory
+	feedMaps common.FeedMaps
+	config   json.RawMessage
+}
+
 type App struct {
-	fetchers            []common.FetcherInterface
+	mu                  sync.Mutex
+	appConfig           AppConfig
+	cexFetchers         map[string]*fetcherEntry
+	dexFetchers         map[string]*fetcherEntry
 	buffer              chan *common.FeedData
 	storeInterval       time.Duration
 	chainReader         *websocketchainreader.ChainReader
 	latestFeedDataMap   *types.LatestFeedDataMap
 	feedDataDumpChannel chan *common.FeedData
-	cancel              context.CancelFunc
+	// set once in Init, loaded without mu so health checks never wait on a running Refresh
+	health       atomic.Pointer[HealthRegistry]
+	decimalsMu   sync.RWMutex
+	feedDecimals map[int32]int32
+	runCtx       context.Context
+	cancel       context.CancelFunc
 }
 
 func New() *App {
@@ -145,15 +214,24 @@ func (a *App) Init(ctx context.Context, opts ...AppOption) error {
 	}
 
 	dexFactories := map[string]func(...common.DexFetcherOption) common.FetcherInterface{
-		"uniswap": uniswap.New,
+		"uniswap":   uniswap.New,
+		"uniswapV2": uniswapv2.New,
+	}
+
+	streamLimits := map[string]int{
+		"binance": binance.MaxStreamsPerConnection,
+		"okx":     okx.MaxStreamsPerConnection,
+		"bybit":   bybit.MaxStreamsPerConnection,
 	}
 
 	appConfig := &AppConfig{
-		SetFromDB:     true,
-		CexFactories:  cexFactories,
-		DexFactories:  dexFactories,
-		BufferSize:    DefaultBufferSize,
-		StoreInterval: DefaultStoreInterval,
+		SetFromDB:      true,
+		CexFactories:   cexFactories,
+		StreamLimits:   streamLimits,
+		DexFactories:   dexFactories,
+		BufferSize:     DefaultBufferSize,
+		StoreInterval:  DefaultStoreInterval,
+		StaleThreshold: DefaultStaleThreshold,
 		LatestFeedDataMap: &types.LatestFeedDataMap{
 			FeedDataMap: make(map[int32]*types.FeedData),
 			Mu:          sync.RWMutex{},
@@ -165,103 +243,453 @@ func (a *App) Init(ctx context.Context, opts ...AppOption) error {
 		opt(appConfig)
 	}
 
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.appConfig = *appConfig
 	a.latestFeedDataMap = appConfig.LatestFeedDataMap
 	a.feedDataDumpChannel = appConfig.FeedDataDumpChannel
+	a.buffer = make(chan *common.FeedData, appConfig.BufferSize)
+	a.storeInterval = appConfig.StoreInterval
+	a.cexFetchers = make(map[string]*fetcherEntry)
+	a.dexFetchers = make(map[string]*fetcherEntry)
+	if a.health.Load() == nil {
+		a.health.Store(NewHealthRegistry(appConfig.StaleThreshold))
+	}
 
-	if err := a.initializeCex(ctx, *appConfig); err != nil {
+	if err := a.loadFeedDecimals(ctx); err != nil {
 		return err
 	}
 
-	if err := a.initializeDex(ctx, *appConfig); err != nil {
+	if err := a.initializeCex(ctx); err != nil {
+		return err
+	}
+
+	if err := a.initializeDex(ctx); err != nil {
+		return err
+	}
+
+	a.health.Load().SetProviderFeeds(a.providerFeeds())
+	return nil
+}
+
+// IsInitialized reports whether Init has been called, Refresh requires it
+func (a *App) IsInitialized() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cexFetchers != nil
+}
+
+func (a *App) initializeCex(ctx context.Context) error {
+	plans, err := a.loadCexPlans(ctx)
+	if err != nil {
 		return err
 	}
 
+	for name, plan := range plans {
+		entry, err := a.newCexEntry(ctx, name, plan)
+		if err != nil {
+			return err
+		}
+		a.cexFetchers[name] = entry
+	}
 	return nil
 }
 
-func (a *App) initializeCex(ctx context.Context, appConfig AppConfig) error {
+func (a *App) loadCexPlans(ctx context.Context) (map[string]cexPlan, error) {
 	var feeds []common.Feed
-	if appConfig.SetFromDB {
+	genericProviders := map[string]json.RawMessage{}
+	if a.appConfig.SetFromDB {
 		var err error
 		feeds, err = db.QueryRows[common.Feed](ctx, common.GetAllWebsocketFeedsQuery, nil)
 		if err != nil {
 			log.Error().Err(err).Msg("error in fetching feeds")
-			return err
+			return nil, err
+		}
+
+		providers, err := db.QueryRows[common.WebsocketProvider](ctx, common.GetAllWebsocketProvidersQuery, nil)
+		if err != nil {
+			log.Error().Err(err).Msg("error in fetching websocket providers")
+			return nil, err
+		}
+		for _, provider := range providers {
+			genericProviders[strings.ToLower(provider.Name)] = provider.Config
 		}
 	}
 
-	if len(appConfig.Feeds) > 0 {
-		feeds = appConfig.Feeds
+	if len(a.appConfig.Feeds) > 0 {
+		feeds = a.appConfig.Feeds
 	}
 	feedMap := common.GetWssFeedMap(feeds)
 
-	a.buffer = make(chan *common.FeedData, appConfig.BufferSize)
-	a.storeInterval = appConfig.StoreInterval
+	maps.Copy(genericProviders, a.appConfig.GenericProviders)
+	maps.Copy(genericProviders, common.GetWssProviderConfigs(feeds))
 
-	wsProxy := os.Getenv("WS_PROXY")
+	// hand written providers take precedence over config driven ones with the same name
+	plans := map[string]cexPlan{}
+	for name, factory := range a.appConfig.CexFactories {
+		plans[name] = cexPlan{factory: factory}
+	}
+	for name, config := range genericProviders {
+		if _, exists := plans[name]; exists {
+			log.Warn().Msgf("generic provider %s ignored, dedicated provider exists", name)
+			continue
+		}
+		plans[name] = cexPlan{factory: generic.NewFactory(name, config), config: config}
+	}
 
-	for name, factory := range appConfig.CexFactories {
+	for name, plan := range plans {
 		if _, ok := feedMap[name]; !ok {
 			log.Warn().Msgf("no feeds for %s", name)
+			delete(plans, name)
 			continue
 		}
-		fetcher, err := factory(
-			ctx,
-			common.WithFeedDataBuffer(a.buffer),
-			common.WithFeedMaps(feedMap[name]),
-			common.WithProxy(wsProxy),
-		)
-		if err != nil {
-			log.Error().Err(err).Msgf("error in creating %s fetcher", name)
-			return err
+		if len(feedMap[name].SeparatedBooks) > 0 && !slices.Contains(bookProviders, name) {
+			log.Warn().Msgf("order book price sources are not supported by %s, ignoring them", name)
 		}
-		a.fetchers = append(a.fetchers, fetcher)
+		if len(feedMap[name].Separated) == 0 && !slices.Contains(bookProviders, name) {
+			delete(plans, name)
+			continue
+		}
+		if limit := a.appConfig.StreamLimits[name]; limit > 0 {
+			plan.factory = common.NewShardedFactory(name, plan.factory, limit)
+		}
+		plan.feedMaps = feedMap[name]
+		plans[name] = plan
 	}
-	return nil
+	return plans, nil
 }
 
-func (a *App) initializeDex(ctx context.Context, appConfig AppConfig) error {
+func (a *App) newCexEntry(ctx context.Context, name string, plan cexPlan) (*fetcherEntry, error) {
+	fetcher, err := plan.factory(
+		ctx,
+		common.WithFeedDataBuffer(a.buffer),
+		common.WithFeedMaps(plan.feedMaps),
+		common.WithProxy(os.Getenv("WS_PROXY")),
+		common.WithConnectionOptions(wss.WithObserver(a.health.Load().Observer(name))),
+		common.WithShardObserver(func(shard int) wss.Observer { return a.health.Load().ShardObserver(name, shard) }),
+	)
+	if err != nil {
+		log.Error().Err(err).Msgf("error in creating %s fetcher", name)
+		return nil, err
+	}
+	return &fetcherEntry{fetcher: fetcher, feedMaps: plan.feedMaps, config: plan.config}, nil
+}
+
+func (a *App) initializeDex(ctx context.Context) error {
 	kaiaWebsocketUrl := os.Getenv("KAIA_WEBSOCKET_URL")
 	ethWebsocketUrl := os.Getenv("ETH_WEBSOCKET_URL")
-	if kaiaWebsocketUrl == "" || ethWebsocketUrl == "" {
-		log.Error().Msg("KAIA_WEBSOCKET_URL and ETH_WEBSOCKET_URL must be set")
-		return errors.New("KAIA_WEBSOCKET_URL and ETH_WEBSOCKET_URL must be set")
+
+	chains, err := websocketchainreader.LoadChainConfigs(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error in loading chain configs")
+		return err
+	}
+
+	if kaiaWebsocketUrl == "" && ethWebsocketUrl == "" && len(chains) == 0 {
+		log.Error().Msg("no websocket url set, KAIA_WEBSOCKET_URL, ETH_WEBSOCKET_URL, CHAIN_WEBSOCKET_URLS or provider_urls required")
+		return errors.New("no websocket url set for dex fetchers")
 	}
 
 	chainReader, err := websocketchainreader.New(
 		websocketchainreader.WithEthWebsocketUrl(ethWebsocketUrl),
-		websocketchainreader.WithKaiaWebsocketUrl(kaiaWebsocketUrl))
+		websocketchainreader.WithKaiaWebsocketUrl(kaiaWebsocketUrl),
+		websocketchainreader.WithChains(chains))
 	if err != nil {
 		log.Error().Err(err).Msg("error in creating chain reader")
 		return err
 	}
 	a.chainReader = chainReader
 
-	for name, factory := range appConfig.DexFactories {
+	for name, factory := range a.appConfig.DexFactories {
+		feeds, err := db.QueryRows[common.Feed](ctx, common.GetDexFeedsQuery(name), nil)
+		if err != nil {
+			log.Error().Err(err).Msg("error in fetching feeds")
+			return err
+		}
+		a.dexFetchers[name] = a.newDexEntry(factory, feeds)
+	}
+
+	return nil
+}
+
+func (a *App) newDexEntry(factory func(...common.DexFetcherOption) common.FetcherInterface, feeds []common.Feed) *fetcherEntry {
+	fetcher := factory(
+		common.WithFeeds(feeds),
+		common.WithDexFeedDataBuffer(a.buffer),
+		common.WithWebsocketChainReader(a.chainReader),
+	)
+	return &fetcherEntry{fetcher: fetcher, feeds: feeds}
+}
+
+// Refresh reloads websocket feeds and applies the changes without dropping unaffected streams.
+// Providers are subscribed and unsubscribed incrementally where supported, otherwise only
+// the changed provider is restarted. Dex fetchers reuse the connected chain reader.
+func (a *App) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := a.loadFeedDecimals(ctx)
+	if err != nil {
+		return err
+	}
+
+	plans, err := a.loadCexPlans(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, entry := range a.cexFetchers {
+		if _, exists := plans[name]; exists {
+			continue
+		}
+		log.Info().Str("provider", name).Msg("no feeds left, stopping websocket fetcher")
+		entry.stop()
+		delete(a.cexFetchers, name)
+	}
+
+	for name, plan := range plans {
+		entry, exists := a.cexFetchers[name]
+		if exists && bytes.Equal(entry.config, plan.config) {
+			err = a.applyFeedMaps(ctx, entry, plan.feedMaps)
+			if err == nil {
+				entry.feedMaps = plan.feedMaps
+				continue
+			}
+			if !errors.Is(err, errorSentinel.ErrFetcherSubscriptionNotSupported) {
+				log.Warn().Err(err).Str("provider", name).Msg("failed to update subscriptions, restarting websocket fetcher")
+			}
+		}
+
+		next, err := a.newCexEntry(ctx, name, plan)
+		if err != nil {
+			return err
+		}
+		if exists {
+			entry.stop()
+		}
+		a.cexFetchers[name] = next
+		a.startEntry(next)
+	}
+
+	for name, factory := range a.appConfig.DexFactories {
 		feeds, err := db.QueryRows[common.Feed](ctx, common.GetDexFeedsQuery(name), nil)
 		if err != nil {
 			log.Error().Err(err).Msg("error in fetching feeds")
 			return err
 		}
-		fetcher := factory(
-			common.WithFeeds(feeds),
-			common.WithDexFeedDataBuffer(a.buffer),
-			common.WithWebsocketChainReader(a.chainReader),
-		)
 
-		a.fetchers = append(a.fetchers, fetcher)
+		entry, exists := a.dexFetchers[name]
+		if exists && sameFeeds(entry.feeds, feeds) {
+			continue
+		}
+		if exists {
+			entry.stop()
+		}
+		next := a.newDexEntry(factory, feeds)
+		a.dexFetchers[name] = next
+		a.startEntry(next)
 	}
 
+	a.health.Load().SetProviderFeeds(a.providerFeeds())
 	return nil
 }
 
+// loadFeedDecimals reads the decimals of every feed from its config, falling back to the configured map
+func (a *App) loadFeedDecimals(ctx context.Context) error {
+	feedDecimals := a.appConfig.FeedDecimals
+	if a.appConfig.SetFromDB {
+		rows, err := db.QueryRows[common.FeedDecimals](ctx, common.GetFeedDecimalsQuery, nil)
+		if err != nil {
+			log.Error().Err(err).Msg("error in fetching feed decimals")
+			return err
+		}
+
+		feedDecimals = make(map[int32]int32, len(rows))
+		for _, row := range rows {
+			feedDecimals[row.FeedID] = row.Decimals
+		}
+	}
+
+	a.decimalsMu.Lock()
+	defer a.decimalsMu.Unlock()
+	a.feedDecimals = feedDecimals
+	return nil
+}
+
+// rescale rounds a price emitted in DECIMALS precision into the decimals of the feed's config
+func (a *App) rescale(feedData *common.FeedData) *common.FeedData {
+	a.decimalsMu.RLock()
+	decimals := a.feedDecimals[feedData.FeedID]
+	a.decimalsMu.RUnlock()
+
+	feedData.Value = common.RescalePrice(feedData.Value, types.DecimalsOrDefault(decimals))
+	return feedData
+}
+
+func (a *App) applyFeedMaps(ctx context.Context, entry *fetcherEntry, next common.FeedMaps) error {
+	removed := common.DiffFeedMaps(entry.feedMaps, next)
+	if !removed.IsEmpty() {
+		if err := entry.fetcher.Unsubscribe(ctx, removed); err != nil {
+			return err
+		}
+	}
+
+	added := common.DiffFeedMaps(next, entry.feedMaps)
+	if !added.IsEmpty() {
+		return entry.fetcher.Subscribe(ctx, added)
+	}
+	return nil
+}
+
+// ShardStatus reports the connections of every sharded provider
+func (a *App) ShardStatus() map[string][]common.ShardStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := make(map[string][]common.ShardStatus)
+	for name, entry := range a.cexFetchers {
+		if sharded, ok := entry.fetcher.(*common.ShardedFetcher); ok {
+			result[name] = sharded.Status()
+		}
+	}
+	return result
+}
+
+// Health reports connection state and message flow of every provider, its shards and its feeds
+func (a *App) Health() []ProviderHealth {
+	health := a.health.Load()
+	if health == nil {
+		return []ProviderHealth{}
+	}
+
+	result := health.Snapshot()
+	shardStatus := a.ShardStatus()
+	for i := range result {
+		if status, exists := shardStatus[result[i].Name]; exists {
+			result[i].Shards = mergeShardStatus(result[i].Shards, status)
+		}
+	}
+	return result
+}
+
+// mergeShardStatus lists the current shards of a provider, connections of shards dropped by resharding are left out
+func mergeShardStatus(shards []ShardHealth, status []common.ShardStatus) []ShardHealth {
+	connections := make(map[int]ShardHealth, len(shards))
+	for _, shard := range shards {
+		connections[shard.Index] = shard
+	}
+
+	result := make([]ShardHealth, 0, len(status))
+	for _, shardStatus := range status {
+		shard, exists := connections[shardStatus.Index]
+		if !exists {
+			shard = ShardHealth{Index: shardStatus.Index, State: ConnectionStateUnknown}
+		}
+		shard.Running = shardStatus.Running
+		shard.Streams = shardStatus.Streams
+		shard.Symbols = shardStatus.Symbols
+		result = append(result, shard)
+	}
+	return result
+}
+
+// IsStale reports websocket feeds which stopped receiving data, other feeds are never stale
+func (a *App) IsStale(feedID int32) bool {
+	health := a.health.Load()
+	return health != nil && health.IsStale(feedID)
+}
+
+func (a *App) providerFeeds() map[string][]int32 {
+	result := make(map[string][]int32)
+	for name, entry := range a.cexFetchers {
+		result[name] = feedMapsIds(entry.feedMaps)
+	}
+	for name, entry := range a.dexFetchers {
+		for _, feed := range entry.feeds {
+			result[name] = append(result[name], feed.ID)
+		}
+	}
+	return result
+}
+
+func feedMapsIds(feedMaps common.FeedMaps) []int32 {
+	ids := make(map[int32]struct{})
+	for _, feedMap := range []map[string][]int32{feedMaps.Combined, feedMaps.Separated} {
+		for _, symbolIds := range feedMap {
+			for _, id := range symbolIds {
+				ids[id] = struct{}{}
+			}
+		}
+	}
+	for _, bookFeedMap := range []map[string][]common.BookFeed{feedMaps.CombinedBooks, feedMaps.SeparatedBooks} {
+		for _, bookFeeds := range bookFeedMap {
+			for _, bookFeed := range bookFeeds {
+				ids[bookFeed.ID] = struct{}{}
+			}
+		}
+	}
+	result := make([]int32, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// startEntry runs the fetcher under its own context, only once the app has been started
+func (a *App) startEntry(entry *fetcherEntry) {
+	if a.runCtx == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(a.runCtx)
+	entry.cancel = cancel
+	go entry.fetcher.Run(ctx)
+}
+
+func (e *fetcherEntry) stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+func sameFeeds(current []common.Feed, next []common.Feed) bool {
+	if len(current) != len(next) {
+		return false
+	}
+
+	currentDefinitions := make(map[int32]json.RawMessage, len(current))
+	for _, feed := range current {
+		currentDefinitions[feed.ID] = feed.Definition
+	}
+	for _, feed := range next {
+		definition, exists := currentDefinitions[feed.ID]
+		if !exists || !bytes.Equal(definition, feed.Definition) {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *App) Start(ctx context.Context) {
+	a.mu.Lock()
+	if a.runCtx != nil {
+		a.mu.Unlock()
+		log.Debug().Msg("websocket fetcher already running")
+		return
+	}
+
 	ctxWithCancel, cancel := context.WithCancel(ctx)
+	a.runCtx = ctxWithCancel
 	a.cancel = cancel
 
-	for _, fetcher := range a.fetchers {
-		go fetcher.Run(ctxWithCancel)
+	for _, entry := range a.cexFetchers {
+		a.startEntry(entry)
 	}
+	for _, entry := range a.dexFetchers {
+		a.startEntry(entry)
+	}
+	a.mu.Unlock()
 
 	ticker := time.NewTicker(a.storeInterval)
 	for {
@@ -276,9 +704,14 @@ func (a *App) Start(ctx context.Context) {
 }
 
 func (a *App) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	if a.cancel != nil {
 		a.cancel()
 	}
+	a.runCtx = nil
+	a.cancel = nil
 }
 
 func (a *App) storeFeedData(ctx context.Context) {
@@ -286,12 +719,13 @@ func (a *App) storeFeedData(ctx context.Context) {
 	case <-ctx.Done():
 		return
 	case feedData := <-a.buffer:
-		batch := []*common.FeedData{feedData}
+		batch := []*common.FeedData{a.rescale(feedData)}
 		// Continue to drain the buffer until it's empty
 	loop:
 		for {
 			select {
 			case feedData := <-a.buffer:
+				feedData = a.rescale(feedData)
 				batch = append(batch, feedData)
 				a.feedDataDumpChannel <- feedData
 			default:
@@ -299,6 +733,7 @@ func (a *App) storeFeedData(ctx context.Context) {
 			}
 		}
 
+		a.health.Load().Record(batch)
 		err := a.latestFeedDataMap.SetLatestFeedData(batch)
 		if err != nil {
 			log.Error().Err(err).Msg("error in setting latest feed data")
@@ -0,0 +1,325 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TickerPriceSource = "ticker"
+	MidPriceSource    = "mid"
+	DepthPriceSource  = "depth"
+
+	DefaultBookDepth = 20
+)
+
+// BookFeed is a feed priced from the order book instead of the ticker stream
+type BookFeed struct {
+	ID            int32
+	PriceSource   string
+	DepthNotional float64
+	MaxSpread     float64
+}
+
+type BookLevel struct {
+	Price float64
+	Size  float64
+}
+
+// OrderBook holds raw (unscaled) levels, bids sorted descending and asks ascending
+type OrderBook struct {
+	Bids      []BookLevel
+	Asks      []BookLevel
+	Timestamp time.Time
+}
+
+func IsBookPriceSource(priceSource string) bool {
+	return priceSource == MidPriceSource || priceSource == DepthPriceSource
+}
+
+// MidPrice averages the best bid and ask exactly, levels are taken as the decimals the exchange sent
+func MidPrice(book OrderBook) (*big.Rat, error) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, errorSentinel.ErrFetcherEmptyOrderBook
+	}
+
+	bid, err := decimal.FromFloat64(book.Bids[0].Price)
+	if err != nil {
+		return nil, err
+	}
+	ask, err := decimal.FromFloat64(book.Asks[0].Price)
+	if err != nil {
+		return nil, err
+	}
+	return average(bid, ask), nil
+}
+
+// Spread returns (bestAsk - bestBid) / mid
+func Spread(book OrderBook) (float64, error) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0, errorSentinel.ErrFetcherEmptyOrderBook
+	}
+
+	mid := (book.Bids[0].Price + book.Asks[0].Price) / 2
+	if mid == 0 {
+		return 0, errorSentinel.ErrFetcherDivisionByZero
+	}
+	return (book.Asks[0].Price - book.Bids[0].Price) / mid, nil
+}
+
+// DepthWeightedPrice averages the execution prices of selling and buying the given quote notional
+// against the book, falling back to the mid price when notional is not set
+func DepthWeightedPrice(book OrderBook, notional float64) (*big.Rat, error) {
+	if notional <= 0 {
+		return MidPrice(book)
+	}
+
+	target, err := decimal.FromFloat64(notional)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := sidePrice(book.Bids, target)
+	if err != nil {
+		return nil, err
+	}
+
+	ask, err := sidePrice(book.Asks, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return average(bid, ask), nil
+}
+
+func sidePrice(levels []BookLevel, notional *big.Rat) (*big.Rat, error) {
+	remaining := new(big.Rat).Set(notional)
+	filledSize := new(big.Rat)
+	for _, level := range levels {
+		price, err := decimal.FromFloat64(level.Price)
+		if err != nil {
+			return nil, err
+		}
+		size, err := decimal.FromFloat64(level.Size)
+		if err != nil {
+			return nil, err
+		}
+		if price.Sign() == 0 {
+			continue
+		}
+
+		levelNotional := new(big.Rat).Mul(price, size)
+		if levelNotional.Cmp(remaining) >= 0 {
+			filledSize.Add(filledSize, new(big.Rat).Quo(remaining, price))
+			remaining.SetInt64(0)
+			break
+		}
+		filledSize.Add(filledSize, size)
+		remaining.Sub(remaining, levelNotional)
+	}
+
+	if remaining.Sign() > 0 || filledSize.Sign() == 0 {
+		return nil, errorSentinel.ErrFetcherInsufficientBookDepth
+	}
+	return new(big.Rat).Quo(notional, filledSize), nil
+}
+
+func average(a, b *big.Rat) *big.Rat {
+	sum := new(big.Rat).Add(a, b)
+	return sum.Quo(sum, big.NewRat(2, 1))
+}
+
+func BookToFeedData(book OrderBook, feeds []BookFeed) []*FeedData {
+	spread, err := Spread(book)
+	if err != nil {
+		return nil
+	}
+
+	timestamp := book.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	result := []*FeedData{}
+	for _, feed := range feeds {
+		if feed.MaxSpread > 0 && spread > feed.MaxSpread {
+			log.Debug().Int32("feedId", feed.ID).Float64("spread", spread).Msg("spread exceeds max spread, skipping order book price")
+			continue
+		}
+
+		var price *big.Rat
+		switch feed.PriceSource {
+		case DepthPriceSource:
+			price, err = DepthWeightedPrice(book, feed.DepthNotional)
+		default:
+			price, err = MidPrice(book)
+		}
+		if err != nil {
+			log.Debug().Err(err).Int32("feedId", feed.ID).Msg("failed to price order book")
+			continue
+		}
+
+		feedSpread := spread
+		feedData := new(FeedData)
+		feedData.FeedID = feed.ID
+		feedData.Value = FormatDecimalPrice(price)
+		feedData.Timestamp = &timestamp
+		feedData.Spread = &feedSpread
+		result = append(result, feedData)
+	}
+	return result
+}
+
+// BookFeedIds flattens a book feed map into feed ids keyed by symbol
+func BookFeedIds(bookFeedMap map[string][]BookFeed) map[string][]int32 {
+	result := make(map[string][]int32, len(bookFeedMap))
+	for symbol, feeds := range bookFeedMap {
+		for _, feed := range feeds {
+			result[symbol] = append(result[symbol], feed.ID)
+		}
+	}
+	return result
+}
+
+// ParseBookLevels parses exchange levels shaped like [price, size, ...] strings
+func ParseBookLevels(raw [][]string) ([]BookLevel, error) {
+	levels := make([]BookLevel, 0, len(raw))
+	for _, entry := range raw {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid book level: %v", entry)
+		}
+
+		price, err := strconv.ParseFloat(entry[0], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.ParseFloat(entry[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, BookLevel{Price: price, Size: size})
+	}
+	return levels, nil
+}
+
+// LocalBook maintains a price level book for exchanges that publish a snapshot followed by deltas
+type LocalBook struct {
+	mu   sync.Mutex
+	bids map[float64]float64
+	asks map[float64]float64
+}
+
+func NewLocalBook() *LocalBook {
+	return &LocalBook{
+		bids: make(map[float64]float64),
+		asks: make(map[float64]float64),
+	}
+}
+
+func (b *LocalBook) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bids = make(map[float64]float64)
+	b.asks = make(map[float64]float64)
+}
+
+// Update applies levels to the book, a level with zero size removes the price
+func (b *LocalBook) Update(bids []BookLevel, asks []BookLevel) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	applyLevels(b.bids, bids)
+	applyLevels(b.asks, asks)
+}
+
+// Trim drops levels beyond depth, for exchanges that only send updates within the subscribed depth
+func (b *LocalBook) Trim(depth int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	trimLevels(b.bids, depth, true)
+	trimLevels(b.asks, depth, false)
+}
+
+func (b *LocalBook) Snapshot(depth int, timestamp time.Time) OrderBook {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return OrderBook{
+		Bids:      sortedLevels(b.bids, depth, true),
+		Asks:      sortedLevels(b.asks, depth, false),
+		Timestamp: timestamp,
+	}
+}
+
+func applyLevels(side map[float64]float64, levels []BookLevel) {
+	for _, level := range levels {
+		if level.Size == 0 || math.IsNaN(level.Size) {
+			delete(side, level.Price)
+			continue
+		}
+		side[level.Price] = level.Size
+	}
+}
+
+func trimLevels(side map[float64]float64, depth int, descending bool) {
+	if len(side) <= depth {
+		return
+	}
+	kept := sortedLevels(side, depth, descending)
+	for price := range side {
+		delete(side, price)
+	}
+	for _, level := range kept {
+		side[level.Price] = level.Size
+	}
+}
+
+func sortedLevels(side map[float64]float64, depth int, descending bool) []BookLevel {
+	levels := make([]BookLevel, 0, len(side))
+	for price, size := range side {
+		levels = append(levels, BookLevel{Price: price, Size: size})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return levels
+}
+
+// LocalBookMap keeps one LocalBook per exchange symbol
+type LocalBookMap struct {
+	mu    sync.Mutex
+	books map[string]*LocalBook
+}
+
+func NewLocalBookMap() *LocalBookMap {
+	return &LocalBookMap{books: make(map[string]*LocalBook)}
+}
+
+func (m *LocalBookMap) Get(symbol string) *LocalBook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	book, exists := m.books[symbol]
+	if !exists {
+		book = NewLocalBook()
+		m.books[symbol] = book
+	}
+	return book
+}
@@ -0,0 +1,333 @@
+package common
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/wss"
+	"github.com/rs/zerolog/log"
+)
+
+type Factory func(context.Context, ...FetcherOption) (FetcherInterface, error)
+
+// ShardStatus describes a single connection of a sharded provider
+type ShardStatus struct {
+	Index   int
+	Streams int
+	Symbols []string
+	Running bool
+}
+
+type fetcherShard struct {
+	fetcher  FetcherInterface
+	feedMaps FeedMaps
+	running  bool
+}
+
+// ShardedFetcher splits the feeds of a provider across several fetchers, one connection each,
+// so exchanges capping streams per connection can serve any number of feeds.
+// Every shard owns its websocket, which is monitored and reconnected on its own.
+type ShardedFetcher struct {
+	mu         sync.Mutex
+	name       string
+	maxStreams int
+	shards     []*fetcherShard
+}
+
+// NewShardedFactory wraps a provider constructor, creating one provider instance per shard
+// of at most maxStreams streams
+func NewShardedFactory(name string, factory Factory, maxStreams int) Factory {
+	return func(ctx context.Context, opts ...FetcherOption) (FetcherInterface, error) {
+		config := &FetcherConfig{}
+		for _, opt := range opts {
+			opt(config)
+		}
+
+		result := &ShardedFetcher{name: name, maxStreams: maxStreams}
+		for i, feedMaps := range ShardFeedMaps(config.FeedMaps, maxStreams) {
+			shardOpts := append(slices.Clone(opts), WithFeedMaps(feedMaps))
+			if config.ShardObserver != nil {
+				connectionOpts := append(slices.Clone(config.ConnectionOptions), wss.WithObserver(config.ShardObserver(i)))
+				shardOpts = append(shardOpts, WithConnectionOptions(connectionOpts...))
+			}
+			fetcher, err := factory(ctx, shardOpts...)
+			if err != nil {
+				log.Error().Str("Player", "Shard").Str("provider", name).Int("shard", i).Err(err).Msg("error in creating shard")
+				return nil, err
+			}
+			result.shards = append(result.shards, &fetcherShard{fetcher: fetcher, feedMaps: feedMaps})
+		}
+
+		log.Info().Str("Player", "Shard").Str("provider", name).Int("shards", len(result.shards)).Int("maxStreams", maxStreams).Msg("sharded provider feeds")
+		return result, nil
+	}
+}
+
+func (s *ShardedFetcher) Run(ctx context.Context) {
+	s.mu.Lock()
+	shards := slices.Clone(s.shards)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(index int, shard *fetcherShard) {
+			defer wg.Done()
+			s.setRunning(shard, true)
+			log.Info().Str("Player", "Shard").Str("provider", s.name).Int("shard", index).Int("streams", shardStreams(shard.feedMaps)).Msg("shard started")
+			shard.fetcher.Run(ctx)
+			s.setRunning(shard, false)
+			log.Info().Str("Player", "Shard").Str("provider", s.name).Int("shard", index).Msg("shard stopped")
+		}(i, shard)
+	}
+	wg.Wait()
+}
+
+func (s *ShardedFetcher) setRunning(shard *fetcherShard, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shard.running = running
+}
+
+// Status reports every shard separately
+func (s *ShardedFetcher) Status() []ShardStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]ShardStatus, 0, len(s.shards))
+	for i, shard := range s.shards {
+		result = append(result, ShardStatus{
+			Index:   i,
+			Streams: shardStreams(shard.feedMaps),
+			Symbols: shardSymbols(shard.feedMaps),
+			Running: shard.running,
+		})
+	}
+	return result
+}
+
+// Subscribe routes new feeds of known symbols to their shard and new symbols to shards with spare capacity,
+// a provider without capacity left is restarted and resharded by the app
+func (s *ShardedFetcher) Subscribe(ctx context.Context, feedMaps FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assigned := make([]map[int32]struct{}, len(s.shards))
+	streams := make([]int, len(s.shards))
+	for i, shard := range s.shards {
+		assigned[i] = make(map[int32]struct{})
+		streams[i] = shardStreams(shard.feedMaps)
+	}
+
+	for _, symbol := range sortedKeys(feedMaps.Separated) {
+		index := s.shardOf(symbol)
+		if index < 0 {
+			for i := range s.shards {
+				if streams[i] < s.maxStreams {
+					index = i
+					streams[i]++
+					break
+				}
+			}
+		}
+		if index < 0 {
+			return errorSentinel.ErrFetcherSubscriptionNotSupported
+		}
+		for _, id := range feedMaps.Separated[symbol] {
+			assigned[index][id] = struct{}{}
+		}
+	}
+
+	for i, ids := range assigned {
+		if len(ids) == 0 {
+			continue
+		}
+		added := filterFeedMaps(feedMaps, ids)
+		if err := s.shards[i].fetcher.Subscribe(ctx, added); err != nil {
+			return err
+		}
+		s.shards[i].feedMaps = mergeFeedMaps(s.shards[i].feedMaps, added)
+	}
+	return nil
+}
+
+func (s *ShardedFetcher) Unsubscribe(ctx context.Context, feedMaps FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shard := range s.shards {
+		ids := make(map[int32]struct{})
+		for symbol, symbolIds := range feedMaps.Separated {
+			if _, exists := shard.feedMaps.Separated[symbol]; !exists {
+				continue
+			}
+			for _, id := range symbolIds {
+				ids[id] = struct{}{}
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		removed := filterFeedMaps(feedMaps, ids)
+		if err := shard.fetcher.Unsubscribe(ctx, removed); err != nil {
+			return err
+		}
+		shard.feedMaps.Combined, _ = RemoveFeedIds(shard.feedMaps.Combined, removed.Combined)
+		shard.feedMaps.Separated, _ = RemoveFeedIds(shard.feedMaps.Separated, removed.Separated)
+	}
+	return nil
+}
+
+func (s *ShardedFetcher) shardOf(symbol string) int {
+	for i, shard := range s.shards {
+		if _, exists := shard.feedMaps.Separated[symbol]; exists {
+			return i
+		}
+	}
+	return -1
+}
+
+// ShardFeedMaps splits feed maps into groups of at most maxStreams streams, keeping every feed of a symbol together.
+// A symbol takes one stream for its ticker, one for its order book and one for trades when it has a volume window.
+func ShardFeedMaps(feedMaps FeedMaps, maxStreams int) []FeedMaps {
+	if maxStreams <= 0 {
+		return []FeedMaps{feedMaps}
+	}
+
+	shards := []map[int32]struct{}{}
+	current := make(map[int32]struct{})
+	currentStreams := 0
+	for _, symbol := range shardSymbols(feedMaps) {
+		ids, streams := symbolStreams(feedMaps, symbol)
+		if currentStreams > 0 && currentStreams+streams > maxStreams {
+			shards = append(shards, current)
+			current = make(map[int32]struct{})
+			currentStreams = 0
+		}
+		for _, id := range ids {
+			current[id] = struct{}{}
+		}
+		currentStreams += streams
+	}
+	if currentStreams > 0 || len(shards) == 0 {
+		shards = append(shards, current)
+	}
+
+	result := make([]FeedMaps, 0, len(shards))
+	for _, ids := range shards {
+		result = append(result, filterFeedMaps(feedMaps, ids))
+	}
+	return result
+}
+
+func shardSymbols(feedMaps FeedMaps) []string {
+	symbols := sortedKeys(feedMaps.Separated)
+	for symbol := range feedMaps.SeparatedBooks {
+		if _, exists := feedMaps.Separated[symbol]; !exists {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func shardStreams(feedMaps FeedMaps) int {
+	result := 0
+	for _, symbol := range shardSymbols(feedMaps) {
+		_, streams := symbolStreams(feedMaps, symbol)
+		result += streams
+	}
+	return result
+}
+
+func symbolStreams(feedMaps FeedMaps, symbol string) ([]int32, int) {
+	ids := slices.Clone(feedMaps.Separated[symbol])
+	streams := 0
+	if len(ids) > 0 {
+		streams++
+	}
+
+	if bookFeeds := feedMaps.SeparatedBooks[symbol]; len(bookFeeds) > 0 {
+		streams++
+		for _, bookFeed := range bookFeeds {
+			ids = append(ids, bookFeed.ID)
+		}
+	}
+
+	for _, id := range ids {
+		if _, exists := feedMaps.VolumeWindows[id]; exists {
+			streams++
+			break
+		}
+	}
+	return ids, streams
+}
+
+// filterFeedMaps keeps entries holding any of ids, Combined and Separated keys differ so entries are matched by id
+func filterFeedMaps(feedMaps FeedMaps, ids map[int32]struct{}) FeedMaps {
+	result := FeedMaps{
+		Combined:       filterIds(feedMaps.Combined, ids),
+		Separated:      filterIds(feedMaps.Separated, ids),
+		CombinedBooks:  filterBookFeeds(feedMaps.CombinedBooks, ids),
+		SeparatedBooks: filterBookFeeds(feedMaps.SeparatedBooks, ids),
+		VolumeWindows:  make(map[int32]time.Duration),
+	}
+	for id, window := range feedMaps.VolumeWindows {
+		if _, exists := ids[id]; exists {
+			result.VolumeWindows[id] = window
+		}
+	}
+	return result
+}
+
+func filterIds(feedMap map[string][]int32, ids map[int32]struct{}) map[string][]int32 {
+	result := make(map[string][]int32)
+	for symbol, symbolIds := range feedMap {
+		for _, id := range symbolIds {
+			if _, exists := ids[id]; exists {
+				result[symbol] = append(result[symbol], id)
+			}
+		}
+	}
+	return result
+}
+
+func filterBookFeeds(bookFeedMap map[string][]BookFeed, ids map[int32]struct{}) map[string][]BookFeed {
+	result := make(map[string][]BookFeed)
+	for symbol, bookFeeds := range bookFeedMap {
+		for _, bookFeed := range bookFeeds {
+			if _, exists := ids[bookFeed.ID]; exists {
+				result[symbol] = append(result[symbol], bookFeed)
+			}
+		}
+	}
+	return result
+}
+
+func mergeFeedMaps(feedMaps FeedMaps, added FeedMaps) FeedMaps {
+	feedMaps.Combined, _ = AddFeedIds(feedMaps.Combined, added.Combined)
+	feedMaps.Separated, _ = AddFeedIds(feedMaps.Separated, added.Separated)
+	return feedMaps
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
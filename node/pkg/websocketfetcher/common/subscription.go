@@ -0,0 +1,124 @@
+package common
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+)
+
+// NoHotSubscription is embedded by fetchers without incremental subscription support,
+// the app restarts only that fetcher when its feeds change
+type NoHotSubscription struct{}
+
+func (NoHotSubscription) Subscribe(ctx context.Context, feedMaps FeedMaps) error {
+	return errorSentinel.ErrFetcherSubscriptionNotSupported
+}
+
+func (NoHotSubscription) Unsubscribe(ctx context.Context, feedMaps FeedMaps) error {
+	return errorSentinel.ErrFetcherSubscriptionNotSupported
+}
+
+func (m FeedMaps) IsEmpty() bool {
+	return len(m.Combined) == 0 && len(m.Separated) == 0 && len(m.CombinedBooks) == 0 && len(m.SeparatedBooks) == 0 && len(m.VolumeWindows) == 0
+}
+
+// HasBookOrVolumeFeeds reports order book or rolling volume changes, which incremental subscriptions don't cover
+func (m FeedMaps) HasBookOrVolumeFeeds() bool {
+	return len(m.CombinedBooks) > 0 || len(m.SeparatedBooks) > 0 || len(m.VolumeWindows) > 0
+}
+
+// DiffFeedMaps returns the feeds of next which are missing from current
+func DiffFeedMaps(next FeedMaps, current FeedMaps) FeedMaps {
+	result := FeedMaps{
+		Combined:       diffIds(next.Combined, current.Combined),
+		Separated:      diffIds(next.Separated, current.Separated),
+		CombinedBooks:  diffBookFeeds(next.CombinedBooks, current.CombinedBooks),
+		SeparatedBooks: diffBookFeeds(next.SeparatedBooks, current.SeparatedBooks),
+		VolumeWindows:  make(map[int32]time.Duration),
+	}
+
+	for id, window := range next.VolumeWindows {
+		if currentWindow, exists := current.VolumeWindows[id]; !exists || currentWindow != window {
+			result.VolumeWindows[id] = window
+		}
+	}
+	return result
+}
+
+func diffIds(next map[string][]int32, current map[string][]int32) map[string][]int32 {
+	result := make(map[string][]int32)
+	for symbol, ids := range next {
+		for _, id := range ids {
+			if !slices.Contains(current[symbol], id) {
+				result[symbol] = append(result[symbol], id)
+			}
+		}
+	}
+	return result
+}
+
+func diffBookFeeds(next map[string][]BookFeed, current map[string][]BookFeed) map[string][]BookFeed {
+	result := make(map[string][]BookFeed)
+	for symbol, feeds := range next {
+		for _, feed := range feeds {
+			if !slices.Contains(current[symbol], feed) {
+				result[symbol] = append(result[symbol], feed)
+			}
+		}
+	}
+	return result
+}
+
+// AddFeedIds returns a copy of feedMap including added, along with the symbols that were not present before
+func AddFeedIds(feedMap map[string][]int32, added map[string][]int32) (map[string][]int32, []string) {
+	result := copyFeedMap(feedMap)
+	newSymbols := []string{}
+	for symbol, ids := range added {
+		if _, exists := result[symbol]; !exists {
+			newSymbols = append(newSymbols, symbol)
+		}
+		for _, id := range ids {
+			if !slices.Contains(result[symbol], id) {
+				result[symbol] = append(result[symbol], id)
+			}
+		}
+	}
+	return result, newSymbols
+}
+
+// RemoveFeedIds returns a copy of feedMap without removed, along with the symbols left without feeds
+func RemoveFeedIds(feedMap map[string][]int32, removed map[string][]int32) (map[string][]int32, []string) {
+	result := copyFeedMap(feedMap)
+	emptySymbols := []string{}
+	for symbol, ids := range removed {
+		current, exists := result[symbol]
+		if !exists {
+			continue
+		}
+
+		remaining := []int32{}
+		for _, id := range current {
+			if !slices.Contains(ids, id) {
+				remaining = append(remaining, id)
+			}
+		}
+
+		if len(remaining) == 0 {
+			delete(result, symbol)
+			emptySymbols = append(emptySymbols, symbol)
+			continue
+		}
+		result[symbol] = remaining
+	}
+	return result, emptySymbols
+}
+
+func copyFeedMap(feedMap map[string][]int32) map[string][]int32 {
+	result := make(map[string][]int32, len(feedMap))
+	for symbol, ids := range feedMap {
+		result[symbol] = slices.Clone(ids)
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -13,14 +14,17 @@ import (
 )
 
 const (
-	DECIMALS                  = 8
+	// precision prices are parsed into before the app rescales them into the decimals of their config
+	DECIMALS                  = types.MaxDecimals
 	GetAllWebsocketFeedsQuery = `SELECT *
 	FROM feeds
 	WHERE definition @> '{"type": "wss"}';`
-	GetAllProxiesQuery  = `SELECT * FROM proxies`
-	VolumeCacheLifespan = 10 * time.Minute
-	VolumeFetchInterval = 10000
-	VolumeFetchTimeout  = 6 * time.Second
+	GetAllProxiesQuery            = `SELECT * FROM proxies`
+	GetAllWebsocketProvidersQuery = `SELECT * FROM websocket_providers`
+	GetFeedDecimalsQuery          = `SELECT feeds.id AS feed_id, configs.decimals FROM feeds JOIN configs ON feeds.config_id = configs.id`
+	VolumeCacheLifespan           = 10 * time.Minute
+	VolumeFetchInterval           = 10000
+	VolumeFetchTimeout            = 6 * time.Second
 )
 
 type Feed = types.Feed
@@ -31,11 +35,35 @@ func GetDexFeedsQuery(name string) string {
 	return fmt.Sprintf(`SELECT * FROM feeds WHERE definition::jsonb @> '{"type": "%sPool"}'::jsonb;`, name)
 }
 
+// decimals of the config a feed belongs to, prices of the feed are stored in this precision
+type FeedDecimals struct {
+	FeedID   int32 `db:"feed_id"`
+	Decimals int32 `db:"decimals"`
+}
+
 type FeedDefinition struct {
 	Type     string `json:"type"`
 	Provider string `json:"provider"`
 	Base     string `json:"base"`
 	Quote    string `json:"quote"`
+
+	// optional generic provider config, takes precedence over the websocket_providers table
+	ProviderConfig json.RawMessage `json:"providerConfig,omitempty"`
+
+	// optional order book pricing, supported by binance, okx, bybit, coinbase, upbit and kraken
+	PriceSource   string  `json:"priceSource,omitempty"`   // ticker (default), mid or depth
+	DepthNotional float64 `json:"depthNotional,omitempty"` // quote notional walked on each side for depth pricing
+	MaxSpread     float64 `json:"maxSpread,omitempty"`     // relative spread above which the book price is dropped
+
+	// optional rolling window (e.g. "1m", "5m") of locally aggregated trades replacing the 24h ticker volume
+	VolumeWindow string `json:"volumeWindow,omitempty"`
+}
+
+// registry entry for config driven exchanges, see providers/generic
+type WebsocketProvider struct {
+	ID     int32           `db:"id"`
+	Name   string          `db:"name"`
+	Config json.RawMessage `db:"config"`
 }
 
 type DexFeedDefinition struct {
@@ -45,12 +73,19 @@ type DexFeedDefinition struct {
 	Token0Decimals int    `json:"token0Decimals"`
 	Token1Decimals int    `json:"token1Decimals"`
 	Reciprocal     *bool  `json:"reciprocal"`
+
+	// optional manipulation resistance settings, only used by v3 style pools
+	TwapWindow      *uint32 `json:"twapWindow"`      // seconds, uses observe() instead of spot price when set
+	MinLiquidity    *string `json:"minLiquidity"`    // minimum in-range liquidity, skips the update when below
+	LiquidityWeight *bool   `json:"liquidityWeight"` // sets FeedData.Volume from in-range liquidity for vwap
 }
 
 type FetcherConfig struct {
-	FeedMaps       FeedMaps
-	Proxy          string
-	FeedDataBuffer chan *FeedData
+	FeedMaps          FeedMaps
+	Proxy             string
+	FeedDataBuffer    chan *FeedData
+	ConnectionOptions []wss.ConnectionOption
+	ShardObserver     func(shard int) wss.Observer
 }
 
 type DexFetcherConfig struct {
@@ -62,6 +97,13 @@ type DexFetcherConfig struct {
 type FeedMaps struct {
 	Combined  map[string][]int32
 	Separated map[string][]int32
+
+	// order book priced feeds, keyed the same way as Combined and Separated
+	CombinedBooks  map[string][]BookFeed
+	SeparatedBooks map[string][]BookFeed
+
+	// rolling trade volume windows keyed by feed id
+	VolumeWindows map[int32]time.Duration
 }
 
 type FetcherOption func(*FetcherConfig)
@@ -78,6 +120,20 @@ func WithProxy(proxy string) FetcherOption {
 	}
 }
 
+// WithConnectionOptions passes extra websocket options to the provider, e.g. recording or a replay dialer
+func WithConnectionOptions(opts ...wss.ConnectionOption) FetcherOption {
+	return func(c *FetcherConfig) {
+		c.ConnectionOptions = opts
+	}
+}
+
+// WithShardObserver observes every shard of a sharded provider on its own, overriding the observer of the connection options
+func WithShardObserver(observer func(shard int) wss.Observer) FetcherOption {
+	return func(c *FetcherConfig) {
+		c.ShardObserver = observer
+	}
+}
+
 func WithFeedDataBuffer(feedDataBuffer chan *FeedData) FetcherOption {
 	return func(c *FetcherConfig) {
 		c.FeedDataBuffer = feedDataBuffer
@@ -105,13 +161,26 @@ func WithDexFeedDataBuffer(feedDataBuffer chan *FeedData) DexFetcherOption {
 }
 
 type Fetcher struct {
+	NoHotSubscription
+
 	FeedMap        map[string][]int32
 	Ws             *wss.WebsocketHelper
 	FeedDataBuffer chan *FeedData
 	VolumeCacheMap VolumeCacheMap
+
+	// order book sources, only used by providers supporting mid and depth prices
+	BookFeedMap map[string][]BookFeed
+	LocalBooks  *LocalBookMap
+	BookWs      *wss.WebsocketHelper
+	// rolling trade volumes, only used by providers supporting trade streams
+	TradeVolumes *TradeVolumeMap
+	// guards FeedMap swaps by providers supporting incremental subscriptions
+	FeedMapMu sync.RWMutex
 }
 
 type DexFetcher struct {
+	NoHotSubscription
+
 	Feeds                []Feed
 	WebsocketChainReader *websocketchainreader.ChainReader
 	FeedDataBuffer       chan *FeedData
@@ -119,6 +188,10 @@ type DexFetcher struct {
 
 type FetcherInterface interface {
 	Run(context.Context)
+	// Subscribe and Unsubscribe apply feed changes over the live connection,
+	// returning ErrFetcherSubscriptionNotSupported when the fetcher has to be restarted instead
+	Subscribe(ctx context.Context, feedMaps FeedMaps) error
+	Unsubscribe(ctx context.Context, feedMaps FeedMaps) error
 }
 
 type VolumeCache struct {
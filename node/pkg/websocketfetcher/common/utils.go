@@ -5,10 +5,12 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"io"
-	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
+	"bisonai.com/miko/node/pkg/utils/decimal"
 	"github.com/rs/zerolog/log"
 )
 
@@ -36,34 +38,87 @@ func GetWssFeedMap(feeds []Feed) map[string]FeedMaps {
 
 		if _, exists := feedMaps[provider]; !exists {
 			feedMaps[provider] = FeedMaps{
-				Combined:  make(map[string][]int32),
-				Separated: make(map[string][]int32),
+				Combined:       make(map[string][]int32),
+				Separated:      make(map[string][]int32),
+				CombinedBooks:  make(map[string][]BookFeed),
+				SeparatedBooks: make(map[string][]BookFeed),
+				VolumeWindows:  make(map[int32]time.Duration),
 			}
-			feedMaps[provider].Combined[combinedName] = []int32{}
-			feedMaps[provider].Separated[separatedName] = []int32{}
+		}
+
+		if def.VolumeWindow != "" {
+			window, parseErr := time.ParseDuration(def.VolumeWindow)
+			if parseErr != nil || window <= 0 {
+				log.Warn().Int32("feedId", feed.ID).Str("volumeWindow", def.VolumeWindow).Msg("invalid volume window, using ticker volume")
+			} else {
+				feedMaps[provider].VolumeWindows[feed.ID] = window
+			}
+		}
 
+		if IsBookPriceSource(def.PriceSource) {
+			bookFeed := BookFeed{
+				ID:            feed.ID,
+				PriceSource:   def.PriceSource,
+				DepthNotional: def.DepthNotional,
+				MaxSpread:     def.MaxSpread,
+			}
+			feedMaps[provider].CombinedBooks[combinedName] = append(feedMaps[provider].CombinedBooks[combinedName], bookFeed)
+			feedMaps[provider].SeparatedBooks[separatedName] = append(feedMaps[provider].SeparatedBooks[separatedName], bookFeed)
+			continue
 		}
+
 		feedMaps[provider].Combined[combinedName] = append(feedMaps[provider].Combined[combinedName], feed.ID)
 		feedMaps[provider].Separated[separatedName] = append(feedMaps[provider].Separated[separatedName], feed.ID)
 	}
 	return feedMaps
 }
 
-func PriceStringToFloat64(price string) (float64, error) {
-	f, err := strconv.ParseFloat(price, 64)
+// GetWssProviderConfigs collects generic provider configs embedded in feed definitions, keyed by provider
+func GetWssProviderConfigs(feeds []Feed) map[string]json.RawMessage {
+	configs := make(map[string]json.RawMessage)
+	for _, feed := range feeds {
+		var def FeedDefinition
+		err := json.Unmarshal(feed.Definition, &def)
+		if err != nil || len(def.ProviderConfig) == 0 {
+			continue
+		}
+		configs[strings.ToLower(def.Provider)] = def.ProviderConfig
+	}
+	return configs
+}
+
+// PriceStringToValue scales the price without going through float64, keeping every digit the exchange sent
+func PriceStringToValue(price string) (*big.Int, error) {
+	value, err := decimal.Parse(price)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return FormatFloat64Price(f), nil
+	return FormatDecimalPrice(value), nil
 }
 
 func VolumeStringToFloat64(volume string) (float64, error) {
 	return strconv.ParseFloat(volume, 64)
 }
 
-func FormatFloat64Price(price float64) float64 {
-	return price * float64(math.Pow10(DECIMALS))
+// FormatFloat64Price scales a price decoded as float64 from the shortest decimal which parses back into it
+func FormatFloat64Price(price float64) (*big.Int, error) {
+	value, err := decimal.FromFloat64(price)
+	if err != nil {
+		return nil, err
+	}
+	return FormatDecimalPrice(value), nil
+}
+
+// FormatDecimalPrice scales an exact price into DECIMALS precision, above the decimals of any config so
+// the only rounding which matters is the app's, see RescalePrice.
+func FormatDecimalPrice(price *big.Rat) *big.Int {
+	return decimal.Round(decimal.Scale(price, DECIMALS))
+}
+
+// RescalePrice converts a price of DECIMALS precision into the given decimals, rounded half away from zero
+func RescalePrice(price *big.Int, decimals int32) *big.Int {
+	return decimal.ScaleInt(price, int(decimals)-DECIMALS)
 }
 
 func MessageToStruct[T any](message map[string]any) (T, error) {
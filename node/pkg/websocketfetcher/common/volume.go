@@ -0,0 +1,139 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+type volumeBucket struct {
+	second int64
+	base   float64
+}
+
+// RollingVolume accumulates traded base volume into one second buckets,
+// keeping only what falls inside the window
+type RollingVolume struct {
+	mu      sync.Mutex
+	window  time.Duration
+	buckets []volumeBucket
+}
+
+func NewRollingVolume(window time.Duration) *RollingVolume {
+	return &RollingVolume{window: window}
+}
+
+func (r *RollingVolume) Add(timestamp time.Time, size float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	second := timestamp.Unix()
+	last := len(r.buckets) - 1
+	if last >= 0 && r.buckets[last].second == second {
+		r.buckets[last].base += size
+	} else {
+		r.buckets = append(r.buckets, volumeBucket{second: second, base: size})
+	}
+	r.prune(timestamp)
+}
+
+// Volume returns base volume traded within the window ending at now
+func (r *RollingVolume) Volume(now time.Time) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(now)
+	base := float64(0)
+	for _, bucket := range r.buckets {
+		base += bucket.base
+	}
+	return base
+}
+
+func (r *RollingVolume) prune(now time.Time) {
+	oldest := now.Add(-r.window).Unix()
+	i := 0
+	for i < len(r.buckets) && r.buckets[i].second <= oldest {
+		i++
+	}
+	if i > 0 {
+		r.buckets = append(r.buckets[:0], r.buckets[i:]...)
+	}
+}
+
+// TradeVolumeMap keeps a rolling window per feed for feeds configured with volumeWindow,
+// trades are matched to feeds through the same symbol keys the provider uses for its feed map
+type TradeVolumeMap struct {
+	symbols map[string][]int32
+	volumes map[int32]*RollingVolume
+}
+
+func NewTradeVolumeMap(windows map[int32]time.Duration, feedMaps ...map[string][]int32) *TradeVolumeMap {
+	result := &TradeVolumeMap{
+		symbols: make(map[string][]int32),
+		volumes: make(map[int32]*RollingVolume),
+	}
+
+	for _, feedMap := range feedMaps {
+		for symbol, ids := range feedMap {
+			for _, id := range ids {
+				window, exists := windows[id]
+				if !exists {
+					continue
+				}
+				result.symbols[symbol] = append(result.symbols[symbol], id)
+				result.volumes[id] = NewRollingVolume(window)
+			}
+		}
+	}
+	return result
+}
+
+// Symbols returns the feed map keys that need a trade subscription
+func (m *TradeVolumeMap) Symbols() []string {
+	if m == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(m.symbols))
+	for symbol := range m.symbols {
+		result = append(result, symbol)
+	}
+	return result
+}
+
+func (m *TradeVolumeMap) Tracks(symbol string) bool {
+	if m == nil {
+		return false
+	}
+	_, exists := m.symbols[symbol]
+	return exists
+}
+
+// AddTrade records a trade at receipt time, so windows don't depend on exchange clock skew
+func (m *TradeVolumeMap) AddTrade(symbol string, size float64) {
+	if m == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, id := range m.symbols[symbol] {
+		m.volumes[id].Add(now, size)
+	}
+}
+
+// Apply replaces ticker volumes with the rolling base volume for feeds that have a window
+func (m *TradeVolumeMap) Apply(feedDataList []*FeedData) []*FeedData {
+	if m == nil {
+		return feedDataList
+	}
+
+	now := time.Now()
+	for _, feedData := range feedDataList {
+		volume, exists := m.volumes[feedData.FeedID]
+		if !exists {
+			continue
+		}
+		feedData.Volume = volume.Volume(now)
+	}
+	return feedDataList
+}
@@ -0,0 +1,348 @@
+package websocketfetcher
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/wss"
+)
+
+const (
+	DefaultStaleThreshold = 2 * time.Minute
+	healthRateWindow      = 10 * time.Second
+
+	ConnectionStateConnected    = "connected"
+	ConnectionStateDisconnected = "disconnected"
+	// providers without connection events yet, or reading through the chain reader
+	ConnectionStateUnknown = "unknown"
+)
+
+type FeedHealth struct {
+	ID          int32      `json:"id"`
+	LastUpdate  *time.Time `json:"lastUpdate"`
+	LastPrice   *big.Int   `json:"lastPrice"`
+	MessageRate float64    `json:"messageRate"`
+	Stale       bool       `json:"stale"`
+}
+
+// ShardHealth is the connection of a single shard, stream and symbol counts are filled in by the app
+type ShardHealth struct {
+	Index       int        `json:"index"`
+	State       string     `json:"state"`
+	Running     bool       `json:"running"`
+	Streams     int        `json:"streams"`
+	Symbols     []string   `json:"symbols"`
+	Connections int        `json:"connections"`
+	Reconnects  int        `json:"reconnects"`
+	LastMessage *time.Time `json:"lastMessage"`
+	MessageRate float64    `json:"messageRate"`
+}
+
+type ProviderHealth struct {
+	Name        string        `json:"name"`
+	State       string        `json:"state"`
+	Connections int           `json:"connections"`
+	Reconnects  int           `json:"reconnects"`
+	LastMessage *time.Time    `json:"lastMessage"`
+	MessageRate float64       `json:"messageRate"`
+	ParseErrors int           `json:"parseErrors"`
+	Feeds       []FeedHealth  `json:"feeds"`
+	Shards      []ShardHealth `json:"shards,omitempty"`
+}
+
+// rateCounter estimates events per second over fixed windows
+type rateCounter struct {
+	windowStart time.Time
+	count       int
+	rate        float64
+}
+
+func (r *rateCounter) add(now time.Time) {
+	r.roll(now)
+	r.count++
+}
+
+func (r *rateCounter) roll(now time.Time) {
+	elapsed := now.Sub(r.windowStart)
+	if elapsed < healthRateWindow {
+		return
+	}
+	if !r.windowStart.IsZero() {
+		r.rate = float64(r.count) / elapsed.Seconds()
+	}
+	r.windowStart = now
+	r.count = 0
+}
+
+// connectionHealth aggregates the connection events of a provider or of one of its shards
+type connectionHealth struct {
+	connections        int
+	connects           int
+	pendingDisconnects int
+	reconnects         int
+	lastMessage        time.Time
+	messages           rateCounter
+}
+
+func (c *connectionHealth) connect() {
+	c.connections++
+	c.connects++
+	if c.pendingDisconnects > 0 {
+		c.pendingDisconnects--
+		c.reconnects++
+	}
+}
+
+func (c *connectionHealth) disconnect() {
+	c.connections = max(c.connections-1, 0)
+	c.pendingDisconnects++
+}
+
+func (c *connectionHealth) message(now time.Time) {
+	c.lastMessage = now
+	c.messages.add(now)
+}
+
+func (c *connectionHealth) state() string {
+	if c.connections > 0 {
+		return ConnectionStateConnected
+	}
+	if c.connects > 0 {
+		return ConnectionStateDisconnected
+	}
+	return ConnectionStateUnknown
+}
+
+type providerHealth struct {
+	connectionHealth
+	feedIds     []int32
+	parseErrors int
+	shards      map[int]*connectionHealth
+}
+
+type feedHealth struct {
+	lastUpdate time.Time
+	lastPrice  *big.Int
+	updates    rateCounter
+}
+
+// HealthRegistry tracks connection state and message flow per provider and feed,
+// connections report through wss observers and feeds through the data stored by the app
+type HealthRegistry struct {
+	mu             sync.Mutex
+	staleThreshold time.Duration
+	providers      map[string]*providerHealth
+	feeds          map[int32]*feedHealth
+}
+
+func NewHealthRegistry(staleThreshold time.Duration) *HealthRegistry {
+	if staleThreshold <= 0 {
+		staleThreshold = DefaultStaleThreshold
+	}
+	return &HealthRegistry{
+		staleThreshold: staleThreshold,
+		providers:      make(map[string]*providerHealth),
+		feeds:          make(map[int32]*feedHealth),
+	}
+}
+
+// SetProviderFeeds replaces the tracked feeds, keeping the history of providers and feeds still present
+func (r *HealthRegistry) SetProviderFeeds(providerFeeds map[string][]int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feeds := make(map[int32]*feedHealth)
+	for name, feedIds := range providerFeeds {
+		provider := r.provider(name)
+		provider.feedIds = feedIds
+		for _, id := range feedIds {
+			feed, exists := r.feeds[id]
+			if !exists {
+				feed = &feedHealth{}
+			}
+			feeds[id] = feed
+		}
+	}
+	r.feeds = feeds
+
+	for name := range r.providers {
+		if _, exists := providerFeeds[name]; !exists {
+			delete(r.providers, name)
+		}
+	}
+}
+
+func (r *HealthRegistry) provider(name string) *providerHealth {
+	provider, exists := r.providers[name]
+	if !exists {
+		provider = &providerHealth{}
+		r.providers[name] = provider
+	}
+	return provider
+}
+
+// Record updates feeds with data received from providers
+func (r *HealthRegistry) Record(feedData []*common.FeedData) {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, data := range feedData {
+		feed, exists := r.feeds[data.FeedID]
+		if !exists {
+			continue
+		}
+		feed.lastUpdate = now
+		feed.lastPrice = data.Value
+		feed.updates.add(now)
+	}
+}
+
+// IsStale reports tracked feeds which stopped receiving data, feeds unknown to the registry are never stale
+func (r *HealthRegistry) IsStale(feedID int32) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	feed, exists := r.feeds[feedID]
+	if !exists {
+		return false
+	}
+	return r.isStale(feed, time.Now())
+}
+
+func (r *HealthRegistry) isStale(feed *feedHealth, now time.Time) bool {
+	return !feed.lastUpdate.IsZero() && now.Sub(feed.lastUpdate) > r.staleThreshold
+}
+
+// Snapshot returns the health of every provider sorted by name, with feeds sorted by id
+func (r *HealthRegistry) Snapshot() []ProviderHealth {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]ProviderHealth, 0, len(r.providers))
+	for name, provider := range r.providers {
+		provider.messages.roll(now)
+		health := ProviderHealth{
+			Name:        name,
+			State:       provider.state(),
+			Connections: provider.connections,
+			Reconnects:  provider.reconnects,
+			LastMessage: timeOrNil(provider.lastMessage),
+			MessageRate: provider.messages.rate,
+			ParseErrors: provider.parseErrors,
+			Feeds:       make([]FeedHealth, 0, len(provider.feedIds)),
+		}
+		for index, shard := range provider.shards {
+			shard.messages.roll(now)
+			health.Shards = append(health.Shards, ShardHealth{
+				Index:       index,
+				State:       shard.state(),
+				Connections: shard.connections,
+				Reconnects:  shard.reconnects,
+				LastMessage: timeOrNil(shard.lastMessage),
+				MessageRate: shard.messages.rate,
+			})
+		}
+		sort.Slice(health.Shards, func(i, j int) bool { return health.Shards[i].Index < health.Shards[j].Index })
+
+		for _, id := range provider.feedIds {
+			feed, exists := r.feeds[id]
+			if !exists {
+				continue
+			}
+			feed.updates.roll(now)
+			health.Feeds = append(health.Feeds, FeedHealth{
+				ID:          id,
+				LastUpdate:  timeOrNil(feed.lastUpdate),
+				LastPrice:   feed.lastPrice,
+				MessageRate: feed.updates.rate,
+				Stale:       r.isStale(feed, now),
+			})
+		}
+		sort.Slice(health.Feeds, func(i, j int) bool { return health.Feeds[i].ID < health.Feeds[j].ID })
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Observer reports connection events of a provider
+func (r *HealthRegistry) Observer(name string) wss.Observer {
+	return &providerObserver{registry: r, name: name, shard: -1}
+}
+
+// ShardObserver reports connection events of a single shard, which count towards its provider as well
+func (r *HealthRegistry) ShardObserver(name string, shard int) wss.Observer {
+	return &providerObserver{registry: r, name: name, shard: shard}
+}
+
+type providerObserver struct {
+	registry *HealthRegistry
+	name     string
+	// -1 for providers served by a single connection
+	shard int
+}
+
+// update applies fn to the provider and to the observed shard, events of providers dropped on refresh are ignored unless they connect again
+func (o *providerObserver) update(create bool, fn func(*connectionHealth)) {
+	o.registry.mu.Lock()
+	defer o.registry.mu.Unlock()
+
+	provider, exists := o.registry.providers[o.name]
+	if !exists {
+		if !create {
+			return
+		}
+		provider = o.registry.provider(o.name)
+	}
+	fn(&provider.connectionHealth)
+
+	if o.shard < 0 {
+		return
+	}
+	if provider.shards == nil {
+		provider.shards = make(map[int]*connectionHealth)
+	}
+	shard, exists := provider.shards[o.shard]
+	if !exists {
+		shard = &connectionHealth{}
+		provider.shards[o.shard] = shard
+	}
+	fn(shard)
+}
+
+func (o *providerObserver) OnConnect() {
+	o.update(true, (*connectionHealth).connect)
+}
+
+func (o *providerObserver) OnDisconnect() {
+	o.update(false, (*connectionHealth).disconnect)
+}
+
+func (o *providerObserver) OnMessage() {
+	now := time.Now()
+	o.update(false, func(connection *connectionHealth) {
+		connection.message(now)
+	})
+}
+
+func (o *providerObserver) OnError(error) {
+	o.registry.mu.Lock()
+	defer o.registry.mu.Unlock()
+
+	if provider, exists := o.registry.providers[o.name]; exists {
+		provider.parseErrors++
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
@@ -20,23 +21,43 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &BinanceFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Combined
+	fetcher.BookFeedMap = config.FeedMaps.CombinedBooks
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
-	streams := []Stream{}
-	for feed := range fetcher.FeedMap {
-		streams = append(streams, Stream(strings.ToLower(feed)+"@miniTicker"))
+	tradeSymbols := fetcher.TradeVolumes.Symbols()
+	if len(fetcher.FeedMap) > 0 || len(tradeSymbols) > 0 {
+		ws, err := wss.NewWebsocketHelper(ctx,
+			wss.WithEndpoint(URL),
+			wss.WithSubscriptions(fetcher.subscriptions()),
+			wss.WithProxyUrl(config.Proxy),
+			wss.WithOptions(config.ConnectionOptions...))
+		if err != nil {
+			log.Error().Str("Player", "Binance").Err(err).Msg("error in binance.New")
+			return nil, err
+		}
+		fetcher.Ws = ws
 	}
-	subscription := Subscription{"SUBSCRIBE", streams, 1}
 
-	ws, err := wss.NewWebsocketHelper(ctx,
-		wss.WithEndpoint(URL),
-		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
-	if err != nil {
-		log.Error().Str("Player", "Binance").Err(err).Msg("error in binance.New")
-		return nil, err
+	// partial depth payloads don't carry the symbol, so books use the combined stream endpoint
+	if len(fetcher.BookFeedMap) > 0 {
+		streams := []Stream{}
+		for feed := range fetcher.BookFeedMap {
+			streams = append(streams, Stream(strings.ToLower(feed)+DepthStreamSuffix))
+		}
+		subscription := Subscription{"SUBSCRIBE", streams, 2}
+
+		bookWs, err := wss.NewWebsocketHelper(ctx,
+			wss.WithEndpoint(CombinedStreamURL),
+			wss.WithSubscriptions([]any{subscription}),
+			wss.WithProxyUrl(config.Proxy),
+			wss.WithOptions(config.ConnectionOptions...))
+		if err != nil {
+			log.Error().Str("Player", "Binance").Err(err).Msg("error in binance.New, failed to create book websocket")
+			return nil, err
+		}
+		fetcher.BookWs = bookWs
 	}
-	fetcher.Ws = ws
 
 	return fetcher, nil
 }
@@ -48,17 +69,66 @@ func (b *BinanceFetcher) handleMessage(ctx context.Context, message map[string]a
 		return err
 	}
 
+	if ticker.EventType == "trade" {
+		return b.handleTrade(message)
+	}
+
 	if ticker.EventType != "24hrMiniTicker" {
 		return nil
 	}
 
-	feedDataList, err := TickerToFeedData(ticker, b.FeedMap)
+	b.FeedMapMu.RLock()
+	feedMap := b.FeedMap
+	b.FeedMapMu.RUnlock()
+
+	feedDataList, err := TickerToFeedData(ticker, feedMap)
 	if err != nil {
 		log.Error().Str("Player", "Binance").Err(err).Msg("error in MiniTickerToFeedData")
 		return err
 	}
 
-	for _, feedData := range feedDataList {
+	for _, feedData := range b.TradeVolumes.Apply(feedDataList) {
+		b.FeedDataBuffer <- feedData
+	}
+
+	return nil
+}
+
+func (b *BinanceFetcher) handleTrade(message map[string]any) error {
+	trade, err := common.MessageToStruct[Trade](message)
+	if err != nil {
+		log.Error().Str("Player", "Binance").Err(err).Msg("error in MessageToTrade")
+		return err
+	}
+
+	size, err := common.VolumeStringToFloat64(trade.Quantity)
+	if err != nil {
+		log.Error().Str("Player", "Binance").Err(err).Msg("error in parsing trade quantity")
+		return err
+	}
+
+	b.TradeVolumes.AddTrade(trade.Symbol, size)
+	return nil
+}
+
+func (b *BinanceFetcher) handleBookMessage(ctx context.Context, message map[string]any) error {
+	depth, err := common.MessageToStruct[DepthStream](message)
+	if err != nil {
+		log.Error().Str("Player", "Binance").Err(err).Msg("error in MessageToDepthStream")
+		return err
+	}
+
+	if !strings.HasSuffix(depth.Stream, DepthStreamSuffix) {
+		return nil
+	}
+
+	feedDataList, err := DepthToFeedData(depth, b.BookFeedMap)
+	if err != nil {
+		log.Error().Str("Player", "Binance").Err(err).Msg("error in DepthToFeedData")
+		return err
+	}
+
+	for _, feedData := range b.TradeVolumes.Apply(feedDataList) {
 		b.FeedDataBuffer <- feedData
 	}
 
@@ -66,5 +136,68 @@ func (b *BinanceFetcher) handleMessage(ctx context.Context, message map[string]a
 }
 
 func (b *BinanceFetcher) Run(ctx context.Context) {
-	b.Ws.Run(ctx, b.handleMessage)
+	if b.BookWs != nil {
+		go b.BookWs.Run(ctx, b.handleBookMessage)
+	}
+	if b.Ws != nil {
+		b.Ws.Run(ctx, b.handleMessage)
+	}
+}
+
+// Subscribe adds ticker feeds over the live connection, book and volume feeds require a restart
+func (b *BinanceFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() || b.Ws == nil {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	b.FeedMapMu.Lock()
+	defer b.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(b.FeedMap, feedMaps.Combined)
+	b.FeedMap = feedMap
+	if len(newSymbols) == 0 {
+		return nil
+	}
+
+	message := Subscription{"SUBSCRIBE", tickerStreams(newSymbols), 3}
+	return b.Ws.UpdateSubscriptions(ctx, b.subscriptions(), []any{message})
+}
+
+func (b *BinanceFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() || b.Ws == nil {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	b.FeedMapMu.Lock()
+	defer b.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(b.FeedMap, feedMaps.Combined)
+	b.FeedMap = feedMap
+	if len(emptySymbols) == 0 {
+		return nil
+	}
+
+	message := Subscription{"UNSUBSCRIBE", tickerStreams(emptySymbols), 4}
+	return b.Ws.UpdateSubscriptions(ctx, b.subscriptions(), []any{message})
+}
+
+func (b *BinanceFetcher) subscriptions() []any {
+	symbols := make([]string, 0, len(b.FeedMap))
+	for feed := range b.FeedMap {
+		symbols = append(symbols, feed)
+	}
+
+	streams := tickerStreams(symbols)
+	for _, symbol := range b.TradeVolumes.Symbols() {
+		streams = append(streams, Stream(strings.ToLower(symbol)+TradeStreamSuffix))
+	}
+	return []any{Subscription{"SUBSCRIBE", streams, 1}}
+}
+
+func tickerStreams(symbols []string) []Stream {
+	streams := make([]Stream, 0, len(symbols))
+	for _, symbol := range symbols {
+		streams = append(streams, Stream(strings.ToLower(symbol)+"@miniTicker"))
+	}
+	return streams
 }
@@ -1,7 +1,13 @@
 package binance
 
 const (
-	URL = "wss://stream.binance.com:443/ws"
+	URL               = "wss://stream.binance.com:443/ws"
+	CombinedStreamURL = "wss://stream.binance.com:443/stream"
+	DepthStreamSuffix = "@depth20@100ms"
+	TradeStreamSuffix = "@trade"
+
+	// https://developers.binance.com/docs/binance-spot-api-docs/web-socket-streams#websocket-limits
+	MaxStreamsPerConnection = 1024
 )
 
 type Stream string
@@ -23,3 +29,21 @@ type MiniTicker struct {
 	Volume      string `json:"v"`
 	QuoteVolume string `json:"q"`
 }
+
+type DepthStream struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		LastUpdateID int64      `json:"lastUpdateId"`
+		Bids         [][]string `json:"bids"`
+		Asks         [][]string `json:"asks"`
+	} `json:"data"`
+}
+
+type Trade struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	Price     string `json:"p"`
+	Quantity  string `json:"q"`
+	TradeTime int64  `json:"T"`
+}
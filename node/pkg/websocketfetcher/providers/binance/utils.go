@@ -2,6 +2,7 @@ package binance
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
@@ -10,7 +11,7 @@ import (
 func TickerToFeedData(miniTicker MiniTicker, feedMap map[string][]int32) ([]*common.FeedData, error) {
 
 	timestamp := time.UnixMilli(miniTicker.EventTime)
-	value, err := common.PriceStringToFloat64(miniTicker.Price)
+	value, err := common.PriceStringToValue(miniTicker.Price)
 	if err != nil {
 		return nil, err
 	}
@@ -37,3 +38,23 @@ func TickerToFeedData(miniTicker MiniTicker, feedMap map[string][]int32) ([]*com
 
 	return result, nil
 }
+
+func DepthToFeedData(depth DepthStream, bookFeedMap map[string][]common.BookFeed) ([]*common.FeedData, error) {
+	symbol := strings.ToUpper(strings.TrimSuffix(depth.Stream, DepthStreamSuffix))
+	feeds, exists := bookFeedMap[symbol]
+	if !exists {
+		return nil, fmt.Errorf("book feed not found from binance for symbol: %s", symbol)
+	}
+
+	bids, err := common.ParseBookLevels(depth.Data.Bids)
+	if err != nil {
+		return nil, err
+	}
+
+	asks, err := common.ParseBookLevels(depth.Data.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.BookToFeedData(common.OrderBook{Bids: bids, Asks: asks, Timestamp: time.Now()}, feeds), nil
+}
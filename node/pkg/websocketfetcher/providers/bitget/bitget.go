@@ -41,7 +41,11 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithCustomReadFunc(fetcher.customReadFunc),
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		// ping timer expected to have 30 seconds interval
+		// https://www.bitget.com/api-doc/common/websocket-intro
+		wss.WithKeepalive(wss.Keepalive{Interval: 30 * time.Second, Message: "ping", Pong: "pong"}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Bitget").Err(err).Msg("error in bitget.New")
 		return nil, err
@@ -76,31 +80,9 @@ func (f *BitgetFetcher) handleMessage(ctx context.Context, message map[string]an
 }
 
 func (f *BitgetFetcher) Run(ctx context.Context) {
-	f.ping(ctx)
 	f.Ws.Run(ctx, f.handleMessage)
 }
 
-func (f *BitgetFetcher) ping(ctx context.Context) {
-	// ping timer expected to have 30 seconds interval
-	// https://www.bitget.com/api-doc/common/websocket-intro
-	ticker := time.NewTicker(30 * time.Second)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				log.Debug().Str("Player", "Bitget").Msg("sending ping message to bitget server")
-				err := f.Ws.RawWrite(ctx, "ping")
-				if err != nil {
-					log.Error().Str("Player", "Bitget").Err(err).Msg("error in bitget.ping")
-				}
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			}
-		}
-	}()
-}
-
 func (f *BitgetFetcher) customReadFunc(ctx context.Context, conn *websocket.Conn) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	_, data, err := conn.Read(ctx)
@@ -16,7 +16,7 @@ func ResponseToFeedDataList(data Response, feedMap map[string][]int32) []*common
 			log.Error().Str("instId", tick.InstId).Msg("feed not found")
 			continue
 		}
-		value, err := common.PriceStringToFloat64(tick.Price)
+		value, err := common.PriceStringToValue(tick.Price)
 		if err != nil {
 			log.Error().Err(err).Msg("failed to convert price string to float64")
 			continue
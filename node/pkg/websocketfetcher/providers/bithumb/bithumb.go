@@ -37,7 +37,8 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{tickerSubscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Bithumb").Err(err).Msg("error in bithumb.New")
 		return nil, err
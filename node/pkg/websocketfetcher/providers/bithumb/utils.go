@@ -33,7 +33,7 @@ func TransactionResponseToFeedDataList(data TransactionResponse, feedMap map[str
 		}
 		timestamp = timestamp.UTC()
 
-		price, err := common.PriceStringToFloat64(transaction.ContPrice)
+		price, err := common.PriceStringToValue(transaction.ContPrice)
 		if err != nil {
 			log.Error().Err(err).Msg("error in bithumb.TransactionResponseToFeedDataList")
 			continue
@@ -87,7 +87,7 @@ func TickerResponseToFeedData(data TickerResponse, feedMap map[string][]int32) (
 	timestamp := time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
 	timestamp = timestamp.UTC()
 
-	price, err := common.PriceStringToFloat64(data.Content.ClosePrice)
+	price, err := common.PriceStringToValue(data.Content.ClosePrice)
 	if err != nil {
 		log.Error().Err(err).Msg("error in bithumb.TickerResponseToFeedData, failed to convert price string to float64")
 		return nil, err
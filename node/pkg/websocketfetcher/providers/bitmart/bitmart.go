@@ -44,7 +44,9 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithCustomReadFunc(fetcher.customReadFunc),
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions(subscriptions),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithKeepalive(wss.Keepalive{Interval: 10 * time.Second, Message: "ping", Pong: "pong"}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Bitmart").Err(err).Msg("error in bitmart.New")
 		return nil, err
@@ -74,29 +76,9 @@ func (f *BitmartFetcher) handleMessage(ctx context.Context, message map[string]a
 }
 
 func (f *BitmartFetcher) Run(ctx context.Context) {
-	f.ping(ctx)
 	f.Ws.Run(ctx, f.handleMessage)
 }
 
-func (f *BitmartFetcher) ping(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Second)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				log.Debug().Str("Player", "Bitmart").Msg("sending ping message to bitmart server")
-				err := f.Ws.RawWrite(ctx, "ping")
-				if err != nil {
-					log.Error().Str("Player", "Bitmart").Err(err).Msg("error in bitmart.ping")
-				}
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			}
-		}
-	}()
-}
-
 func (f *BitmartFetcher) customReadFunc(ctx context.Context, conn *websocket.Conn) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	_, data, err := conn.Read(ctx)
@@ -18,9 +18,9 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) []*common
 			continue
 		}
 
-		value, err := common.PriceStringToFloat64(data.Price)
+		value, err := common.PriceStringToValue(data.Price)
 		if err != nil {
-			log.Warn().Str("Player", "Bitmart").Err(err).Msg("error in PriceStringToFloat64")
+			log.Warn().Str("Player", "Bitmart").Err(err).Msg("error in PriceStringToValue")
 			continue
 		}
 		volume, err := common.VolumeStringToFloat64(data.Volume)
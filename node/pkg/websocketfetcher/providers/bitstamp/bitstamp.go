@@ -42,7 +42,8 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions(subscriptions),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Bitstamp").Err(err).Msg("error in bitstamp.New")
 		return nil, err
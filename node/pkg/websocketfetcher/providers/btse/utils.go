@@ -20,7 +20,11 @@ func ResponseToFeedDataList(data Response, feedMap map[string][]int32, volumeCac
 		}
 
 		timestamp := time.UnixMilli(ticker.Timestamp)
-		price := common.FormatFloat64Price(ticker.Price)
+		price, err := common.FormatFloat64Price(ticker.Price)
+		if err != nil {
+			log.Warn().Str("Player", "btse").Err(err).Str("symbol", symbol).Msg("invalid price")
+			continue
+		}
 
 		for _, id := range ids {
 			entry := common.FeedData{
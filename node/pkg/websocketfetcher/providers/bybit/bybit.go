@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
@@ -20,29 +21,19 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &BybitFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Combined
+	fetcher.BookFeedMap = config.FeedMaps.CombinedBooks
+	fetcher.LocalBooks = common.NewLocalBookMap()
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
-
-	pairList := []string{}
-
-	for feed := range fetcher.FeedMap {
-		pairList = append(pairList, "tickers."+feed)
-	}
-
-	subscriptions := []any{}
-	// bybit allows maximum 10 pairs per subscription
-	// https://bybit-exchange.github.io/docs/v5/ws/connect#public-channel---args-limits
-	for i := 0; i < len(pairList); i += 10 {
-		end := common.Min(i+10, len(pairList))
-		subscriptions = append(subscriptions, Subscription{
-			Op:   "subscribe",
-			Args: pairList[i:end],
-		})
-	}
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
-		wss.WithSubscriptions(subscriptions),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithSubscriptions(fetcher.subscriptions()),
+		wss.WithProxyUrl(config.Proxy),
+		// bybit expects ping message every 20seconds for stable subscription
+		// https://bybit-exchange.github.io/docs/v5/ws/connect#how-to-send-the-heartbeat-packet
+		wss.WithKeepalive(wss.Keepalive{Interval: 20 * time.Second, Message: Heartbeat{Op: "ping"}, IsPong: isPong}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.New")
 		return nil, err
@@ -58,47 +49,150 @@ func (f *BybitFetcher) handleMessage(ctx context.Context, message map[string]any
 		return err
 	}
 
+	if response.Topic != nil && strings.HasPrefix(*response.Topic, TradeTopicPrefix) {
+		return f.handleTradeMessage(message)
+	}
+
+	if response.Topic != nil && strings.HasPrefix(*response.Topic, BookTopicPrefix) {
+		return f.handleBookMessage(message)
+	}
+
 	if response.Topic == nil || !strings.HasPrefix(*response.Topic, "tickers.") {
 		return nil
 	}
 
-	feedDataList, err := ResponseToFeedData(response, f.FeedMap)
+	f.FeedMapMu.RLock()
+	feedMap := f.FeedMap
+	f.FeedMapMu.RUnlock()
+
+	feedDataList, err := ResponseToFeedData(response, feedMap)
 	if err != nil {
 		log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.handleMessage")
 		return err
 	}
 
-	for _, feedData := range feedDataList {
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
+		f.FeedDataBuffer <- feedData
+	}
+
+	return nil
+}
+
+func (f *BybitFetcher) handleTradeMessage(message map[string]any) error {
+	response, err := common.MessageToStruct[TradeResponse](message)
+	if err != nil {
+		log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.handleTradeMessage")
+		return err
+	}
+
+	for _, trade := range response.Data {
+		size, parseErr := common.VolumeStringToFloat64(trade.Size)
+		if parseErr != nil {
+			log.Error().Str("Player", "Bybit").Err(parseErr).Msg("error in bybit.handleTradeMessage")
+			continue
+		}
+		f.TradeVolumes.AddTrade(trade.Symbol, size)
+	}
+	return nil
+}
+
+func (f *BybitFetcher) handleBookMessage(message map[string]any) error {
+	response, err := common.MessageToStruct[BookResponse](message)
+	if err != nil {
+		log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.handleBookMessage")
+		return err
+	}
+
+	feedDataList, err := BookResponseToFeedData(response, f.LocalBooks, f.BookFeedMap)
+	if err != nil {
+		log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.handleBookMessage")
+		return err
+	}
+
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
 		f.FeedDataBuffer <- feedData
 	}
 
 	return nil
 }
 
+func (f *BybitFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(f.FeedMap, feedMaps.Combined)
+	f.FeedMap = feedMap
+	if len(newSymbols) == 0 {
+		return nil
+	}
+
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), batchSubscriptions("subscribe", tickerTopics(newSymbols)))
+}
+
+func (f *BybitFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(f.FeedMap, feedMaps.Combined)
+	f.FeedMap = feedMap
+	if len(emptySymbols) == 0 {
+		return nil
+	}
+
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), batchSubscriptions("unsubscribe", tickerTopics(emptySymbols)))
+}
+
+func (f *BybitFetcher) subscriptions() []any {
+	symbols := make([]string, 0, len(f.FeedMap))
+	for feed := range f.FeedMap {
+		symbols = append(symbols, feed)
+	}
+
+	pairList := tickerTopics(symbols)
+	for feed := range f.BookFeedMap {
+		pairList = append(pairList, BookTopicPrefix+feed)
+	}
+	for _, symbol := range f.TradeVolumes.Symbols() {
+		pairList = append(pairList, TradeTopicPrefix+symbol)
+	}
+	return batchSubscriptions("subscribe", pairList)
+}
+
+func tickerTopics(symbols []string) []string {
+	topics := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		topics = append(topics, "tickers."+symbol)
+	}
+	return topics
+}
+
+func batchSubscriptions(op string, pairList []string) []any {
+	subscriptions := []any{}
+	// bybit allows maximum 10 pairs per subscription
+	// https://bybit-exchange.github.io/docs/v5/ws/connect#public-channel---args-limits
+	for i := 0; i < len(pairList); i += 10 {
+		end := common.Min(i+10, len(pairList))
+		subscriptions = append(subscriptions, Subscription{
+			Op:   op,
+			Args: pairList[i:end],
+		})
+	}
+	return subscriptions
+}
+
 func (f *BybitFetcher) Run(ctx context.Context) {
-	go f.ping(ctx)
 	f.Ws.Run(ctx, f.handleMessage)
 }
 
-func (f *BybitFetcher) ping(ctx context.Context) {
-	// bybit expects ping message every 20seconds for stable subscription
-	// https://bybit-exchange.github.io/docs/v5/ws/connect#how-to-send-the-heartbeat-packet
-	ticker := time.NewTicker(20 * time.Second)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				log.Debug().Str("Player", "Bybit").Msg("sending ping message to bybit server")
-				err := f.Ws.Write(ctx, Heartbeat{
-					Op: "ping",
-				})
-				if err != nil {
-					log.Error().Str("Player", "Bybit").Err(err).Msg("error in bybit.ping")
-				}
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			}
-		}
-	}()
+// spot answers with {"ret_msg": "pong", "op": "ping"}, other categories with {"op": "pong"}
+func isPong(message map[string]any) bool {
+	return message["ret_msg"] == "pong" || message["op"] == "pong"
 }
@@ -2,6 +2,18 @@ package bybit
 
 const URL = "wss://stream.bybit.com/v5/public/spot"
 
+// spot topics are capped per connection, requests are additionally batched by 10 args
+// https://bybit-exchange.github.io/docs/v5/ws/connect#public-channel---args-limits
+const MaxStreamsPerConnection = 100
+
+// 50 levels, first message is a snapshot followed by deltas
+const (
+	BookTopicPrefix = "orderbook.50."
+	BookDepth       = 50
+
+	TradeTopicPrefix = "publicTrade."
+)
+
 type Subscription struct {
 	Op   string   `json:"op"`
 	Args []string `json:"args"`
@@ -20,3 +32,23 @@ type Response struct {
 type Heartbeat struct {
 	Op string `json:"op"`
 }
+
+type BookResponse struct {
+	Topic     string `json:"topic"`
+	Timestamp int64  `json:"ts"`
+	Type      string `json:"type"`
+	Data      struct {
+		Symbol string     `json:"s"`
+		Bids   [][]string `json:"b"`
+		Asks   [][]string `json:"a"`
+	} `json:"data"`
+}
+
+type TradeResponse struct {
+	Topic string `json:"topic"`
+	Data  []struct {
+		Symbol string `json:"s"`
+		Price  string `json:"p"`
+		Size   string `json:"v"`
+	} `json:"data"`
+}
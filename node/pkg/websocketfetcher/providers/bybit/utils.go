@@ -14,7 +14,7 @@ func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.Fe
 	}
 
 	timestamp := time.UnixMilli(*data.Timestamp)
-	value, err := common.PriceStringToFloat64(data.Data.Price)
+	value, err := common.PriceStringToValue(data.Data.Price)
 	if err != nil {
 		return nil, err
 	}
@@ -37,3 +37,28 @@ func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.Fe
 
 	return result, nil
 }
+
+func BookResponseToFeedData(data BookResponse, localBooks *common.LocalBookMap, bookFeedMap map[string][]common.BookFeed) ([]*common.FeedData, error) {
+	feeds, exists := bookFeedMap[data.Data.Symbol]
+	if !exists {
+		return nil, fmt.Errorf("book feed not found")
+	}
+
+	bids, err := common.ParseBookLevels(data.Data.Bids)
+	if err != nil {
+		return nil, err
+	}
+
+	asks, err := common.ParseBookLevels(data.Data.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	book := localBooks.Get(data.Data.Symbol)
+	if data.Type == "snapshot" {
+		book.Reset()
+	}
+	book.Update(bids, asks)
+
+	return common.BookToFeedData(book.Snapshot(BookDepth, time.UnixMilli(data.Timestamp)), feeds), nil
+}
@@ -2,7 +2,9 @@ package coinbase
 
 import (
 	"context"
+	"strings"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
@@ -19,22 +21,16 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &CoinbaseFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Separated
+	fetcher.BookFeedMap = config.FeedMaps.SeparatedBooks
+	fetcher.LocalBooks = common.NewLocalBookMap()
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
-
-	pairListString := []string{}
-	for feed := range fetcher.FeedMap {
-		pairListString = append(pairListString, feed)
-	}
-	subscription := Subscription{
-		Type:       "subscribe",
-		ProductIds: pairListString,
-		Channels:   []string{"ticker"},
-	}
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
-		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithSubscriptions(fetcher.subscriptions()),
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Coinbase").Err(err).Msg("error in coinbase.New")
 		return nil, err
@@ -44,27 +40,180 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 }
 
 func (c *CoinbaseFetcher) handleMessage(ctx context.Context, message map[string]any) error {
+	messageType, _ := message["type"].(string)
+	switch messageType {
+	case "snapshot", "l2update":
+		return c.handleBookMessage(message)
+	case "match":
+		return c.handleMatch(message)
+	case "ticker":
+	default:
+		return nil
+	}
+
 	ticker, err := common.MessageToStruct[Ticker](message)
 	if err != nil {
 		return err
 	}
 
-	if ticker.Type != "ticker" {
+	c.FeedMapMu.RLock()
+	feedMap := c.FeedMap
+	c.FeedMapMu.RUnlock()
+
+	feedDataList := []*common.FeedData{}
+	if _, exists := feedMap[strings.ToUpper(ticker.ProductID)]; exists {
+		feedDataList, err = TickerToFeedData(ticker, feedMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	if bookFeeds, exists := c.BookFeedMap[strings.ToUpper(ticker.ProductID)]; exists {
+		bookFeedDataList, bookErr := TickerToBookFeedData(ticker, filterBookFeeds(bookFeeds, common.MidPriceSource))
+		if bookErr != nil {
+			return bookErr
+		}
+		feedDataList = append(feedDataList, bookFeedDataList...)
+	}
+
+	for _, feedData := range c.TradeVolumes.Apply(feedDataList) {
+		c.FeedDataBuffer <- feedData
+	}
+
+	return nil
+}
+
+func (c *CoinbaseFetcher) handleMatch(message map[string]any) error {
+	match, err := common.MessageToStruct[Match](message)
+	if err != nil {
+		return err
+	}
+
+	size, err := common.VolumeStringToFloat64(match.Size)
+	if err != nil {
+		log.Error().Str("Player", "Coinbase").Err(err).Msg("error in coinbase.handleMatch")
+		return err
+	}
+
+	c.TradeVolumes.AddTrade(strings.ToUpper(match.ProductID), size)
+	return nil
+}
+
+func (c *CoinbaseFetcher) handleBookMessage(message map[string]any) error {
+	update, err := common.MessageToStruct[BookUpdate](message)
+	if err != nil {
+		return err
+	}
+
+	bookFeeds, exists := c.BookFeedMap[strings.ToUpper(update.ProductID)]
+	if !exists {
 		return nil
 	}
 
-	feedDataList, err := TickerToFeedData(ticker, c.FeedMap)
+	feedDataList, err := BookUpdateToFeedData(update, c.LocalBooks, filterBookFeeds(bookFeeds, common.DepthPriceSource))
 	if err != nil {
+		log.Error().Str("Player", "Coinbase").Err(err).Msg("error in coinbase.handleBookMessage")
 		return err
 	}
 
-	for _, feedData := range feedDataList {
+	for _, feedData := range c.TradeVolumes.Apply(feedDataList) {
 		c.FeedDataBuffer <- feedData
 	}
 
 	return nil
 }
 
+func (c *CoinbaseFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	c.FeedMapMu.Lock()
+	defer c.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(c.FeedMap, feedMaps.Separated)
+	c.FeedMap = feedMap
+
+	// symbols with mid priced book feeds already receive the ticker channel
+	productIds := c.withoutBookFeeds(newSymbols)
+	if len(productIds) == 0 {
+		return nil
+	}
+
+	message := Subscription{Type: "subscribe", ProductIds: productIds, Channels: []string{"ticker"}}
+	return c.Ws.UpdateSubscriptions(ctx, c.subscriptions(), []any{message})
+}
+
+func (c *CoinbaseFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	c.FeedMapMu.Lock()
+	defer c.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(c.FeedMap, feedMaps.Separated)
+	c.FeedMap = feedMap
+
+	productIds := c.withoutBookFeeds(emptySymbols)
+	if len(productIds) == 0 {
+		return nil
+	}
+
+	message := Subscription{Type: "unsubscribe", ProductIds: productIds, Channels: []string{"ticker"}}
+	return c.Ws.UpdateSubscriptions(ctx, c.subscriptions(), []any{message})
+}
+
+func (c *CoinbaseFetcher) withoutBookFeeds(symbols []string) []string {
+	result := []string{}
+	for _, symbol := range symbols {
+		if _, exists := c.BookFeedMap[symbol]; !exists {
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
+
+func (c *CoinbaseFetcher) subscriptions() []any {
+	pairListString := []string{}
+	for feed := range c.FeedMap {
+		pairListString = append(pairListString, feed)
+	}
+
+	// mid prices come from the best bid and ask of the ticker channel, depth needs the level2 book
+	depthPairListString := []string{}
+	for feed, bookFeeds := range c.BookFeedMap {
+		if _, exists := c.FeedMap[feed]; !exists {
+			pairListString = append(pairListString, feed)
+		}
+		if len(filterBookFeeds(bookFeeds, common.DepthPriceSource)) > 0 {
+			depthPairListString = append(depthPairListString, feed)
+		}
+	}
+
+	subscriptions := []any{Subscription{
+		Type:       "subscribe",
+		ProductIds: pairListString,
+		Channels:   []string{"ticker"},
+	}}
+	if tradeSymbols := c.TradeVolumes.Symbols(); len(tradeSymbols) > 0 {
+		subscriptions = append(subscriptions, Subscription{
+			Type:       "subscribe",
+			ProductIds: tradeSymbols,
+			Channels:   []string{TradeChannel},
+		})
+	}
+	if len(depthPairListString) > 0 {
+		subscriptions = append(subscriptions, Subscription{
+			Type:       "subscribe",
+			ProductIds: depthPairListString,
+			Channels:   []string{BookChannel},
+		})
+	}
+
+	return subscriptions
+}
+
 func (k *CoinbaseFetcher) Run(ctx context.Context) {
 	k.Ws.Run(ctx, k.handleMessage)
 }
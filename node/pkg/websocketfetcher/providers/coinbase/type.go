@@ -1,7 +1,11 @@
 package coinbase
 
 const (
-	URL = "wss://ws-feed.exchange.coinbase.com"
+	URL         = "wss://ws-feed.exchange.coinbase.com"
+	BookChannel = "level2_batch"
+	BookDepth   = 50
+
+	TradeChannel = "matches"
 )
 
 type Ticker struct {
@@ -29,3 +33,21 @@ type Subscription struct {
 	ProductIds []string `json:"product_ids"`
 	Channels   []string `json:"channels"`
 }
+
+// snapshot carries bids and asks, l2update carries changes as [side, price, size]
+type BookUpdate struct {
+	Type      string     `json:"type"`
+	ProductID string     `json:"product_id"`
+	Bids      [][]string `json:"bids"`
+	Asks      [][]string `json:"asks"`
+	Changes   [][]string `json:"changes"`
+	Time      string     `json:"time"`
+}
+
+type Match struct {
+	Type      string `json:"type"`
+	ProductID string `json:"product_id"`
+	Price     string `json:"price"`
+	Size      string `json:"size"`
+	Time      string `json:"time"`
+}
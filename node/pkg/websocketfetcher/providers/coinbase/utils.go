@@ -21,7 +21,7 @@ func TickerToFeedData(ticker Ticker, feedMap map[string][]int32) ([]*common.Feed
 		timestamp = time.Now()
 	}
 
-	value, err := common.PriceStringToFloat64(ticker.Price)
+	value, err := common.PriceStringToValue(ticker.Price)
 	if err != nil {
 		return nil, err
 	}
@@ -43,3 +43,82 @@ func TickerToFeedData(ticker Ticker, feedMap map[string][]int32) ([]*common.Feed
 
 	return result, nil
 }
+
+func TickerToBookFeedData(ticker Ticker, bookFeeds []common.BookFeed) ([]*common.FeedData, error) {
+	if len(bookFeeds) == 0 || ticker.BestBid == "" || ticker.BestAsk == "" {
+		return nil, nil
+	}
+
+	levels, err := common.ParseBookLevels([][]string{
+		{ticker.BestBid, ticker.BestBidSize},
+		{ticker.BestAsk, ticker.BestAskSize},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, ticker.Time)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	book := common.OrderBook{Bids: levels[:1], Asks: levels[1:], Timestamp: timestamp}
+	return common.BookToFeedData(book, bookFeeds), nil
+}
+
+func BookUpdateToFeedData(update BookUpdate, localBooks *common.LocalBookMap, bookFeeds []common.BookFeed) ([]*common.FeedData, error) {
+	book := localBooks.Get(strings.ToUpper(update.ProductID))
+
+	switch update.Type {
+	case "snapshot":
+		bids, err := common.ParseBookLevels(update.Bids)
+		if err != nil {
+			return nil, err
+		}
+		asks, err := common.ParseBookLevels(update.Asks)
+		if err != nil {
+			return nil, err
+		}
+		book.Reset()
+		book.Update(bids, asks)
+	case "l2update":
+		bidChanges := [][]string{}
+		askChanges := [][]string{}
+		for _, change := range update.Changes {
+			if len(change) < 3 {
+				return nil, fmt.Errorf("invalid level2 change: %v", change)
+			}
+			if change[0] == "buy" {
+				bidChanges = append(bidChanges, change[1:])
+			} else {
+				askChanges = append(askChanges, change[1:])
+			}
+		}
+		bids, err := common.ParseBookLevels(bidChanges)
+		if err != nil {
+			return nil, err
+		}
+		asks, err := common.ParseBookLevels(askChanges)
+		if err != nil {
+			return nil, err
+		}
+		book.Update(bids, asks)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, update.Time)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	return common.BookToFeedData(book.Snapshot(BookDepth, timestamp), bookFeeds), nil
+}
+
+func filterBookFeeds(bookFeeds []common.BookFeed, priceSource string) []common.BookFeed {
+	result := []common.BookFeed{}
+	for _, bookFeed := range bookFeeds {
+		if bookFeed.PriceSource == priceSource {
+			result = append(result, bookFeed)
+		}
+	}
+	return result
+}
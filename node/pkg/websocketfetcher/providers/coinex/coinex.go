@@ -41,6 +41,7 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithSubscriptions([]any{subscription}),
 		wss.WithProxyUrl(config.Proxy),
 		wss.WithCustomReadFunc(fetcher.customReadFunc),
+		wss.WithOptions(config.ConnectionOptions...),
 	)
 	if err != nil {
 		log.Error().Str("Player", "Coinex").Err(err).Msg("error in coinex.New")
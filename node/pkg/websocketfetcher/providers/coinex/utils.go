@@ -17,9 +17,9 @@ func ResponseToFeedDataList(data Response, feedMap map[string][]int32) ([]*commo
 			log.Warn().Str("Player", "Coinex").Str("key", item.Market).Msg("feed not found")
 			continue
 		}
-		price, err := common.PriceStringToFloat64(item.Last)
+		price, err := common.PriceStringToValue(item.Last)
 		if err != nil {
-			log.Error().Str("Player", "Coinex").Err(err).Msg("error in PriceStringToFloat64")
+			log.Error().Str("Player", "Coinex").Err(err).Msg("error in PriceStringToValue")
 			continue
 		}
 		volume, err := common.VolumeStringToFloat64(item.Volume)
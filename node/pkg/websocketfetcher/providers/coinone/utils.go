@@ -15,7 +15,7 @@ func DataToFeedData(data Data, feedMap map[string][]int32) ([]*common.FeedData,
 	}
 
 	timestamp := time.UnixMilli(data.Timestamp)
-	value, err := common.PriceStringToFloat64(data.Last)
+	value, err := common.PriceStringToValue(data.Last)
 	if err != nil {
 		return nil, err
 	}
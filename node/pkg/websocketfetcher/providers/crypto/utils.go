@@ -16,7 +16,7 @@ func ResponseToFeedDataList(data Response, feedMap map[string][]int32) ([]*commo
 			continue
 		}
 		timestamp := time.UnixMilli(tick.Timestamp)
-		value, err := common.PriceStringToFloat64(*tick.LastTradePrice)
+		value, err := common.PriceStringToValue(*tick.LastTradePrice)
 		if err != nil {
 			log.Warn().Str("Player", "cryptodotcom").Str("priceValue", *tick.LastTradePrice).Err(err).Msg("failed to convert price string to float64")
 			continue
@@ -39,7 +39,13 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithKeepalive(wss.Keepalive{
+			Interval: PingInterval,
+			Message:  Ping{Channel: "spot.ping"},
+			IsPong:   func(message map[string]any) bool { return message["channel"] == "spot.pong" },
+		}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Gateio").Err(err).Msg("error in gateio.New")
 		return nil, err
@@ -1,7 +1,15 @@
 package gateio
 
+import "time"
+
 const URL = "wss://api.gateio.ws/ws/v4/"
 
+const PingInterval = 20 * time.Second
+
+type Ping struct {
+	Channel string `json:"channel"`
+}
+
 type Subscription struct {
 	Time    int64    `json:"time"`
 	Channel string   `json:"channel"`
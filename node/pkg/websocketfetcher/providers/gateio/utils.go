@@ -11,7 +11,7 @@ import (
 func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.FeedData, error) {
 
 	timestamp := time.Unix(data.Time, 0)
-	price, err := common.PriceStringToFloat64(data.Result.Last)
+	price, err := common.PriceStringToValue(data.Result.Last)
 	if err != nil {
 		return nil, err
 	}
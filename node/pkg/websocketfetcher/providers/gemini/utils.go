@@ -21,9 +21,9 @@ func TradeResponseToFeedDataList(data Response, feedMap map[string][]int32, volu
 			continue
 		}
 
-		price, err := common.PriceStringToFloat64(event.Price)
+		price, err := common.PriceStringToValue(event.Price)
 		if err != nil {
-			log.Warn().Str("Player", "Gemini").Err(err).Msg("error in PriceStringToFloat64")
+			log.Warn().Str("Player", "Gemini").Err(err).Msg("error in PriceStringToValue")
 			continue
 		}
 
@@ -0,0 +1,178 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/wss"
+	"github.com/rs/zerolog/log"
+)
+
+type GenericFetcher struct {
+	common.Fetcher
+	Name         string
+	Config       Config
+	SymbolMap    map[string][]int32
+	PingInterval time.Duration
+}
+
+// NewFactory returns a constructor with the same signature as the hand written providers,
+// so config driven exchanges can be registered next to them
+func NewFactory(name string, rawConfig json.RawMessage) func(context.Context, ...common.FetcherOption) (common.FetcherInterface, error) {
+	return func(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInterface, error) {
+		return New(ctx, name, rawConfig, opts...)
+	}
+}
+
+func New(ctx context.Context, name string, rawConfig json.RawMessage, opts ...common.FetcherOption) (common.FetcherInterface, error) {
+	config := &common.FetcherConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	providerConfig, err := ParseConfig(rawConfig)
+	if err != nil {
+		log.Error().Str("Player", "Generic").Str("provider", name).Err(err).Msg("error in generic.New, invalid provider config")
+		return nil, err
+	}
+
+	fetcher := &GenericFetcher{Name: name, Config: providerConfig, PingInterval: DefaultPingInterval}
+	fetcher.FeedMap = config.FeedMaps.Separated
+	fetcher.FeedDataBuffer = config.FeedDataBuffer
+
+	if providerConfig.PingInterval != "" {
+		fetcher.PingInterval, err = time.ParseDuration(providerConfig.PingInterval)
+		if err != nil {
+			log.Error().Str("Player", "Generic").Str("provider", name).Err(err).Msg("error in generic.New, invalid ping interval")
+			return nil, err
+		}
+	}
+
+	fetcher.SymbolMap, err = GetSymbolMap(providerConfig.SymbolFormat, fetcher.FeedMap)
+	if err != nil {
+		log.Error().Str("Player", "Generic").Str("provider", name).Err(err).Msg("error in generic.New, failed to format symbols")
+		return nil, err
+	}
+
+	subscriptions, err := fetcher.buildMessages(providerConfig.Subscription, fetcher.feeds())
+	if err != nil {
+		log.Error().Str("Player", "Generic").Str("provider", name).Err(err).Msg("error in generic.New, failed to build subscriptions")
+		return nil, err
+	}
+
+	connectionOptions := []wss.ConnectionOption{
+		wss.WithEndpoint(providerConfig.Endpoint),
+		wss.WithSubscriptions(subscriptions),
+		wss.WithProxyUrl(config.Proxy),
+	}
+	if providerConfig.PingMessage != "" {
+		connectionOptions = append(connectionOptions, wss.WithKeepalive(wss.Keepalive{
+			Interval: fetcher.PingInterval,
+			Message:  providerConfig.PingMessage,
+			Pong:     providerConfig.PongMessage,
+		}))
+	}
+	connectionOptions = append(connectionOptions, config.ConnectionOptions...)
+
+	ws, err := wss.NewWebsocketHelper(ctx, connectionOptions...)
+	if err != nil {
+		log.Error().Str("Player", "Generic").Str("provider", name).Err(err).Msg("error in generic.New")
+		return nil, err
+	}
+	fetcher.Ws = ws
+	return fetcher, nil
+}
+
+func (f *GenericFetcher) handleMessage(ctx context.Context, message map[string]any) error {
+	f.FeedMapMu.RLock()
+	symbolMap := f.SymbolMap
+	f.FeedMapMu.RUnlock()
+
+	feedDataList, err := ResponseToFeedData(message, f.Config, symbolMap)
+	if err != nil {
+		log.Error().Str("Player", "Generic").Str("provider", f.Name).Err(err).Msg("error in generic.handleMessage")
+		return err
+	}
+
+	for _, feedData := range feedDataList {
+		f.FeedDataBuffer <- feedData
+	}
+
+	return nil
+}
+
+func (f *GenericFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() || !HasSymbolPlaceholder(f.Config.Subscription) {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(f.FeedMap, feedMaps.Separated)
+	return f.updateFeedMap(ctx, feedMap, f.Config.Subscription, newSymbols)
+}
+
+func (f *GenericFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() || !HasSymbolPlaceholder(f.Config.Unsubscription) {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(f.FeedMap, feedMaps.Separated)
+	return f.updateFeedMap(ctx, feedMap, f.Config.Unsubscription, emptySymbols)
+}
+
+// updateFeedMap swaps the feed and symbol maps, then sends template messages for the changed feeds
+func (f *GenericFetcher) updateFeedMap(ctx context.Context, feedMap map[string][]int32, template json.RawMessage, changed []string) error {
+	symbolMap, err := GetSymbolMap(f.Config.SymbolFormat, feedMap)
+	if err != nil {
+		return err
+	}
+	f.FeedMap = feedMap
+	f.SymbolMap = symbolMap
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	messages, err := f.buildMessages(template, changed)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := f.buildMessages(f.Config.Subscription, f.feeds())
+	if err != nil {
+		return err
+	}
+	return f.Ws.UpdateSubscriptions(ctx, subscriptions, messages)
+}
+
+func (f *GenericFetcher) feeds() []string {
+	feeds := make([]string, 0, len(f.FeedMap))
+	for feed := range f.FeedMap {
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+func (f *GenericFetcher) buildMessages(template json.RawMessage, feeds []string) ([]any, error) {
+	symbols := make([]string, 0, len(feeds))
+	for _, feed := range feeds {
+		symbol, err := FormatSymbol(f.Config.SymbolFormat, feed)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+	return BuildSubscriptions(template, symbols)
+}
+
+func (f *GenericFetcher) Run(ctx context.Context) {
+	f.Ws.Run(ctx, f.handleMessage)
+}
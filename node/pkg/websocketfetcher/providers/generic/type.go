@@ -0,0 +1,66 @@
+package generic
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultSymbolFormat  = "{BASE}{QUOTE}"
+	DefaultPingInterval  = 20 * time.Second
+	DefaultTimestampUnit = "ms"
+
+	// replaced by a json array of every formatted symbol, one subscription message in total
+	SymbolsPlaceholder = `"{{symbols}}"`
+	// replaced by a single formatted symbol, one subscription message per symbol
+	SymbolPlaceholder = "{{symbol}}"
+)
+
+/*
+Config describes a simple ticker style exchange without code.
+
+	{
+	  "endpoint": "wss://stream.example.com/ws",
+	  "subscription": {"op": "subscribe", "args": "{{symbols}}"},
+	  "unsubscription": {"op": "unsubscribe", "args": "{{symbols}}"},
+	  "symbolFormat": "{BASE}-{QUOTE}",
+	  "pingMessage": "ping",
+	  "pingInterval": "20s",
+	  "pongMessage": "pong",
+	  "dataPath": "data",
+	  "symbolPath": "s",
+	  "pricePath": "c",
+	  "volumePath": "v",
+	  "timestampPath": "t",
+	  "timestampUnit": "ms"
+	}
+
+Paths are keys: dataPath is the key of the message holding a ticker or an array of tickers, and is left out
+when the message is the ticker itself, the other paths are keys of a ticker. Numbers may be sent as json numbers or strings.
+Exchanges nesting tickers deeper need a dedicated provider.
+Feeds are added over the live connection when subscription has a symbol placeholder,
+and removed when unsubscription is set, otherwise the provider is restarted on refresh.
+*/
+type Config struct {
+	Endpoint       string          `json:"endpoint"`
+	Subscription   json.RawMessage `json:"subscription"`
+	Unsubscription json.RawMessage `json:"unsubscription"`
+	SymbolFormat   string          `json:"symbolFormat"`
+	PingMessage    string          `json:"pingMessage"`
+	PingInterval   string          `json:"pingInterval"`
+	PongMessage    string          `json:"pongMessage"` // raw text reply to pingMessage, any message counts as a reply when empty
+	DataPath       string          `json:"dataPath"`
+	SymbolPath     string          `json:"symbolPath"`
+	PricePath      string          `json:"pricePath"`
+	VolumePath     string          `json:"volumePath"`
+	TimestampPath  string          `json:"timestampPath"`
+	TimestampUnit  string          `json:"timestampUnit"` // s, ms, us or ns
+}
+
+// Ticker holds the configured fields of a single ticker, decoded with common.MessageToStruct
+type Ticker struct {
+	Symbol    string      `json:"symbol"`
+	Price     json.Number `json:"price"`
+	Volume    json.Number `json:"volume"`
+	Timestamp json.Number `json:"timestamp"`
+}
@@ -0,0 +1,231 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+)
+
+func ParseConfig(raw json.RawMessage) (Config, error) {
+	var config Config
+	err := json.Unmarshal(raw, &config)
+	if err != nil {
+		return config, err
+	}
+
+	if config.Endpoint == "" || config.PricePath == "" {
+		return config, errorSentinel.ErrFetcherInvalidGenericProviderConfig
+	}
+
+	if config.SymbolFormat == "" {
+		config.SymbolFormat = DefaultSymbolFormat
+	}
+
+	if config.TimestampUnit == "" {
+		config.TimestampUnit = DefaultTimestampUnit
+	}
+
+	return config, nil
+}
+
+// FormatSymbol converts a separated feed name such as "BTC-USDT" into the exchange symbol,
+// {BASE} and {QUOTE} keep upper case while {base} and {quote} are lower cased
+func FormatSymbol(format string, separatedName string) (string, error) {
+	base, quote, found := strings.Cut(separatedName, "-")
+	if !found {
+		return "", fmt.Errorf("invalid feed name: %s", separatedName)
+	}
+
+	replacer := strings.NewReplacer(
+		"{BASE}", strings.ToUpper(base),
+		"{QUOTE}", strings.ToUpper(quote),
+		"{base}", strings.ToLower(base),
+		"{quote}", strings.ToLower(quote),
+	)
+	return replacer.Replace(format), nil
+}
+
+// GetSymbolMap maps upper cased exchange symbols into feed ids
+func GetSymbolMap(format string, feedMap map[string][]int32) (map[string][]int32, error) {
+	result := make(map[string][]int32, len(feedMap))
+	for name, ids := range feedMap {
+		symbol, err := FormatSymbol(format, name)
+		if err != nil {
+			return nil, err
+		}
+		result[strings.ToUpper(symbol)] = append(result[strings.ToUpper(symbol)], ids...)
+	}
+	return result, nil
+}
+
+// HasSymbolPlaceholder reports whether messages built from template target individual symbols
+func HasSymbolPlaceholder(template json.RawMessage) bool {
+	raw := string(template)
+	return strings.Contains(raw, SymbolsPlaceholder) || strings.Contains(raw, SymbolPlaceholder)
+}
+
+func BuildSubscriptions(template json.RawMessage, symbols []string) ([]any, error) {
+	if len(template) == 0 {
+		return nil, nil
+	}
+
+	sorted := append([]string{}, symbols...)
+	sort.Strings(sorted)
+
+	raw := string(template)
+	messages := []string{}
+	switch {
+	case strings.Contains(raw, SymbolsPlaceholder):
+		encoded, err := json.Marshal(sorted)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, strings.ReplaceAll(raw, SymbolsPlaceholder, string(encoded)))
+	case strings.Contains(raw, SymbolPlaceholder):
+		for _, symbol := range sorted {
+			messages = append(messages, strings.ReplaceAll(raw, SymbolPlaceholder, symbol))
+		}
+	default:
+		messages = append(messages, raw)
+	}
+
+	subscriptions := make([]any, 0, len(messages))
+	for _, message := range messages {
+		if !json.Valid([]byte(message)) {
+			return nil, errorSentinel.ErrFetcherInvalidGenericProviderConfig
+		}
+		subscriptions = append(subscriptions, json.RawMessage(message))
+	}
+	return subscriptions, nil
+}
+
+func ResponseToFeedData(message map[string]any, config Config, symbolMap map[string][]int32) ([]*common.FeedData, error) {
+	result := []*common.FeedData{}
+	for _, entry := range tickerEntries(message, config.DataPath) {
+		ticker, err := common.MessageToStruct[Ticker](map[string]any{
+			"symbol":    entry[config.SymbolPath],
+			"price":     entry[config.PricePath],
+			"volume":    entry[config.VolumePath],
+			"timestamp": entry[config.TimestampPath],
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ticker.Price == "" {
+			// subscription acks, pongs and other non ticker messages
+			continue
+		}
+
+		price, err := common.PriceStringToValue(ticker.Price.String())
+		if err != nil {
+			return nil, err
+		}
+
+		ids, err := feedIds(ticker, config, symbolMap)
+		if err != nil {
+			return nil, err
+		}
+
+		volume := float64(0)
+		if ticker.Volume != "" {
+			volume, err = common.VolumeStringToFloat64(ticker.Volume.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		timestamp, err := toTimestamp(ticker, config)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			feedData := new(common.FeedData)
+			feedData.FeedID = id
+			feedData.Value = price
+			feedData.Volume = volume
+			feedData.Timestamp = &timestamp
+
+			result = append(result, feedData)
+		}
+	}
+
+	return result, nil
+}
+
+// tickerEntries returns the message itself without dataPath, otherwise the ticker or every ticker of the array under it
+func tickerEntries(message map[string]any, dataPath string) []map[string]any {
+	if dataPath == "" {
+		return []map[string]any{message}
+	}
+
+	switch data := message[dataPath].(type) {
+	case map[string]any:
+		return []map[string]any{data}
+	case []any:
+		entries := make([]map[string]any, 0, len(data))
+		for _, item := range data {
+			if entry, ok := item.(map[string]any); ok {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	default:
+		return nil
+	}
+}
+
+func feedIds(ticker Ticker, config Config, symbolMap map[string][]int32) ([]int32, error) {
+	if config.SymbolPath == "" {
+		// without a symbol path the connection can only serve a single symbol
+		if len(symbolMap) != 1 {
+			return nil, errorSentinel.ErrFetcherInvalidGenericProviderConfig
+		}
+		for _, ids := range symbolMap {
+			return ids, nil
+		}
+	}
+
+	if ticker.Symbol == "" {
+		return nil, fmt.Errorf("symbol not found in message: %s", config.SymbolPath)
+	}
+
+	ids, exists := symbolMap[strings.ToUpper(ticker.Symbol)]
+	if !exists {
+		return nil, fmt.Errorf("feed not found for symbol: %s", ticker.Symbol)
+	}
+	return ids, nil
+}
+
+func toTimestamp(ticker Ticker, config Config) (time.Time, error) {
+	if ticker.Timestamp == "" {
+		return time.Now(), nil
+	}
+
+	value, err := ticker.Timestamp.Int64()
+	if err != nil {
+		fractional, floatErr := ticker.Timestamp.Float64()
+		if floatErr != nil {
+			return time.Time{}, floatErr
+		}
+		value = int64(fractional)
+	}
+
+	switch config.TimestampUnit {
+	case "s":
+		return time.Unix(value, 0), nil
+	case "ms":
+		return time.UnixMilli(value), nil
+	case "us":
+		return time.UnixMicro(value), nil
+	case "ns":
+		return time.Unix(0, value), nil
+	default:
+		return time.Time{}, errorSentinel.ErrFetcherInvalidGenericProviderConfig
+	}
+}
@@ -38,6 +38,7 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithProxyUrl(config.Proxy),
 		wss.WithReadLimit(IncreasedReadLimit),
 		wss.WithCustomReadFunc(fetcher.customReadFunc),
+		wss.WithOptions(config.ConnectionOptions...),
 	)
 	if err != nil {
 		log.Error().Str("Player", "Gopax").Err(err).Msg("error in gopax.New")
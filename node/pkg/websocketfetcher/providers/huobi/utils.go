@@ -12,7 +12,10 @@ import (
 func ResponseToFeedData(response Response, feedMap map[string][]int32) ([]*common.FeedData, error) {
 
 	timestamp := time.UnixMilli(response.Ts)
-	price := common.FormatFloat64Price(response.Tick.LastPrice)
+	price, err := common.FormatFloat64Price(response.Tick.LastPrice)
+	if err != nil {
+		return nil, err
+	}
 
 	splitted := strings.Split(response.Ch, ".")
 	if len(splitted) < 3 || splitted[2] != "ticker" {
@@ -10,7 +10,7 @@ import (
 
 func DataToFeedData(data Ticker, feedMap map[string][]int32) ([]*common.FeedData, error) {
 	timestamp := time.UnixMilli(data.Timestamp)
-	value, err := common.PriceStringToFloat64(data.Last)
+	value, err := common.PriceStringToValue(data.Last)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
@@ -19,28 +20,16 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &KrakenFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Separated
+	fetcher.BookFeedMap = config.FeedMaps.SeparatedBooks
+	fetcher.LocalBooks = common.NewLocalBookMap()
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
-
-	symbols := []string{}
-	for feed := range fetcher.FeedMap {
-		symbol := strings.ReplaceAll(feed, "-", "/")
-		symbols = append(symbols, symbol)
-	}
-
-	params := Params{
-		Channel: "ticker",
-		Symbol:  symbols,
-	}
-
-	subscription := Subscription{
-		Method: "subscribe",
-		Params: params,
-	}
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
-		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithSubscriptions(fetcher.subscriptions()),
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Kraken").Err(err).Msg("error in kraken.New")
 		return nil, err
@@ -56,18 +45,152 @@ func (f *KrakenFetcher) handleMessage(ctx context.Context, message map[string]an
 		return err
 	}
 
+	if raw.Channel == BookChannel {
+		return f.handleBookMessage(message)
+	}
+
+	if raw.Channel == TradeChannel {
+		return f.handleTradeMessage(message)
+	}
+
 	if raw.Channel != "ticker" {
 		return nil
 	}
 
-	feedDataList := ResponseToFeedData(raw, f.FeedMap)
+	f.FeedMapMu.RLock()
+	feedMap := f.FeedMap
+	f.FeedMapMu.RUnlock()
 
-	for _, feedData := range feedDataList {
+	feedDataList := ResponseToFeedData(raw, feedMap)
+
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
 		f.FeedDataBuffer <- feedData
 	}
 	return nil
 }
 
+func (f *KrakenFetcher) handleBookMessage(message map[string]any) error {
+	response, err := common.MessageToStruct[BookResponse](message)
+	if err != nil {
+		log.Error().Str("Player", "Kraken").Err(err).Msg("error in kraken.handleBookMessage")
+		return err
+	}
+
+	for _, feedData := range f.TradeVolumes.Apply(BookResponseToFeedData(response, f.LocalBooks, f.BookFeedMap)) {
+		f.FeedDataBuffer <- feedData
+	}
+	return nil
+}
+
+func (f *KrakenFetcher) handleTradeMessage(message map[string]any) error {
+	response, err := common.MessageToStruct[TradeResponse](message)
+	if err != nil {
+		log.Error().Str("Player", "Kraken").Err(err).Msg("error in kraken.handleTradeMessage")
+		return err
+	}
+
+	for _, trade := range response.Data {
+		f.TradeVolumes.AddTrade(strings.ReplaceAll(trade.Symbol, "/", "-"), trade.Qty)
+	}
+	return nil
+}
+
+func (f *KrakenFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(f.FeedMap, feedMaps.Separated)
+	f.FeedMap = feedMap
+	if len(newSymbols) == 0 {
+		return nil
+	}
+
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), []any{tickerSubscription("subscribe", newSymbols)})
+}
+
+func (f *KrakenFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(f.FeedMap, feedMaps.Separated)
+	f.FeedMap = feedMap
+	if len(emptySymbols) == 0 {
+		return nil
+	}
+
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), []any{tickerSubscription("unsubscribe", emptySymbols)})
+}
+
+func (f *KrakenFetcher) subscriptions() []any {
+	feeds := make([]string, 0, len(f.FeedMap))
+	for feed := range f.FeedMap {
+		feeds = append(feeds, feed)
+	}
+
+	subscriptions := []any{}
+	if len(feeds) > 0 {
+		subscriptions = append(subscriptions, tickerSubscription("subscribe", feeds))
+	}
+
+	bookSymbols := []string{}
+	for feed := range f.BookFeedMap {
+		bookSymbols = append(bookSymbols, strings.ReplaceAll(feed, "-", "/"))
+	}
+	if len(bookSymbols) > 0 {
+		depth := BookDepth
+		subscriptions = append(subscriptions, Subscription{
+			Method: "subscribe",
+			Params: Params{
+				Channel: BookChannel,
+				Symbol:  bookSymbols,
+				Depth:   &depth,
+			},
+		})
+	}
+
+	tradeSymbols := []string{}
+	for _, symbol := range f.TradeVolumes.Symbols() {
+		tradeSymbols = append(tradeSymbols, strings.ReplaceAll(symbol, "-", "/"))
+	}
+	if len(tradeSymbols) > 0 {
+		// skip the snapshot of recent trades so the window only counts live activity
+		snapshot := false
+		subscriptions = append(subscriptions, Subscription{
+			Method: "subscribe",
+			Params: Params{
+				Channel:  TradeChannel,
+				Symbol:   tradeSymbols,
+				Snapshot: &snapshot,
+			},
+		})
+	}
+
+	return subscriptions
+}
+
+func tickerSubscription(method string, feeds []string) Subscription {
+	symbols := make([]string, 0, len(feeds))
+	for _, feed := range feeds {
+		symbols = append(symbols, strings.ReplaceAll(feed, "-", "/"))
+	}
+
+	return Subscription{
+		Method: method,
+		Params: Params{
+			Channel: "ticker",
+			Symbol:  symbols,
+		},
+	}
+}
+
 func (f *KrakenFetcher) Run(ctx context.Context) {
 	f.Ws.Run(ctx, f.handleMessage)
 }
@@ -2,9 +2,19 @@ package kraken
 
 const URL = "wss://ws.kraken.com/v2"
 
+// snapshot followed by updates, levels outside the subscribed depth must be dropped locally
+const (
+	BookChannel = "book"
+	BookDepth   = 10
+
+	TradeChannel = "trade"
+)
+
 type Params struct {
-	Channel string   `json:"channel"`
-	Symbol  []string `json:"symbol"`
+	Channel  string   `json:"channel"`
+	Symbol   []string `json:"symbol"`
+	Depth    *int     `json:"depth,omitempty"`
+	Snapshot *bool    `json:"snapshot,omitempty"`
 }
 
 type Subscription struct {
@@ -21,3 +31,29 @@ type Response struct {
 		Volume float64 `json:"volume"`
 	} `json:"data"`
 }
+
+type BookLevel struct {
+	Price float64 `json:"price"`
+	Qty   float64 `json:"qty"`
+}
+
+type BookResponse struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	Data    []struct {
+		Symbol    string      `json:"symbol"`
+		Bids      []BookLevel `json:"bids"`
+		Asks      []BookLevel `json:"asks"`
+		Timestamp string      `json:"timestamp"`
+	} `json:"data"`
+}
+
+type TradeResponse struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	Data    []struct {
+		Symbol string  `json:"symbol"`
+		Price  float64 `json:"price"`
+		Qty    float64 `json:"qty"`
+	} `json:"data"`
+}
@@ -16,7 +16,10 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) []*common
 			continue
 		}
 
-		value := common.FormatFloat64Price(data.Price)
+		value, err := common.FormatFloat64Price(data.Price)
+		if err != nil {
+			continue
+		}
 		timestamp := time.Now()
 		volume := data.Volume
 
@@ -31,3 +34,37 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) []*common
 	}
 	return feedDataList
 }
+
+func BookResponseToFeedData(response BookResponse, localBooks *common.LocalBookMap, bookFeedMap map[string][]common.BookFeed) []*common.FeedData {
+	feedDataList := []*common.FeedData{}
+	for _, data := range response.Data {
+		symbol := strings.ReplaceAll(data.Symbol, "/", "-")
+		feeds, exists := bookFeedMap[symbol]
+		if !exists {
+			continue
+		}
+
+		book := localBooks.Get(symbol)
+		if response.Type == "snapshot" {
+			book.Reset()
+		}
+		book.Update(toBookLevels(data.Bids), toBookLevels(data.Asks))
+		book.Trim(BookDepth)
+
+		timestamp, err := time.Parse(time.RFC3339Nano, data.Timestamp)
+		if err != nil {
+			timestamp = time.Now()
+		}
+
+		feedDataList = append(feedDataList, common.BookToFeedData(book.Snapshot(BookDepth, timestamp), feeds)...)
+	}
+	return feedDataList
+}
+
+func toBookLevels(levels []BookLevel) []common.BookLevel {
+	result := make([]common.BookLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, common.BookLevel{Price: level.Price, Size: level.Qty})
+	}
+	return result
+}
@@ -16,8 +16,6 @@ import (
 
 type KucoinFetcher common.Fetcher
 
-var pingInterval = DEFAULT_PING_INTERVAL
-
 func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInterface, error) {
 	config := &common.FetcherConfig{}
 	for _, opt := range opts {
@@ -45,7 +43,13 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithCustomDialFunc(fetcher.customDialFunc),
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithKeepalive(wss.Keepalive{
+			Interval: DEFAULT_PING_INTERVAL * time.Millisecond,
+			Message:  Ping{ID: 1, Type: "ping"},
+			IsPong:   func(message map[string]any) bool { return message["type"] == "pong" },
+		}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Kucoin").Err(err).Msg("error in kucoin.New")
 		return nil, err
@@ -79,33 +83,17 @@ func (f *KucoinFetcher) handleMessage(ctx context.Context, message map[string]an
 }
 
 func (f *KucoinFetcher) Run(ctx context.Context) {
-	go f.pingJob(ctx)
 	f.Ws.Run(ctx, f.handleMessage)
 }
 
-func (f *KucoinFetcher) pingJob(ctx context.Context) {
-	pingTicker := time.NewTicker(time.Duration(pingInterval) * time.Millisecond)
-
-	for range pingTicker.C {
-		log.Debug().Msg("sending ping message to kucoin server")
-		err := f.Ws.Write(ctx, Ping{
-			ID:   1,
-			Type: "ping",
-		})
-		if err != nil {
-			log.Error().Str("Player", "Kucoin").Err(err).Msg("error in kucoin.pingJob")
-			return
-		}
-	}
-}
-
 func (f *KucoinFetcher) customDialFunc(ctx context.Context, endpoint string, dialOptions *websocket.DialOptions) (*websocket.Conn, *http.Response, error) {
 	token, interval, err := f.getTokenAndPingInterval()
 	if err != nil {
 		log.Error().Str("Player", "Kucoin").Err(err).Msg("error in kucoin.customDialFunc")
 		return nil, nil, err
 	}
-	pingInterval = interval
+	// ping interval is handed out with the token, applied to the connection about to be opened
+	f.Ws.Keepalive.Interval = time.Duration(interval) * time.Millisecond
 	log.Debug().Int("pingInterval", interval).Msg("kucoin ping interval set")
 
 	url := endpoint + "?token=" + token
@@ -18,7 +18,10 @@ func RawDataToFeedData(raw SymbolSnapshotRaw, feedMap map[string][]int32) ([]*co
 	}
 
 	timestamp := time.UnixMilli(snapshot.Time)
-	value := common.FormatFloat64Price(snapshot.Price)
+	value, err := common.FormatFloat64Price(snapshot.Price)
+	if err != nil {
+		return nil, err
+	}
 	volume := snapshot.Volume
 
 	result := []*common.FeedData{}
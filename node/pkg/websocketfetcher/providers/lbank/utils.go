@@ -21,7 +21,10 @@ func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.Fe
 		return nil, err
 	}
 	timestamp := timestampRaw.UTC()
-	value := common.FormatFloat64Price(data.Tick.Latest)
+	value, err := common.FormatFloat64Price(data.Tick.Latest)
+	if err != nil {
+		return nil, err
+	}
 	symbol := strings.ToUpper(strings.ReplaceAll(data.Pair, "_", "-"))
 	volume := data.Tick.Vol
 
@@ -22,13 +22,20 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	subscription := Subscription{
 		Method: "SUBSCRIPTION",
-		Params: []string{"spot@public.miniTickers.v3.api@UTC+0"},
+		Params: []string{MiniTickersChannel},
 	}
 
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		// the server drops connections idle for a minute, pongs are answered as json text frames
+		wss.WithKeepalive(wss.Keepalive{
+			Interval: PingInterval,
+			Message:  Subscription{Method: "PING"},
+			IsPong:   func(message map[string]any) bool { return message["msg"] == "PONG" },
+		}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Mexc").Err(err).Msg("error in mexc.New")
 		return nil, err
@@ -37,13 +44,7 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 	return fetcher, nil
 }
 
-func (f *MexcFetcher) handleMessage(ctx context.Context, message map[string]any) error {
-	response, err := common.MessageToStruct[BatchResponse](message)
-	if err != nil {
-		log.Error().Str("Player", "Mexc").Err(err).Msg("failed to parse message to response")
-		return err
-	}
-
+func (f *MexcFetcher) handleMessage(ctx context.Context, response *PushDataV3ApiWrapper) error {
 	feedDataList, err := ResponseToFeedDataList(response, f.FeedMap)
 	if err != nil {
 		log.Error().Str("Player", "Mexc").Err(err).Msg("failed to extract feedData from response")
@@ -58,5 +59,5 @@ func (f *MexcFetcher) handleMessage(ctx context.Context, message map[string]any)
 }
 
 func (f *MexcFetcher) Run(ctx context.Context) {
-	f.Ws.Run(ctx, f.handleMessage)
+	wss.RunTyped(ctx, f.Ws, Decode, f.handleMessage)
 }
@@ -0,0 +1,483 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: mexc.proto
+
+package mexc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PublicMiniTickerV3Api struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol             string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price              string `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Rate               string `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	ZonedRate          string `protobuf:"bytes,4,opt,name=zonedRate,proto3" json:"zonedRate,omitempty"`
+	High               string `protobuf:"bytes,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low                string `protobuf:"bytes,6,opt,name=low,proto3" json:"low,omitempty"`
+	Volume             string `protobuf:"bytes,7,opt,name=volume,proto3" json:"volume,omitempty"`
+	Quantity           string `protobuf:"bytes,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	LastCloseRate      string `protobuf:"bytes,9,opt,name=lastCloseRate,proto3" json:"lastCloseRate,omitempty"`
+	LastCloseZonedRate string `protobuf:"bytes,10,opt,name=lastCloseZonedRate,proto3" json:"lastCloseZonedRate,omitempty"`
+	LastCloseHigh      string `protobuf:"bytes,11,opt,name=lastCloseHigh,proto3" json:"lastCloseHigh,omitempty"`
+	LastCloseLow       string `protobuf:"bytes,12,opt,name=lastCloseLow,proto3" json:"lastCloseLow,omitempty"`
+}
+
+func (x *PublicMiniTickerV3Api) Reset() {
+	*x = PublicMiniTickerV3Api{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mexc_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicMiniTickerV3Api) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicMiniTickerV3Api) ProtoMessage() {}
+
+func (x *PublicMiniTickerV3Api) ProtoReflect() protoreflect.Message {
+	mi := &file_mexc_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicMiniTickerV3Api.ProtoReflect.Descriptor instead.
+func (*PublicMiniTickerV3Api) Descriptor() ([]byte, []int) {
+	return file_mexc_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PublicMiniTickerV3Api) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetZonedRate() string {
+	if x != nil {
+		return x.ZonedRate
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetHigh() string {
+	if x != nil {
+		return x.High
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetLow() string {
+	if x != nil {
+		return x.Low
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetVolume() string {
+	if x != nil {
+		return x.Volume
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetLastCloseRate() string {
+	if x != nil {
+		return x.LastCloseRate
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetLastCloseZonedRate() string {
+	if x != nil {
+		return x.LastCloseZonedRate
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetLastCloseHigh() string {
+	if x != nil {
+		return x.LastCloseHigh
+	}
+	return ""
+}
+
+func (x *PublicMiniTickerV3Api) GetLastCloseLow() string {
+	if x != nil {
+		return x.LastCloseLow
+	}
+	return ""
+}
+
+type PublicMiniTickersV3Api struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*PublicMiniTickerV3Api `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *PublicMiniTickersV3Api) Reset() {
+	*x = PublicMiniTickersV3Api{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mexc_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublicMiniTickersV3Api) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicMiniTickersV3Api) ProtoMessage() {}
+
+func (x *PublicMiniTickersV3Api) ProtoReflect() protoreflect.Message {
+	mi := &file_mexc_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicMiniTickersV3Api.ProtoReflect.Descriptor instead.
+func (*PublicMiniTickersV3Api) Descriptor() ([]byte, []int) {
+	return file_mexc_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublicMiniTickersV3Api) GetItems() []*PublicMiniTickerV3Api {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type PushDataV3ApiWrapper struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	// Types that are assignable to Body:
+	//	*PushDataV3ApiWrapper_PublicMiniTickers
+	//	*PushDataV3ApiWrapper_PublicMiniTicker
+	Body       isPushDataV3ApiWrapper_Body `protobuf_oneof:"body"`
+	Symbol     *string                     `protobuf:"bytes,3,opt,name=symbol,proto3,oneof" json:"symbol,omitempty"`
+	SymbolId   *string                     `protobuf:"bytes,4,opt,name=symbolId,proto3,oneof" json:"symbolId,omitempty"`
+	CreateTime *int64                      `protobuf:"varint,5,opt,name=createTime,proto3,oneof" json:"createTime,omitempty"`
+	SendTime   *int64                      `protobuf:"varint,6,opt,name=sendTime,proto3,oneof" json:"sendTime,omitempty"`
+}
+
+func (x *PushDataV3ApiWrapper) Reset() {
+	*x = PushDataV3ApiWrapper{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mexc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PushDataV3ApiWrapper) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushDataV3ApiWrapper) ProtoMessage() {}
+
+func (x *PushDataV3ApiWrapper) ProtoReflect() protoreflect.Message {
+	mi := &file_mexc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushDataV3ApiWrapper.ProtoReflect.Descriptor instead.
+func (*PushDataV3ApiWrapper) Descriptor() ([]byte, []int) {
+	return file_mexc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PushDataV3ApiWrapper) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (m *PushDataV3ApiWrapper) GetBody() isPushDataV3ApiWrapper_Body {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (x *PushDataV3ApiWrapper) GetPublicMiniTickers() *PublicMiniTickersV3Api {
+	if x, ok := x.GetBody().(*PushDataV3ApiWrapper_PublicMiniTickers); ok {
+		return x.PublicMiniTickers
+	}
+	return nil
+}
+
+func (x *PushDataV3ApiWrapper) GetPublicMiniTicker() *PublicMiniTickerV3Api {
+	if x, ok := x.GetBody().(*PushDataV3ApiWrapper_PublicMiniTicker); ok {
+		return x.PublicMiniTicker
+	}
+	return nil
+}
+
+func (x *PushDataV3ApiWrapper) GetSymbol() string {
+	if x != nil && x.Symbol != nil {
+		return *x.Symbol
+	}
+	return ""
+}
+
+func (x *PushDataV3ApiWrapper) GetSymbolId() string {
+	if x != nil && x.SymbolId != nil {
+		return *x.SymbolId
+	}
+	return ""
+}
+
+func (x *PushDataV3ApiWrapper) GetCreateTime() int64 {
+	if x != nil && x.CreateTime != nil {
+		return *x.CreateTime
+	}
+	return 0
+}
+
+func (x *PushDataV3ApiWrapper) GetSendTime() int64 {
+	if x != nil && x.SendTime != nil {
+		return *x.SendTime
+	}
+	return 0
+}
+
+type isPushDataV3ApiWrapper_Body interface {
+	isPushDataV3ApiWrapper_Body()
+}
+
+type PushDataV3ApiWrapper_PublicMiniTickers struct {
+	PublicMiniTickers *PublicMiniTickersV3Api `protobuf:"bytes,309,opt,name=publicMiniTickers,proto3,oneof"`
+}
+
+type PushDataV3ApiWrapper_PublicMiniTicker struct {
+	PublicMiniTicker *PublicMiniTickerV3Api `protobuf:"bytes,310,opt,name=publicMiniTicker,proto3,oneof"`
+}
+
+func (*PushDataV3ApiWrapper_PublicMiniTickers) isPushDataV3ApiWrapper_Body() {}
+
+func (*PushDataV3ApiWrapper_PublicMiniTicker) isPushDataV3ApiWrapper_Body() {}
+
+var File_mexc_proto protoreflect.FileDescriptor
+
+var file_mexc_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x6d, 0x65, 0x78, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x6d, 0x65,
+	0x78, 0x63, 0x22, 0xf1, 0x02, 0x0a, 0x15, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d, 0x69, 0x6e,
+	0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x56, 0x33, 0x41, 0x70, 0x69, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x7a, 0x6f, 0x6e, 0x65, 0x64, 0x52, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x7a, 0x6f, 0x6e, 0x65, 0x64, 0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x68, 0x69, 0x67, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x69, 0x67, 0x68,
+	0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c,
+	0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75,
+	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x71, 0x75,
+	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x52, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c,
+	0x61, 0x73, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x12,
+	0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x64, 0x52, 0x61,
+	0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x64, 0x52, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d,
+	0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x48, 0x69, 0x67, 0x68, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x48, 0x69,
+	0x67, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4c,
+	0x6f, 0x77, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x4c, 0x6f, 0x77, 0x22, 0x4b, 0x0a, 0x16, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x4d, 0x69, 0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x56, 0x33, 0x41, 0x70, 0x69,
+	0x12, 0x31, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6d, 0x65, 0x78, 0x63, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d, 0x69, 0x6e,
+	0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x56, 0x33, 0x41, 0x70, 0x69, 0x52, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x22, 0x8b, 0x03, 0x0a, 0x14, 0x50, 0x75, 0x73, 0x68, 0x44, 0x61, 0x74, 0x61,
+	0x56, 0x33, 0x41, 0x70, 0x69, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x4d, 0x0a, 0x11, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x4d, 0x69, 0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0xb5, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x78, 0x63, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x4d, 0x69, 0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x56, 0x33, 0x41, 0x70, 0x69,
+	0x48, 0x00, 0x52, 0x11, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d, 0x69, 0x6e, 0x69, 0x54, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x4a, 0x0a, 0x10, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d,
+	0x69, 0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0xb6, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x6d, 0x65, 0x78, 0x63, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d, 0x69,
+	0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x56, 0x33, 0x41, 0x70, 0x69, 0x48, 0x00, 0x52,
+	0x10, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4d, 0x69, 0x6e, 0x69, 0x54, 0x69, 0x63, 0x6b, 0x65,
+	0x72, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x01, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x02, 0x52, 0x08, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x23, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x79, 0x6d,
+	0x62, 0x6f, 0x6c, 0x49, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x42, 0x3b, 0x5a, 0x39, 0x62, 0x69, 0x73, 0x6f, 0x6e, 0x61, 0x69, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x6d, 0x69, 0x6b, 0x6f, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x77,
+	0x65, 0x62, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x2f,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x73, 0x2f, 0x6d, 0x65, 0x78, 0x63, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mexc_proto_rawDescOnce sync.Once
+	file_mexc_proto_rawDescData = file_mexc_proto_rawDesc
+)
+
+func file_mexc_proto_rawDescGZIP() []byte {
+	file_mexc_proto_rawDescOnce.Do(func() {
+		file_mexc_proto_rawDescData = protoimpl.X.CompressGZIP(file_mexc_proto_rawDescData)
+	})
+	return file_mexc_proto_rawDescData
+}
+
+var file_mexc_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_mexc_proto_goTypes = []interface{}{
+	(*PublicMiniTickerV3Api)(nil),  // 0: mexc.PublicMiniTickerV3Api
+	(*PublicMiniTickersV3Api)(nil), // 1: mexc.PublicMiniTickersV3Api
+	(*PushDataV3ApiWrapper)(nil),   // 2: mexc.PushDataV3ApiWrapper
+}
+var file_mexc_proto_depIdxs = []int32{
+	0, // 0: mexc.PublicMiniTickersV3Api.items:type_name -> mexc.PublicMiniTickerV3Api
+	1, // 1: mexc.PushDataV3ApiWrapper.publicMiniTickers:type_name -> mexc.PublicMiniTickersV3Api
+	0, // 2: mexc.PushDataV3ApiWrapper.publicMiniTicker:type_name -> mexc.PublicMiniTickerV3Api
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_mexc_proto_init() }
+func file_mexc_proto_init() {
+	if File_mexc_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mexc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublicMiniTickerV3Api); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mexc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublicMiniTickersV3Api); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mexc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushDataV3ApiWrapper); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_mexc_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*PushDataV3ApiWrapper_PublicMiniTickers)(nil),
+		(*PushDataV3ApiWrapper_PublicMiniTicker)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mexc_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_mexc_proto_goTypes,
+		DependencyIndexes: file_mexc_proto_depIdxs,
+		MessageInfos:      file_mexc_proto_msgTypes,
+	}.Build()
+	File_mexc_proto = out.File
+	file_mexc_proto_rawDesc = nil
+	file_mexc_proto_goTypes = nil
+	file_mexc_proto_depIdxs = nil
+}
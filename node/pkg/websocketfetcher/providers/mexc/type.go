@@ -1,28 +1,15 @@
 package mexc
 
-const URL = "wss://wbs.mexc.com/ws"
+import "time"
 
-type Subscription struct {
-	Method string   `json:"method"`
-	Params []string `json:"params"`
-}
+const URL = "wss://wbs-api.mexc.com/ws"
 
-type Ticker struct {
-	Symbol                string `json:"s"`
-	Price                 string `json:"p"`
-	PercentChange         string `json:"r"`
-	TimezonePercentChange string `json:"tr"`
-	HighPrice             string `json:"h"`
-	LowPrice              string `json:"l"`
-	Volume                string `json:"v"`
-	QuoteVolume           string `json:"q"`
-	LastRT                string `json:"lastRT"`
-	MergeTimes            string `json:"MT"`
-	NetValue              string `json:"NV"`
-}
+// protobuf stream, json mini ticker channels are no longer served on the v3 endpoint
+const MiniTickersChannel = "spot@public.miniTickers.v3.api.pb@UTC+0"
 
-type BatchResponse struct {
-	Channel string   `json:"c"`
-	Data    []Ticker `json:"d"`
-	Time    int64    `json:"t"`
+const PingInterval = 20 * time.Second
+
+type Subscription struct {
+	Method string   `json:"method"`
+	Params []string `json:"params,omitempty"`
 }
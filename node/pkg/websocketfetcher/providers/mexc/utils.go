@@ -4,26 +4,50 @@ import (
 	"time"
 
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
 )
 
-func ResponseToFeedDataList(response BatchResponse, feedMap map[string][]int32) ([]*common.FeedData, error) {
+// Decode unmarshals binary push frames, text frames only carry subscription acks and pongs
+func Decode(messageType websocket.MessageType, data []byte) (*PushDataV3ApiWrapper, bool, error) {
+	if messageType != websocket.MessageBinary {
+		return nil, false, nil
+	}
+
+	response := &PushDataV3ApiWrapper{}
+	err := proto.Unmarshal(data, response)
+	if err != nil {
+		return nil, false, err
+	}
+	return response, true, nil
+}
+
+func ResponseToFeedDataList(response *PushDataV3ApiWrapper, feedMap map[string][]int32) ([]*common.FeedData, error) {
 	feedDataList := []*common.FeedData{}
 
-	timestamp := time.UnixMilli(int64(response.Time))
+	timestamp := time.Now()
+	if response.SendTime != nil {
+		timestamp = time.UnixMilli(response.GetSendTime())
+	}
+
+	items := response.GetPublicMiniTickers().GetItems()
+	if ticker := response.GetPublicMiniTicker(); ticker != nil {
+		items = append(items, ticker)
+	}
 
-	for _, item := range response.Data {
-		ids, exists := feedMap[item.Symbol]
+	for _, item := range items {
+		ids, exists := feedMap[item.GetSymbol()]
 		if !exists {
 			continue
 		}
 
-		value, err := common.PriceStringToFloat64(item.Price)
+		value, err := common.PriceStringToValue(item.GetPrice())
 		if err != nil {
 			return feedDataList, err
 		}
 
-		// mexc is using quote volume and volume in a opposite way
-		volume, err := common.VolumeStringToFloat64(item.QuoteVolume)
+		// mexc volume is the quote volume, quantity holds the base volume
+		volume, err := common.VolumeStringToFloat64(item.GetQuantity())
 		if err != nil {
 			return feedDataList, err
 		}
@@ -3,6 +3,7 @@ package okx
 import (
 	"context"
 
+	errorSentinel "bisonai.com/miko/node/pkg/error"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
@@ -18,26 +19,16 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &OkxFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Separated
+	fetcher.BookFeedMap = config.FeedMaps.SeparatedBooks
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
-
-	args := []Arg{}
-	for feed := range fetcher.FeedMap {
-		arg := Arg{
-			Channel: "tickers",
-			InstId:  feed,
-		}
-		args = append(args, arg)
-	}
-
-	subscription := Subscription{
-		Operation: "subscribe",
-		Args:      args,
-	}
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
-		wss.WithSubscriptions([]any{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithSubscriptions(fetcher.subscriptions()),
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithKeepalive(wss.Keepalive{Interval: PingInterval, Message: "ping", Pong: "pong"}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Okx").Err(err).Msg("error in okx.New")
 		return nil, err
@@ -58,9 +49,44 @@ func (f *OkxFetcher) handleMessage(ctx context.Context, message map[string]any)
 		return nil
 	}
 
-	feedDataList := ResponseToFeedData(raw, f.FeedMap)
+	if raw.Arg.Channel == TradeChannel {
+		trades, tradeErr := common.MessageToStruct[TradeResponse](message)
+		if tradeErr != nil {
+			log.Error().Str("Player", "Okx").Err(tradeErr).Msg("error in okx.handleMessage, failed to parse trades")
+			return tradeErr
+		}
 
-	for _, feedData := range feedDataList {
+		for _, trade := range trades.Data {
+			size, parseErr := common.VolumeStringToFloat64(trade.Size)
+			if parseErr != nil {
+				log.Error().Str("Player", "Okx").Err(parseErr).Msg("error in okx.handleMessage, failed to parse trade")
+				continue
+			}
+			f.TradeVolumes.AddTrade(trade.InstId, size)
+		}
+		return nil
+	}
+
+	if raw.Arg.Channel == BookChannel {
+		book, bookErr := common.MessageToStruct[BookResponse](message)
+		if bookErr != nil {
+			log.Error().Str("Player", "Okx").Err(bookErr).Msg("error in okx.handleMessage, failed to parse book")
+			return bookErr
+		}
+
+		for _, feedData := range f.TradeVolumes.Apply(BookResponseToFeedData(book, f.BookFeedMap)) {
+			f.FeedDataBuffer <- feedData
+		}
+		return nil
+	}
+
+	f.FeedMapMu.RLock()
+	feedMap := f.FeedMap
+	f.FeedMapMu.RUnlock()
+
+	feedDataList := ResponseToFeedData(raw, feedMap)
+
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
 		f.FeedDataBuffer <- feedData
 	}
 
@@ -70,3 +96,74 @@ func (f *OkxFetcher) handleMessage(ctx context.Context, message map[string]any)
 func (f *OkxFetcher) Run(ctx context.Context) {
 	f.Ws.Run(ctx, f.handleMessage)
 }
+
+func (f *OkxFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, newSymbols := common.AddFeedIds(f.FeedMap, feedMaps.Separated)
+	f.FeedMap = feedMap
+	if len(newSymbols) == 0 {
+		return nil
+	}
+
+	message := Subscription{Operation: "subscribe", Args: tickerArgs(newSymbols)}
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), []any{message})
+}
+
+func (f *OkxFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	if feedMaps.HasBookOrVolumeFeeds() {
+		return errorSentinel.ErrFetcherSubscriptionNotSupported
+	}
+
+	f.FeedMapMu.Lock()
+	defer f.FeedMapMu.Unlock()
+
+	feedMap, emptySymbols := common.RemoveFeedIds(f.FeedMap, feedMaps.Separated)
+	f.FeedMap = feedMap
+	if len(emptySymbols) == 0 {
+		return nil
+	}
+
+	message := Subscription{Operation: "unsubscribe", Args: tickerArgs(emptySymbols)}
+	return f.Ws.UpdateSubscriptions(ctx, f.subscriptions(), []any{message})
+}
+
+func (f *OkxFetcher) subscriptions() []any {
+	args := []Arg{}
+	for feed := range f.FeedMap {
+		args = append(args, Arg{
+			Channel: "tickers",
+			InstId:  feed,
+		})
+	}
+	for feed := range f.BookFeedMap {
+		args = append(args, Arg{
+			Channel: BookChannel,
+			InstId:  feed,
+		})
+	}
+	for _, symbol := range f.TradeVolumes.Symbols() {
+		args = append(args, Arg{
+			Channel: TradeChannel,
+			InstId:  symbol,
+		})
+	}
+
+	return []any{Subscription{
+		Operation: "subscribe",
+		Args:      args,
+	}}
+}
+
+func tickerArgs(symbols []string) []Arg {
+	args := make([]Arg, 0, len(symbols))
+	for _, symbol := range symbols {
+		args = append(args, Arg{Channel: "tickers", InstId: symbol})
+	}
+	return args
+}
@@ -1,8 +1,23 @@
 package okx
 
+import "time"
+
 // rate limits to 3 request / sec
 const URL = "wss://ws.okx.com:8443/ws/v5/public"
 
+// connections without any message for 30 seconds are dropped, "ping" is answered with "pong"
+// https://www.okx.com/docs-v5/en/#overview-websocket-connect
+const PingInterval = 20 * time.Second
+
+// top 5 levels, pushed as a full snapshot every time
+const BookChannel = "books5"
+
+const TradeChannel = "trades"
+
+// subscribe requests are capped at 64kb and 480 requests per hour per connection,
+// keeping channels per connection small keeps both reconnects and resubscribes cheap
+const MaxStreamsPerConnection = 100
+
 type Arg struct {
 	Channel string `json:"channel"`
 	InstId  string `json:"instId"`
@@ -23,3 +38,22 @@ type Response struct {
 		Timestamp string `json:"ts"`
 	} `json:"data"`
 }
+
+type BookResponse struct {
+	Arg  Arg `json:"arg"`
+	Data []struct {
+		InstId    string     `json:"instId"`
+		Asks      [][]string `json:"asks"`
+		Bids      [][]string `json:"bids"`
+		Timestamp string     `json:"ts"`
+	} `json:"data"`
+}
+
+type TradeResponse struct {
+	Arg  Arg `json:"arg"`
+	Data []struct {
+		InstId string `json:"instId"`
+		Price  string `json:"px"`
+		Size   string `json:"sz"`
+	} `json:"data"`
+}
@@ -16,9 +16,9 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) []*common
 			continue
 		}
 
-		value, err := common.PriceStringToFloat64(data.Price)
+		value, err := common.PriceStringToValue(data.Price)
 		if err != nil {
-			log.Error().Err(err).Str("Player", "OKX").Msg("error in PriceStringToFloat64")
+			log.Error().Err(err).Str("Player", "OKX").Msg("error in PriceStringToValue")
 			continue
 		}
 		intTimestamp, err := strconv.ParseInt(data.Timestamp, 10, 64)
@@ -46,3 +46,32 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) []*common
 	}
 	return feedDataList
 }
+
+func BookResponseToFeedData(response BookResponse, bookFeedMap map[string][]common.BookFeed) []*common.FeedData {
+	feedDataList := []*common.FeedData{}
+	for _, data := range response.Data {
+		feeds, exists := bookFeedMap[data.InstId]
+		if !exists {
+			continue
+		}
+
+		bids, err := common.ParseBookLevels(data.Bids)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "OKX").Msg("error in ParseBookLevels")
+			continue
+		}
+		asks, err := common.ParseBookLevels(data.Asks)
+		if err != nil {
+			log.Error().Err(err).Str("Player", "OKX").Msg("error in ParseBookLevels")
+			continue
+		}
+
+		timestamp := time.Now()
+		if intTimestamp, parseErr := strconv.ParseInt(data.Timestamp, 10, 64); parseErr == nil {
+			timestamp = time.UnixMilli(intTimestamp)
+		}
+
+		feedDataList = append(feedDataList, common.BookToFeedData(common.OrderBook{Bids: bids, Asks: asks, Timestamp: timestamp}, feeds)...)
+	}
+	return feedDataList
+}
@@ -88,7 +88,8 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]any{raw}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "OrangeX").Err(err).Msg("error in orangex.New")
 		return nil, err
@@ -132,9 +133,9 @@ func TickerResponseToFeedData(data TickerResponse, feedMap map[string][]int32) (
 		return nil, nil
 	}
 
-	value, err := common.PriceStringToFloat64(data.Params.Data.LastPrice)
+	value, err := common.PriceStringToValue(data.Params.Data.LastPrice)
 	if err != nil {
-		log.Error().Str("Player", "OrangeX").Err(err).Msg("error in PriceStringToFloat64")
+		log.Error().Str("Player", "OrangeX").Err(err).Msg("error in PriceStringToValue")
 		return nil, err
 	}
 
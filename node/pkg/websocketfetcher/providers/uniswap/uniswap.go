@@ -3,6 +3,7 @@ package uniswap
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"math"
 	"math/big"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"bisonai.com/miko/node/pkg/chain/utils"
 	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
 	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/rs/zerolog/log"
@@ -18,8 +20,13 @@ import (
 type UniswapFetcher common.DexFetcher
 
 const (
-	SLOT0 = "function slot0() external view returns (uint160 sqrtPriceX96, int24 tick, uint16 observationIndex, uint16 observationCardinality, uint16 observationCardinalityNext, uint8 feeProtocol, bool unlocked)"
-	EVENT = `event Swap(
+	// bits of precision for 1.0001^tick, far beyond the DECIMALS digits which are kept
+	TickRatioPrecision = 256
+
+	SLOT0     = "function slot0() external view returns (uint160 sqrtPriceX96, int24 tick, uint16 observationIndex, uint16 observationCardinality, uint16 observationCardinalityNext, uint8 feeProtocol, bool unlocked)"
+	LIQUIDITY = "function liquidity() external view returns (uint128)"
+	OBSERVE   = "function observe(uint32[] secondsAgos) external view returns (int56[] tickCumulatives, uint160[] secondsPerLiquidityCumulativeX128s)"
+	EVENT     = `event Swap(
     address indexed sender,
     address indexed recipient,
     int256 amount0,
@@ -53,20 +60,16 @@ func (f *UniswapFetcher) Run(ctx context.Context) {
 
 func (f *UniswapFetcher) run(ctx context.Context, feed common.Feed) {
 	// 1. get initial data once through contract call
-	price, err := f.getInitialPrice(ctx, feed)
+	initialFeedData, err := f.getInitialFeedData(ctx, feed)
 	if err != nil {
 		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.run, failed to get initial price")
 		return
 	}
 
-	now := time.Now()
-	initialFeedData := &common.FeedData{
-		FeedID:    feed.ID,
-		Value:     *price,
-		Timestamp: &now,
+	if initialFeedData != nil {
+		log.Debug().Str("Player", "Uniswap").Any("feedData", initialFeedData).Msg("initial price fetched")
+		f.FeedDataBuffer <- initialFeedData
 	}
-	log.Debug().Str("Player", "Uniswap").Any("feedData", initialFeedData).Msg("initial price fetched")
-	f.FeedDataBuffer <- initialFeedData
 	// 2. get subsequent data through websocket
 	err = f.subscribeEvent(ctx, feed)
 	if err != nil {
@@ -75,14 +78,39 @@ func (f *UniswapFetcher) run(ctx context.Context, feed common.Feed) {
 	}
 }
 
-func (f *UniswapFetcher) getInitialPrice(ctx context.Context, feed common.Feed) (*float64, error) {
+func (f *UniswapFetcher) getInitialFeedData(ctx context.Context, feed common.Feed) (*common.FeedData, error) {
 	definition := new(common.DexFeedDefinition)
 	err := json.Unmarshal(feed.Definition, &definition)
 	if err != nil {
-		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.getInitialPrice, failed to unmarshal definition")
+		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.getInitialFeedData, failed to unmarshal definition")
+		return nil, err
+	}
+
+	chainType, ok := f.WebsocketChainReader.ChainType(definition.ChainId)
+	if !ok {
+		log.Error().Str("Player", "Uniswap").Str("chainId", definition.ChainId).Msg("error in uniswap.getInitialFeedData, chain type not found")
+		return nil, errorSentinel.ErrFetcherNoMatchingChainID
+	}
+
+	sqrtPrice, err := f.getSqrtPriceThroughSlotCall(ctx, chainType, definition)
+	if err != nil {
 		return nil, err
 	}
-	return f.getPriceThroughSlotCall(ctx, definition)
+
+	var liquidity *big.Int
+	if definition.MinLiquidity != nil || isLiquidityWeighted(definition) {
+		liquidity, err = f.getLiquidity(ctx, chainType, definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	feedData, err := f.toFeedData(ctx, feed, definition, chainType, sqrtPrice, liquidity)
+	if errors.Is(err, errorSentinel.ErrFetcherInsufficientDexLiquidity) {
+		log.Warn().Str("Player", "Uniswap").Str("feed", feed.Name).Msg("initial price skipped, pool liquidity below minimum")
+		return nil, nil
+	}
+	return feedData, err
 }
 
 func (f *UniswapFetcher) subscribeEvent(ctx context.Context, feed common.Feed) error {
@@ -96,13 +124,7 @@ func (f *UniswapFetcher) subscribeEvent(ctx context.Context, feed common.Feed) e
 	return f.readSwapEvent(ctx, feed, definition)
 }
 
-func (f *UniswapFetcher) getPriceThroughSlotCall(ctx context.Context, definition *common.DexFeedDefinition) (*float64, error) {
-	chainType, ok := f.WebsocketChainReader.ChainIdToChainType[definition.ChainId]
-	if !ok {
-		log.Error().Str("Player", "Uniswap").Str("chainId", definition.ChainId).Msg("error in uniswap.getInitialPrice, chain type not found")
-		return nil, errorSentinel.ErrFetcherNoMatchingChainID
-	}
-
+func (f *UniswapFetcher) getSqrtPriceThroughSlotCall(ctx context.Context, chainType websocketchainreader.BlockchainType, definition *common.DexFeedDefinition) (*big.Int, error) {
 	rawResult, err := f.WebsocketChainReader.ReadContractOnce(ctx, chainType, definition.Address, SLOT0)
 	if err != nil {
 		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.getInitialPrice, failed to read contract")
@@ -121,14 +143,104 @@ func (f *UniswapFetcher) getPriceThroughSlotCall(ctx context.Context, definition
 		return nil, errorSentinel.ErrFetcherFailedBigIntConvert
 	}
 
-	return getTokenPrice(sqrtPrice, definition)
+	return sqrtPrice, nil
+}
+
+func (f *UniswapFetcher) getLiquidity(ctx context.Context, chainType websocketchainreader.BlockchainType, definition *common.DexFeedDefinition) (*big.Int, error) {
+	rawResult, err := f.WebsocketChainReader.ReadContractOnce(ctx, chainType, definition.Address, LIQUIDITY)
+	if err != nil {
+		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.getLiquidity, failed to read contract")
+		return nil, err
+	}
+
+	rawResultSlice, ok := rawResult.([]interface{})
+	if !ok || len(rawResultSlice) < 1 {
+		log.Error().Str("Player", "Uniswap").Msg("error in uniswap.getLiquidity, failed to get slice result")
+		return nil, errorSentinel.ErrFetcherFailedToGetDexResultSlice
+	}
+
+	liquidity, ok := rawResultSlice[0].(*big.Int)
+	if !ok {
+		log.Error().Str("Player", "Uniswap").Msg("error in uniswap.getLiquidity, failed to convert liquidity")
+		return nil, errorSentinel.ErrFetcherFailedBigIntConvert
+	}
+
+	return liquidity, nil
+}
+
+func (f *UniswapFetcher) getTwapPrice(ctx context.Context, chainType websocketchainreader.BlockchainType, definition *common.DexFeedDefinition) (*big.Int, error) {
+	window := *definition.TwapWindow
+	rawResult, err := f.WebsocketChainReader.ReadContractOnce(ctx, chainType, definition.Address, OBSERVE, []uint32{window, 0})
+	if err != nil {
+		log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.getTwapPrice, failed to read contract")
+		return nil, err
+	}
+
+	rawResultSlice, ok := rawResult.([]interface{})
+	if !ok || len(rawResultSlice) < 1 {
+		log.Error().Str("Player", "Uniswap").Msg("error in uniswap.getTwapPrice, failed to get slice result")
+		return nil, errorSentinel.ErrFetcherFailedToGetDexResultSlice
+	}
+
+	tickCumulatives, ok := rawResultSlice[0].([]*big.Int)
+	if !ok {
+		log.Error().Str("Player", "Uniswap").Msg("error in uniswap.getTwapPrice, failed to convert tick cumulatives")
+		return nil, errorSentinel.ErrFetcherFailedBigIntConvert
+	}
+
+	tick, err := AverageTick(tickCumulatives, window)
+	if err != nil {
+		return nil, err
+	}
+
+	return getTokenPriceFromTick(tick, definition)
+}
+
+// builds feed data from the pool state, applying the twap, min liquidity and liquidity weight settings of the definition
+func (f *UniswapFetcher) toFeedData(ctx context.Context, feed common.Feed, definition *common.DexFeedDefinition, chainType websocketchainreader.BlockchainType, sqrtPrice *big.Int, liquidity *big.Int) (*common.FeedData, error) {
+	if definition.MinLiquidity != nil {
+		minLiquidity, ok := new(big.Int).SetString(*definition.MinLiquidity, 10)
+		if !ok {
+			return nil, errorSentinel.ErrFetcherInvalidInput
+		}
+		if liquidity == nil || liquidity.Cmp(minLiquidity) < 0 {
+			return nil, errorSentinel.ErrFetcherInsufficientDexLiquidity
+		}
+	}
+
+	var price *big.Int
+	var err error
+	if definition.TwapWindow != nil && *definition.TwapWindow > 0 {
+		price, err = f.getTwapPrice(ctx, chainType, definition)
+	} else {
+		price, err = getTokenPrice(sqrtPrice, definition)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var volume float64
+	if isLiquidityWeighted(definition) {
+		volume, err = LiquidityWeight(liquidity, sqrtPrice, definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	return &common.FeedData{
+		FeedID:    feed.ID,
+		Value:     price,
+		Volume:    volume,
+		Timestamp: &now,
+	}, nil
 }
 
 func (f *UniswapFetcher) readSwapEvent(ctx context.Context, feed common.Feed, definition *common.DexFeedDefinition) error {
 	logChannel := make(chan types.Log)
 	address := definition.Address
 
-	chainType, ok := f.WebsocketChainReader.ChainIdToChainType[definition.ChainId]
+	chainType, ok := f.WebsocketChainReader.ChainType(definition.ChainId)
 	if !ok {
 		log.Error().Str("Player", "Uniswap").Str("chainId", definition.ChainId).Msg("error in uniswap.getInitialPrice, chain type not found")
 		return errorSentinel.ErrFetcherNoMatchingChainID
@@ -164,50 +276,137 @@ func (f *UniswapFetcher) readSwapEvent(ctx context.Context, feed common.Feed, de
 		if err != nil {
 			continue
 		}
-		sqrtPrice := res[2]
-		price, err := getTokenPrice(sqrtPrice.(*big.Int), definition)
+		sqrtPrice, ok := res[2].(*big.Int)
+		if !ok {
+			continue
+		}
+		liquidity, ok := res[3].(*big.Int)
+		if !ok {
+			continue
+		}
+
+		feedData, err := f.toFeedData(ctx, feed, definition, chainType, sqrtPrice, liquidity)
 		if err != nil {
+			if errors.Is(err, errorSentinel.ErrFetcherInsufficientDexLiquidity) {
+				log.Debug().Str("Player", "Uniswap").Str("feed", feed.Name).Str("liquidity", liquidity.String()).Msg("swap skipped, pool liquidity below minimum")
+				continue
+			}
 			log.Error().Str("Player", "Uniswap").Err(err).Msg("error in uniswap.subscribeEvent, failed to get token price")
 			continue
 		}
-		now := time.Now()
-		feedData := &common.FeedData{
-			FeedID:    feed.ID,
-			Value:     *price,
-			Timestamp: &now,
-		}
 		log.Debug().Str("Player", "Uniswap").Any("feedData", feedData).Msg("price fetched")
 		f.FeedDataBuffer <- feedData
 	}
 	return nil
 }
 
-func getTokenPrice(sqrtPrice *big.Int, definition *common.DexFeedDefinition) (*float64, error) {
+func getTokenPrice(sqrtPrice *big.Int, definition *common.DexFeedDefinition) (*big.Int, error) {
 	decimal0 := definition.Token0Decimals
 	decimal1 := definition.Token1Decimals
 	if sqrtPrice == nil || decimal0 == 0 || decimal1 == 0 {
 		return nil, errorSentinel.ErrFetcherInvalidInput
 	}
 
-	sqrtPriceX96Float := new(big.Float).SetInt(sqrtPrice)
-	sqrtPriceX96Float.Quo(sqrtPriceX96Float, new(big.Float).SetFloat64(math.Pow(2, 96)))
-	sqrtPriceX96Float.Mul(sqrtPriceX96Float, sqrtPriceX96Float)
+	// (sqrtPriceX96 / 2^96)^2 as an exact fraction
+	squared := new(big.Int).Mul(sqrtPrice, sqrtPrice)
+	rawPrice := new(big.Rat).SetFrac(squared, new(big.Int).Lsh(big.NewInt(1), 192))
+
+	return adjustRawPrice(rawPrice, definition)
+}
+
+func getTokenPriceFromTick(tick int64, definition *common.DexFeedDefinition) (*big.Int, error) {
+	if definition.Token0Decimals == 0 || definition.Token1Decimals == 0 {
+		return nil, errorSentinel.ErrFetcherInvalidInput
+	}
+
+	// price of token0 in token1 raw units is 1.0001^tick
+	rawPrice, _ := tickRatio(tick).Rat(nil)
+	return adjustRawPrice(rawPrice, definition)
+}
+
+// tickRatio computes 1.0001^tick by squaring at TickRatioPrecision bits, an exact fraction of
+// 10001^tick / 10000^tick would grow to millions of digits for extreme ticks.
+// big.Float rounds the same way on every platform, unlike math.Pow.
+func tickRatio(tick int64) *big.Float {
+	base := new(big.Float).SetPrec(TickRatioPrecision).Quo(
+		new(big.Float).SetPrec(TickRatioPrecision).SetInt64(10001),
+		new(big.Float).SetPrec(TickRatioPrecision).SetInt64(10000),
+	)
+	result := new(big.Float).SetPrec(TickRatioPrecision).SetInt64(1)
+
+	exp := tick
+	if exp < 0 {
+		exp = -exp
+	}
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
 
-	decimalDiff := new(big.Float).SetFloat64(math.Pow(10, float64(decimal1-decimal0)))
+	if tick < 0 {
+		result.Quo(new(big.Float).SetPrec(TickRatioPrecision).SetInt64(1), result)
+	}
+	return result
+}
 
-	datum := sqrtPriceX96Float.Quo(sqrtPriceX96Float, decimalDiff)
+// scales token1/token0 raw price by token decimals, applies reciprocal and converts into DECIMALS precision
+func adjustRawPrice(rawPrice *big.Rat, definition *common.DexFeedDefinition) (*big.Int, error) {
+	datum := decimal.Scale(rawPrice, definition.Token0Decimals-definition.Token1Decimals)
 	if definition.Reciprocal != nil && *definition.Reciprocal {
-		if datum == nil || datum.Sign() == 0 {
+		if datum.Sign() == 0 {
 			return nil, errorSentinel.ErrFetcherDivisionByZero
 		}
-		datum = datum.Quo(new(big.Float).SetFloat64(1), datum)
+		datum = datum.Inv(datum)
+	}
+
+	return common.FormatDecimalPrice(datum), nil
+}
+
+// arithmetic mean tick over the window, rounded toward negative infinity like uniswap's OracleLibrary.consult
+func AverageTick(tickCumulatives []*big.Int, window uint32) (int64, error) {
+	if len(tickCumulatives) < 2 || window == 0 || tickCumulatives[0] == nil || tickCumulatives[1] == nil {
+		return 0, errorSentinel.ErrFetcherInvalidTwapObservation
+	}
+
+	delta := new(big.Int).Sub(tickCumulatives[1], tickCumulatives[0])
+	windowInt := big.NewInt(int64(window))
+
+	tick, remainder := new(big.Int).QuoRem(delta, windowInt, new(big.Int))
+	if delta.Sign() < 0 && remainder.Sign() != 0 {
+		tick.Sub(tick, big.NewInt(1))
+	}
+
+	if !tick.IsInt64() {
+		return 0, errorSentinel.ErrFetcherInvalidTwapObservation
+	}
+	return tick.Int64(), nil
+}
+
+// in-range virtual reserve of the base token, L / sqrtP for token0 and L * sqrtP for token1 when reciprocal
+func LiquidityWeight(liquidity *big.Int, sqrtPrice *big.Int, definition *common.DexFeedDefinition) (float64, error) {
+	if liquidity == nil || sqrtPrice == nil || sqrtPrice.Sign() == 0 {
+		return 0, errorSentinel.ErrFetcherInvalidInput
 	}
 
-	multiplier := new(big.Float).SetFloat64(math.Pow(10, common.DECIMALS))
-	datum.Mul(datum, multiplier)
+	q96 := new(big.Float).SetFloat64(math.Pow(2, 96))
+	sqrtPriceFloat := new(big.Float).Quo(new(big.Float).SetInt(sqrtPrice), q96)
+	reserve := new(big.Float).SetInt(liquidity)
 
-	result, _ := datum.Float64()
-	result = math.Round(result)
+	decimals := definition.Token0Decimals
+	if definition.Reciprocal != nil && *definition.Reciprocal {
+		reserve.Mul(reserve, sqrtPriceFloat)
+		decimals = definition.Token1Decimals
+	} else {
+		reserve.Quo(reserve, sqrtPriceFloat)
+	}
+	reserve.Quo(reserve, new(big.Float).SetFloat64(math.Pow10(decimals)))
+
+	result, _ := reserve.Float64()
+	return result, nil
+}
 
-	return &result, nil
+func isLiquidityWeighted(definition *common.DexFeedDefinition) bool {
+	return definition.LiquidityWeight != nil && *definition.LiquidityWeight
 }
@@ -0,0 +1,205 @@
+package uniswapv2
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"bisonai.com/miko/node/pkg/chain/utils"
+	"bisonai.com/miko/node/pkg/chain/websocketchainreader"
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/utils/decimal"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/rs/zerolog/log"
+)
+
+type UniswapV2Fetcher common.DexFetcher
+
+const (
+	GET_RESERVES = "function getReserves() external view returns (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)"
+	EVENT        = "event Sync(uint112 reserve0, uint112 reserve1)"
+)
+
+func New(opts ...common.DexFetcherOption) common.FetcherInterface {
+	config := &common.DexFetcherConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &UniswapV2Fetcher{
+		Feeds:                config.Feeds,
+		FeedDataBuffer:       config.FeedDataBuffer,
+		WebsocketChainReader: config.WebsocketChainReader,
+	}
+}
+
+func (f *UniswapV2Fetcher) Run(ctx context.Context) {
+	for _, feed := range f.Feeds {
+		go f.run(ctx, feed)
+		// sleep to avoid blockage from json rpc url rate limitation
+		time.Sleep(1 * time.Second)
+	}
+}
+
+func (f *UniswapV2Fetcher) run(ctx context.Context, feed common.Feed) {
+	definition := new(common.DexFeedDefinition)
+	err := json.Unmarshal(feed.Definition, &definition)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.run, failed to unmarshal definition")
+		return
+	}
+
+	// 1. get initial data once through getReserves call
+	price, err := f.getPriceThroughReservesCall(ctx, definition)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.run, failed to get initial price")
+		return
+	}
+
+	now := time.Now()
+	initialFeedData := &common.FeedData{
+		FeedID:    feed.ID,
+		Value:     price,
+		Timestamp: &now,
+	}
+	log.Debug().Str("Player", "UniswapV2").Any("feedData", initialFeedData).Msg("initial price fetched")
+	f.FeedDataBuffer <- initialFeedData
+
+	// 2. get subsequent data through Sync events
+	err = f.readSyncEvent(ctx, feed, definition)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.run, failed to subscribe event")
+		return
+	}
+}
+
+func (f *UniswapV2Fetcher) getPriceThroughReservesCall(ctx context.Context, definition *common.DexFeedDefinition) (*big.Int, error) {
+	chainType, ok := f.WebsocketChainReader.ChainType(definition.ChainId)
+	if !ok {
+		log.Error().Str("Player", "UniswapV2").Str("chainId", definition.ChainId).Msg("error in uniswapv2.getInitialPrice, chain type not found")
+		return nil, errorSentinel.ErrFetcherNoMatchingChainID
+	}
+
+	rawResult, err := f.WebsocketChainReader.ReadContractOnce(ctx, chainType, definition.Address, GET_RESERVES)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.getInitialPrice, failed to read contract")
+		return nil, err
+	}
+
+	rawResultSlice, ok := rawResult.([]interface{})
+	if !ok || len(rawResultSlice) < 2 {
+		log.Error().Str("Player", "UniswapV2").Msg("error in uniswapv2.getInitialPrice, failed to get slice result")
+		return nil, errorSentinel.ErrFetcherFailedToGetDexResultSlice
+	}
+
+	reserve0, ok := rawResultSlice[0].(*big.Int)
+	if !ok {
+		log.Error().Str("Player", "UniswapV2").Msg("error in uniswapv2.getInitialPrice, failed to convert reserve0")
+		return nil, errorSentinel.ErrFetcherFailedBigIntConvert
+	}
+
+	reserve1, ok := rawResultSlice[1].(*big.Int)
+	if !ok {
+		log.Error().Str("Player", "UniswapV2").Msg("error in uniswapv2.getInitialPrice, failed to convert reserve1")
+		return nil, errorSentinel.ErrFetcherFailedBigIntConvert
+	}
+
+	return GetTokenPrice(reserve0, reserve1, definition)
+}
+
+func (f *UniswapV2Fetcher) readSyncEvent(ctx context.Context, feed common.Feed, definition *common.DexFeedDefinition) error {
+	logChannel := make(chan types.Log)
+	address := definition.Address
+
+	chainType, ok := f.WebsocketChainReader.ChainType(definition.ChainId)
+	if !ok {
+		log.Error().Str("Player", "UniswapV2").Str("chainId", definition.ChainId).Msg("error in uniswapv2.readSyncEvent, chain type not found")
+		return errorSentinel.ErrFetcherNoMatchingChainID
+	}
+
+	eventName, input, _, err := utils.ParseMethodSignature(EVENT)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.readSyncEvent, failed to parse method signature")
+		return err
+	}
+
+	syncEventABI, err := utils.GenerateEventABI(eventName, input)
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.readSyncEvent, failed to generate event abi")
+		return err
+	}
+	syncEventID := syncEventABI.Events[eventName].ID
+
+	err = f.WebsocketChainReader.Subscribe(
+		ctx,
+		websocketchainreader.WithAddress(address),
+		websocketchainreader.WithChannel(logChannel),
+		websocketchainreader.WithChainType(chainType))
+	if err != nil {
+		log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.readSyncEvent, failed to subscribe")
+		return err
+	}
+
+	for eventLog := range logChannel {
+		// pair contracts also emit Swap, Mint, Burn and Transfer, only Sync carries the reserves
+		if len(eventLog.Topics) == 0 || eventLog.Topics[0] != syncEventID {
+			continue
+		}
+
+		res, err := syncEventABI.Unpack(eventName, eventLog.Data)
+		if err != nil || len(res) < 2 {
+			continue
+		}
+
+		reserve0, ok := res[0].(*big.Int)
+		if !ok {
+			continue
+		}
+		reserve1, ok := res[1].(*big.Int)
+		if !ok {
+			continue
+		}
+
+		price, err := GetTokenPrice(reserve0, reserve1, definition)
+		if err != nil {
+			log.Error().Str("Player", "UniswapV2").Err(err).Msg("error in uniswapv2.readSyncEvent, failed to get token price")
+			continue
+		}
+		now := time.Now()
+		feedData := &common.FeedData{
+			FeedID:    feed.ID,
+			Value:     price,
+			Timestamp: &now,
+		}
+		log.Debug().Str("Player", "UniswapV2").Any("feedData", feedData).Msg("price fetched")
+		f.FeedDataBuffer <- feedData
+	}
+	return nil
+}
+
+// price of token0 denominated in token1: (reserve1 / reserve0) / 10^(decimal1 - decimal0)
+func GetTokenPrice(reserve0 *big.Int, reserve1 *big.Int, definition *common.DexFeedDefinition) (*big.Int, error) {
+	decimal0 := definition.Token0Decimals
+	decimal1 := definition.Token1Decimals
+	if reserve0 == nil || reserve1 == nil || decimal0 == 0 || decimal1 == 0 {
+		return nil, errorSentinel.ErrFetcherInvalidInput
+	}
+
+	if reserve0.Sign() == 0 {
+		return nil, errorSentinel.ErrFetcherDivisionByZero
+	}
+
+	datum := new(big.Rat).SetFrac(reserve1, reserve0)
+	datum = decimal.Scale(datum, decimal0-decimal1)
+
+	if definition.Reciprocal != nil && *definition.Reciprocal {
+		if datum.Sign() == 0 {
+			return nil, errorSentinel.ErrFetcherDivisionByZero
+		}
+		datum = datum.Inv(datum)
+	}
+
+	return common.FormatDecimalPrice(datum), nil
+}
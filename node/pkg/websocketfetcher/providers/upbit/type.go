@@ -41,3 +41,15 @@ type Response struct {
 	StreamType         string   `json:"st"`
 	SequentialId       *int64   `json:"sid"`
 }
+
+type BookResponse struct {
+	Type      string `json:"ty"`
+	Code      string `json:"cd"`
+	Timestamp int64  `json:"tms"`
+	Units     []struct {
+		AskPrice float64 `json:"ap"`
+		BidPrice float64 `json:"bp"`
+		AskSize  float64 `json:"as"`
+		BidSize  float64 `json:"bs"`
+	} `json:"obu"`
+}
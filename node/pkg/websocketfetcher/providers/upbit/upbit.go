@@ -20,26 +20,42 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 
 	fetcher := &UpbitFetcher{}
 	fetcher.FeedMap = config.FeedMaps.Separated
+	fetcher.BookFeedMap = config.FeedMaps.SeparatedBooks
 	fetcher.FeedDataBuffer = config.FeedDataBuffer
+	fetcher.TradeVolumes = common.NewTradeVolumeMap(config.FeedMaps.VolumeWindows, fetcher.FeedMap, common.BookFeedIds(fetcher.BookFeedMap))
 
 	codes := []string{}
 	for feed := range fetcher.FeedMap {
-		splitted := strings.Split(feed, "-")
-		base := strings.ToUpper(splitted[0])
-		quote := strings.ToUpper(splitted[1])
-		codes = append(codes, quote+"-"+base)
+		codes = append(codes, toCode(feed))
+	}
+
+	bookCodes := []string{}
+	for feed := range fetcher.BookFeedMap {
+		bookCodes = append(bookCodes, toCode(feed))
 	}
 
 	subscription := Subscription{
 		map[string]string{"ticket": uuid.New().String()},
-		// map[string]interface{}{"type": "trade", "codes": codes, "isOnlyRealtime": true},
-		map[string]interface{}{"type": "ticker", "codes": codes, "isOnlyRealtime": true},
-		map[string]string{"format": "SIMPLE"},
 	}
+	if len(codes) > 0 {
+		subscription = append(subscription, map[string]interface{}{"type": "ticker", "codes": codes, "isOnlyRealtime": true})
+	}
+	tradeCodes := []string{}
+	for _, symbol := range fetcher.TradeVolumes.Symbols() {
+		tradeCodes = append(tradeCodes, toCode(symbol))
+	}
+	if len(tradeCodes) > 0 {
+		subscription = append(subscription, map[string]interface{}{"type": "trade", "codes": tradeCodes, "isOnlyRealtime": true})
+	}
+	if len(bookCodes) > 0 {
+		subscription = append(subscription, map[string]interface{}{"type": "orderbook", "codes": bookCodes, "isOnlyRealtime": true})
+	}
+	subscription = append(subscription, map[string]string{"format": "SIMPLE"})
 	ws, err := wss.NewWebsocketHelper(ctx,
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions([]interface{}{subscription}),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Upbit").Err(err).Msg("error in upbit.New")
 		return nil, err
@@ -55,19 +71,66 @@ func (f *UpbitFetcher) handleMessage(ctx context.Context, message map[string]int
 		log.Error().Str("Player", "Upbit").Err(err).Msg("error in upbit.handleMessage")
 		return err
 	}
+
+	if response.Type == "orderbook" {
+		return f.handleBookMessage(message)
+	}
+
+	if response.Type == "trade" {
+		f.TradeVolumes.AddTrade(toSymbol(response.Code), response.TradeVolume)
+		return nil
+	}
+
 	feedDataList, err := ResponseToFeedData(response, f.FeedMap)
 	if err != nil {
 		log.Error().Str("Player", "Upbit").Err(err).Msg("error in upbit.handleMessage")
 		return err
 	}
 
-	for _, feedData := range feedDataList {
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
+		f.FeedDataBuffer <- feedData
+	}
+
+	return nil
+}
+
+func (f *UpbitFetcher) handleBookMessage(message map[string]interface{}) error {
+	response, err := common.MessageToStruct[BookResponse](message)
+	if err != nil {
+		log.Error().Str("Player", "Upbit").Err(err).Msg("error in upbit.handleBookMessage")
+		return err
+	}
+
+	feedDataList, err := BookResponseToFeedData(response, f.BookFeedMap)
+	if err != nil {
+		log.Error().Str("Player", "Upbit").Err(err).Msg("error in upbit.handleBookMessage")
+		return err
+	}
+
+	for _, feedData := range f.TradeVolumes.Apply(feedDataList) {
 		f.FeedDataBuffer <- feedData
 	}
 
 	return nil
 }
 
+// upbit codes are "<quote>-<base>"
+func toCode(feed string) string {
+	splitted := strings.Split(feed, "-")
+	base := strings.ToUpper(splitted[0])
+	quote := strings.ToUpper(splitted[1])
+	return quote + "-" + base
+}
+
+// inverse of toCode, "<quote>-<base>" into the "<base>-<quote>" feed map key
+func toSymbol(code string) string {
+	splitted := strings.Split(code, "-")
+	if len(splitted) != 2 {
+		return code
+	}
+	return strings.ToUpper(splitted[1]) + "-" + strings.ToUpper(splitted[0])
+}
+
 func (f *UpbitFetcher) Run(ctx context.Context) {
 	f.Ws.Run(ctx, f.handleMessage)
 }
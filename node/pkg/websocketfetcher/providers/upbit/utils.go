@@ -11,7 +11,10 @@ import (
 func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.FeedData, error) {
 
 	timestamp := time.UnixMilli(data.TradeTimestamp)
-	price := common.FormatFloat64Price(data.TradePrice)
+	price, err := common.FormatFloat64Price(data.TradePrice)
+	if err != nil {
+		return nil, err
+	}
 
 	volume := data.AccTradeVolume24h
 
@@ -41,3 +44,23 @@ func ResponseToFeedData(data Response, feedMap map[string][]int32) ([]*common.Fe
 
 	return result, nil
 }
+
+func BookResponseToFeedData(data BookResponse, bookFeedMap map[string][]common.BookFeed) ([]*common.FeedData, error) {
+	splitted := strings.Split(data.Code, "-")
+	if len(splitted) != 2 {
+		return nil, fmt.Errorf("invalid code: %s", data.Code)
+	}
+
+	feeds, exists := bookFeedMap[strings.ToUpper(splitted[1])+"-"+strings.ToUpper(splitted[0])]
+	if !exists {
+		return nil, fmt.Errorf("book feed not found")
+	}
+
+	book := common.OrderBook{Timestamp: time.UnixMilli(data.Timestamp)}
+	for _, unit := range data.Units {
+		book.Bids = append(book.Bids, common.BookLevel{Price: unit.BidPrice, Size: unit.BidSize})
+		book.Asks = append(book.Asks, common.BookLevel{Price: unit.AskPrice, Size: unit.AskSize})
+	}
+
+	return common.BookToFeedData(book, feeds), nil
+}
@@ -17,7 +17,7 @@ func ResponseToFeedData(response Response, feedMap map[string][]int32) ([]*commo
 		return nil, fmt.Errorf("feed not found from xt for symbol: %s", symbol)
 	}
 	timestamp := time.UnixMilli(response.Data.Time)
-	value, err := common.PriceStringToFloat64(response.Data.Price)
+	value, err := common.PriceStringToValue(response.Data.Price)
 	if err != nil {
 		return nil, err
 	}
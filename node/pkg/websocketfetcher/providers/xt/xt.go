@@ -45,7 +45,9 @@ func New(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInter
 		wss.WithCompressionMode(),
 		wss.WithEndpoint(URL),
 		wss.WithSubscriptions(subscriptions),
-		wss.WithProxyUrl(config.Proxy))
+		wss.WithProxyUrl(config.Proxy),
+		wss.WithKeepalive(wss.Keepalive{Interval: 10 * time.Second, Message: "ping", Pong: "pong"}),
+		wss.WithOptions(config.ConnectionOptions...))
 	if err != nil {
 		log.Error().Str("Player", "Xt").Err(err).Msg("error in xt.New")
 		return nil, err
@@ -82,20 +84,9 @@ func (f *XtFetcher) handleMessage(ctx context.Context, message map[string]any) e
 }
 
 func (f *XtFetcher) Run(ctx context.Context) {
-	go f.ping(ctx)
 	f.Ws.Run(ctx, f.handleMessage)
 }
 
-func (f *XtFetcher) ping(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Second)
-	for range ticker.C {
-		err := f.Ws.RawWrite(ctx, "ping")
-		if err != nil {
-			log.Error().Str("Player", "Xt").Err(err).Msg("error in xt.ping")
-		}
-	}
-}
-
 func (f *XtFetcher) customReadFunc(ctx context.Context, conn *websocket.Conn) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	_, data, err := conn.Read(ctx)
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/binance"
+	"github.com/stretchr/testify/assert"
+)
+
+var testBook = common.OrderBook{
+	Bids: []common.BookLevel{{Price: 99, Size: 1}, {Price: 98, Size: 2}},
+	Asks: []common.BookLevel{{Price: 101, Size: 1}, {Price: 102, Size: 2}},
+}
+
+func TestMidPriceAndSpread(t *testing.T) {
+	mid, err := common.MidPrice(testBook)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "100", mid.RatString())
+
+	spread, err := common.Spread(testBook)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 0.02, spread)
+
+	_, err = common.MidPrice(common.OrderBook{})
+	assert.Error(t, err)
+}
+
+func TestDepthWeightedPrice(t *testing.T) {
+	// selling 197 quote fills 1 @ 99 and 1 @ 98, buying 203 fills 1 @ 101 and 1 @ 102
+	price, err := common.DepthWeightedPrice(testBook, 197)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// (197/2 + 197/(1+96/102)) / 2, exactly
+	assert.Equal(t, "13199/132", price.RatString())
+
+	_, err = common.DepthWeightedPrice(testBook, 1000)
+	assert.Error(t, err)
+}
+
+func TestBookToFeedData(t *testing.T) {
+	feeds := []common.BookFeed{
+		{ID: 1, PriceSource: common.MidPriceSource},
+		{ID: 2, PriceSource: common.MidPriceSource, MaxSpread: 0.01},
+		{ID: 3, PriceSource: common.DepthPriceSource, DepthNotional: 50},
+	}
+
+	feedDataList := common.BookToFeedData(testBook, feeds)
+	assert.Len(t, feedDataList, 2)
+	assert.Equal(t, int32(1), feedDataList[0].FeedID)
+	assert.Equal(t, big.NewInt(10000000000), common.RescalePrice(feedDataList[0].Value, 8))
+	assert.Equal(t, 0.02, *feedDataList[0].Spread)
+	assert.Equal(t, int32(3), feedDataList[1].FeedID)
+	assert.Equal(t, big.NewInt(10000000000), common.RescalePrice(feedDataList[1].Value, 8))
+}
+
+func TestLocalBook(t *testing.T) {
+	book := common.NewLocalBook()
+	book.Update(testBook.Bids, testBook.Asks)
+	book.Update([]common.BookLevel{{Price: 99, Size: 0}, {Price: 97, Size: 3}}, []common.BookLevel{{Price: 100.5, Size: 1}})
+	book.Trim(2)
+
+	snapshot := book.Snapshot(0, time.Now())
+	assert.Equal(t, []common.BookLevel{{Price: 98, Size: 2}, {Price: 97, Size: 3}}, snapshot.Bids)
+	assert.Equal(t, []common.BookLevel{{Price: 100.5, Size: 1}, {Price: 101, Size: 1}}, snapshot.Asks)
+}
+
+func TestGetWssFeedMapBookFeeds(t *testing.T) {
+	feeds := []common.Feed{
+		{ID: 1, Name: "binance-wss-BTC-USDT", Definition: json.RawMessage(`{"type": "wss", "provider": "binance", "base": "btc", "quote": "usdt"}`)},
+		{ID: 2, Name: "binance-wss-BTC-USDT-mid", Definition: json.RawMessage(`{"type": "wss", "provider": "binance", "base": "btc", "quote": "usdt", "priceSource": "mid", "maxSpread": 0.005}`)},
+	}
+
+	feedMaps := common.GetWssFeedMap(feeds)
+	assert.Equal(t, []int32{1}, feedMaps["binance"].Combined["BTCUSDT"])
+	assert.Equal(t, []common.BookFeed{{ID: 2, PriceSource: common.MidPriceSource, MaxSpread: 0.005}}, feedMaps["binance"].CombinedBooks["BTCUSDT"])
+}
+
+func TestBinanceDepthToFeedData(t *testing.T) {
+	var message map[string]any
+	err := json.Unmarshal([]byte(`{"stream":"btcusdt@depth20@100ms","data":{"lastUpdateId":1,"bids":[["99.0","1.0"]],"asks":[["101.0","1.0"]]}}`), &message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	depth, err := common.MessageToStruct[binance.DepthStream](message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	feedDataList, err := binance.DepthToFeedData(depth, map[string][]common.BookFeed{"BTCUSDT": {{ID: 1, PriceSource: common.MidPriceSource}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, feedDataList, 1)
+	assert.Equal(t, big.NewInt(10000000000), common.RescalePrice(feedDataList[0].Value, 8))
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/uniswap"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/uniswapv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniswapV2GetTokenPrice(t *testing.T) {
+	t.Run("TestUniswapV2GetTokenPrice", func(t *testing.T) {
+		// 10 WETH (18 decimals) against 30,000 USDT (6 decimals)
+		reserve0, _ := new(big.Int).SetString("10000000000000000000", 10)
+		reserve1 := big.NewInt(30_000_000_000)
+		definition := &common.DexFeedDefinition{
+			Token0Decimals: 18,
+			Token1Decimals: 6,
+		}
+
+		price, err := uniswapv2.GetTokenPrice(reserve0, reserve1, definition)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, "3000000000000000000000", price.String())
+	})
+
+	t.Run("TestUniswapV2GetTokenPriceReciprocal", func(t *testing.T) {
+		reserve0, _ := new(big.Int).SetString("10000000000000000000", 10)
+		reserve1 := big.NewInt(30_000_000_000)
+		reciprocal := true
+		definition := &common.DexFeedDefinition{
+			Token0Decimals: 18,
+			Token1Decimals: 6,
+			Reciprocal:     &reciprocal,
+		}
+
+		price, err := uniswapv2.GetTokenPrice(reserve0, reserve1, definition)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, "333333333333333", price.String())
+	})
+
+	t.Run("TestUniswapV2GetTokenPriceRoundsHalfAwayFromZero", func(t *testing.T) {
+		// 3 / 2 * 10^-8, exactly half a unit once stored in 8 decimals, rounded only then
+		reserve0 := big.NewInt(200_000_000)
+		reserve1 := big.NewInt(3)
+		definition := &common.DexFeedDefinition{
+			Token0Decimals: 6,
+			Token1Decimals: 6,
+		}
+
+		price, err := uniswapv2.GetTokenPrice(reserve0, reserve1, definition)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, big.NewInt(15_000_000_000), price)
+		assert.Equal(t, big.NewInt(2), common.RescalePrice(price, 8))
+	})
+
+	t.Run("TestUniswapV2GetTokenPriceEmptyReserve", func(t *testing.T) {
+		definition := &common.DexFeedDefinition{
+			Token0Decimals: 18,
+			Token1Decimals: 6,
+		}
+
+		_, err := uniswapv2.GetTokenPrice(big.NewInt(0), big.NewInt(100), definition)
+		assert.Error(t, err)
+	})
+}
+
+func TestUniswapAverageTick(t *testing.T) {
+	t.Run("TestUniswapAverageTickPositive", func(t *testing.T) {
+		tick, err := uniswap.AverageTick([]*big.Int{big.NewInt(1000), big.NewInt(61000)}, 60)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, int64(1000), tick)
+	})
+
+	t.Run("TestUniswapAverageTickRoundsDown", func(t *testing.T) {
+		tick, err := uniswap.AverageTick([]*big.Int{big.NewInt(0), big.NewInt(-601)}, 60)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, int64(-11), tick)
+	})
+
+	t.Run("TestUniswapAverageTickInvalid", func(t *testing.T) {
+		_, err := uniswap.AverageTick([]*big.Int{big.NewInt(0)}, 60)
+		assert.Error(t, err)
+
+		_, err = uniswap.AverageTick([]*big.Int{big.NewInt(0), big.NewInt(10)}, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestUniswapLiquidityWeight(t *testing.T) {
+	// sqrtPriceX96 of 2^96 means a raw price of 1, so virtual reserves equal liquidity
+	sqrtPrice := new(big.Int).Lsh(big.NewInt(1), 96)
+	liquidity, _ := new(big.Int).SetString("5000000000000000000", 10)
+	definition := &common.DexFeedDefinition{
+		Token0Decimals: 18,
+		Token1Decimals: 18,
+	}
+
+	weight, err := uniswap.LiquidityWeight(liquidity, sqrtPrice, definition)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, float64(5), weight)
+
+	_, err = uniswap.LiquidityWeight(nil, sqrtPrice, definition)
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/generic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericFormatSymbol(t *testing.T) {
+	symbol, err := generic.FormatSymbol("{base}_{quote}", "BTC-USDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "btc_usdt", symbol)
+
+	symbol, err = generic.FormatSymbol(generic.DefaultSymbolFormat, "BTC-USDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "BTCUSDT", symbol)
+
+	_, err = generic.FormatSymbol(generic.DefaultSymbolFormat, "BTCUSDT")
+	assert.Error(t, err)
+}
+
+func TestGenericBuildSubscriptions(t *testing.T) {
+	t.Run("TestGenericBuildSubscriptionsCombined", func(t *testing.T) {
+		subscriptions, err := generic.BuildSubscriptions(json.RawMessage(`{"op":"subscribe","args":"{{symbols}}"}`), []string{"ETHUSDT", "BTCUSDT"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, []any{json.RawMessage(`{"op":"subscribe","args":["BTCUSDT","ETHUSDT"]}`)}, subscriptions)
+	})
+
+	t.Run("TestGenericBuildSubscriptionsPerSymbol", func(t *testing.T) {
+		subscriptions, err := generic.BuildSubscriptions(json.RawMessage(`{"channel":"ticker.{{symbol}}"}`), []string{"ETHUSDT", "BTCUSDT"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, []any{
+			json.RawMessage(`{"channel":"ticker.BTCUSDT"}`),
+			json.RawMessage(`{"channel":"ticker.ETHUSDT"}`),
+		}, subscriptions)
+	})
+}
+
+func TestGenericResponseToFeedData(t *testing.T) {
+	config, err := generic.ParseConfig(json.RawMessage(`{
+		"endpoint": "wss://stream.example.com/ws",
+		"symbolFormat": "{BASE}-{QUOTE}",
+		"dataPath": "data",
+		"symbolPath": "s",
+		"pricePath": "c",
+		"volumePath": "v",
+		"timestampPath": "t"
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	symbolMap, err := generic.GetSymbolMap(config.SymbolFormat, map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2, 3}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var message map[string]any
+	err = json.Unmarshal([]byte(`{"data":[{"s":"btc-usdt","c":"65000.5","v":12.5,"t":1718000000000},{"s":"ETH-USDT","c":3500,"v":"100","t":1718000000000}]}`), &message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	feedDataList, err := generic.ResponseToFeedData(message, config, symbolMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, feedDataList, 3)
+	assert.Equal(t, int32(1), feedDataList[0].FeedID)
+	assert.Equal(t, big.NewInt(6500050000000), common.RescalePrice(feedDataList[0].Value, 8))
+	assert.Equal(t, float64(12.5), feedDataList[0].Volume)
+	assert.Equal(t, int64(1718000000000), feedDataList[0].Timestamp.UnixMilli())
+	assert.Equal(t, big.NewInt(350000000000), common.RescalePrice(feedDataList[2].Value, 8))
+
+	var ack map[string]any
+	err = json.Unmarshal([]byte(`{"event":"subscribed"}`), &ack)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	feedDataList, err = generic.ResponseToFeedData(ack, config, symbolMap)
+	assert.NoError(t, err)
+	assert.Empty(t, feedDataList)
+
+	// a message holding a single ticker, numbers sent as strings
+	config.DataPath = ""
+	config.TimestampUnit = "s"
+	var ticker map[string]any
+	err = json.Unmarshal([]byte(`{"s":"BTC-USDT","c":0.00000001,"t":"1718000000"}`), &ticker)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	feedDataList, err = generic.ResponseToFeedData(ticker, config, symbolMap)
+	assert.NoError(t, err)
+	assert.Len(t, feedDataList, 1)
+	assert.Equal(t, big.NewInt(1), common.RescalePrice(feedDataList[0].Value, 8))
+	assert.Zero(t, feedDataList[0].Volume)
+	assert.Equal(t, int64(1718000000), feedDataList[0].Timestamp.Unix())
+}
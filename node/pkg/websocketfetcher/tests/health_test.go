@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRegistry(t *testing.T) {
+	registry := websocketfetcher.NewHealthRegistry(50 * time.Millisecond)
+	registry.SetProviderFeeds(map[string][]int32{"binance": {2, 1}, "uniswap": {3}})
+
+	observer := registry.Observer("binance")
+	observer.OnConnect()
+	observer.OnMessage()
+	observer.OnError(errors.New("unexpected message"))
+	observer.OnDisconnect()
+	observer.OnConnect()
+
+	now := time.Now()
+	registry.Record([]*common.FeedData{{FeedID: 1, Value: big.NewInt(100), Timestamp: &now}, {FeedID: 99, Value: big.NewInt(1), Timestamp: &now}})
+
+	snapshot := registry.Snapshot()
+	assert.Len(t, snapshot, 2)
+
+	binance := snapshot[0]
+	assert.Equal(t, "binance", binance.Name)
+	assert.Equal(t, websocketfetcher.ConnectionStateConnected, binance.State)
+	assert.Equal(t, 1, binance.Connections)
+	assert.Equal(t, 1, binance.Reconnects)
+	assert.Equal(t, 1, binance.ParseErrors)
+	assert.NotNil(t, binance.LastMessage)
+	assert.Len(t, binance.Feeds, 2)
+	assert.Equal(t, int32(1), binance.Feeds[0].ID)
+	assert.Equal(t, big.NewInt(100), binance.Feeds[0].LastPrice)
+	assert.Nil(t, binance.Feeds[1].LastUpdate)
+
+	assert.Equal(t, "uniswap", snapshot[1].Name)
+	assert.Equal(t, websocketfetcher.ConnectionStateUnknown, snapshot[1].State)
+
+	assert.False(t, registry.IsStale(1))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, registry.IsStale(1))
+	// feeds without any data yet and feeds of other fetchers are left to the aggregator
+	assert.False(t, registry.IsStale(2))
+	assert.False(t, registry.IsStale(99))
+
+	// providers dropped on refresh ignore late events
+	registry.SetProviderFeeds(map[string][]int32{"uniswap": {3}})
+	observer.OnDisconnect()
+	snapshot = registry.Snapshot()
+	assert.Len(t, snapshot, 1)
+	assert.Equal(t, "uniswap", snapshot[0].Name)
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/binance"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/mexc"
+	"bisonai.com/miko/node/pkg/wss"
+	"github.com/stretchr/testify/assert"
+)
+
+// golden file recorded through wss.WithRecordFile, replayed offline
+func TestBinanceReplay(t *testing.T) {
+	replayServer, err := wss.NewReplayServer("testdata/binance.jsonl", 0)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buffer := make(chan *common.FeedData, 10)
+	fetcher, err := binance.New(ctx,
+		common.WithFeedDataBuffer(buffer),
+		common.WithFeedMaps(common.FeedMaps{Combined: map[string][]int32{"BTCUSDT": {1}, "ETHUSDT": {2}}}),
+		common.WithConnectionOptions(wss.WithCustomDialFunc(replayServer.DialFunc)))
+	assert.NoError(t, err)
+	go fetcher.Run(ctx)
+
+	expected := map[int32]int64{1: 6750012000000, 2: 378055000000}
+	for range expected {
+		select {
+		case feedData := <-buffer:
+			assert.Equal(t, big.NewInt(expected[feedData.FeedID]), common.RescalePrice(feedData.Value, 8))
+			assert.NotNil(t, feedData.Timestamp)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for replayed feed data")
+		}
+	}
+}
+
+// protobuf push frames are stored base64 encoded, acks and pongs as text
+func TestMexcReplay(t *testing.T) {
+	replayServer, err := wss.NewReplayServer("testdata/mexc.jsonl", 0)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buffer := make(chan *common.FeedData, 10)
+	fetcher, err := mexc.New(ctx,
+		common.WithFeedDataBuffer(buffer),
+		common.WithFeedMaps(common.FeedMaps{Combined: map[string][]int32{"BTCUSDT": {1}, "ETHUSDT": {2}}}),
+		common.WithConnectionOptions(wss.WithCustomDialFunc(replayServer.DialFunc)))
+	assert.NoError(t, err)
+	go fetcher.Run(ctx)
+
+	expected := map[int32]struct {
+		value     int64
+		volume    float64
+		timestamp int64
+	}{
+		1: {value: 6750012000000, volume: 1520.5, timestamp: 1717200000500},
+		2: {value: 378055000000, volume: 25000, timestamp: 1717200001000},
+	}
+	for range expected {
+		select {
+		case feedData := <-buffer:
+			assert.Equal(t, big.NewInt(expected[feedData.FeedID].value), common.RescalePrice(feedData.Value, 8))
+			assert.Equal(t, expected[feedData.FeedID].volume, feedData.Volume)
+			assert.Equal(t, expected[feedData.FeedID].timestamp, feedData.Timestamp.UnixMilli())
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for replayed feed data")
+		}
+	}
+}
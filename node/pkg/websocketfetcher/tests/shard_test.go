@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/websocketfetcher"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/wss"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockShardFetcher struct {
+	feedMaps   common.FeedMaps
+	subscribed []common.FeedMaps
+}
+
+func (m *mockShardFetcher) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func (m *mockShardFetcher) Subscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	m.subscribed = append(m.subscribed, feedMaps)
+	return nil
+}
+
+func (m *mockShardFetcher) Unsubscribe(ctx context.Context, feedMaps common.FeedMaps) error {
+	return nil
+}
+
+func TestShardFeedMaps(t *testing.T) {
+	feedMaps := common.FeedMaps{
+		Combined:       map[string][]int32{"BTCUSDT": {1}, "ETHUSDT": {2}, "SOLUSDT": {3}},
+		Separated:      map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2}, "SOL-USDT": {3}},
+		SeparatedBooks: map[string][]common.BookFeed{"BTC-USDT": {{ID: 4, PriceSource: common.MidPriceSource}}},
+		CombinedBooks:  map[string][]common.BookFeed{"BTCUSDT": {{ID: 4, PriceSource: common.MidPriceSource}}},
+		VolumeWindows:  map[int32]time.Duration{3: time.Minute},
+	}
+
+	shards := common.ShardFeedMaps(feedMaps, 2)
+	assert.Len(t, shards, 3)
+	assert.Equal(t, map[string][]int32{"BTCUSDT": {1}}, shards[0].Combined)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {1}}, shards[0].Separated)
+	assert.Len(t, shards[0].SeparatedBooks["BTC-USDT"], 1)
+	assert.Equal(t, map[string][]int32{"ETH-USDT": {2}}, shards[1].Separated)
+	assert.Equal(t, map[string][]int32{"SOL-USDT": {3}}, shards[2].Separated)
+	assert.Equal(t, map[int32]time.Duration{3: time.Minute}, shards[2].VolumeWindows)
+
+	assert.Len(t, common.ShardFeedMaps(feedMaps, 10), 1)
+	assert.Len(t, common.ShardFeedMaps(feedMaps, 0), 1)
+}
+
+func TestShardedFetcher(t *testing.T) {
+	created := []*mockShardFetcher{}
+	factory := func(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInterface, error) {
+		config := &common.FetcherConfig{}
+		for _, opt := range opts {
+			opt(config)
+		}
+		fetcher := &mockShardFetcher{feedMaps: config.FeedMaps}
+		created = append(created, fetcher)
+		return fetcher, nil
+	}
+
+	feedMaps := common.FeedMaps{
+		Combined:  map[string][]int32{"BTCUSDT": {1}, "ETHUSDT": {2}, "SOLUSDT": {3}},
+		Separated: map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2}, "SOL-USDT": {3}},
+	}
+
+	ctx := context.Background()
+	fetcher, err := common.NewShardedFactory("test", factory, 2)(ctx, common.WithFeedMaps(feedMaps))
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+
+	sharded := fetcher.(*common.ShardedFetcher)
+	status := sharded.Status()
+	assert.Len(t, status, 2)
+	assert.Equal(t, []string{"BTC-USDT", "ETH-USDT"}, status[0].Symbols)
+	assert.Equal(t, 1, status[1].Streams)
+
+	// existing symbols stay on their shard, new symbols fill spare capacity
+	err = fetcher.Subscribe(ctx, common.FeedMaps{
+		Combined:  map[string][]int32{"BTCUSDT": {5}, "XRPUSDT": {6}},
+		Separated: map[string][]int32{"BTC-USDT": {5}, "XRP-USDT": {6}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {5}}, created[0].subscribed[0].Separated)
+	assert.Equal(t, map[string][]int32{"XRP-USDT": {6}}, created[1].subscribed[0].Separated)
+	assert.Equal(t, 2, sharded.Status()[1].Streams)
+
+	err = fetcher.Subscribe(ctx, common.FeedMaps{
+		Combined:  map[string][]int32{"ADAUSDT": {7}},
+		Separated: map[string][]int32{"ADA-USDT": {7}},
+	})
+	assert.ErrorIs(t, err, errorSentinel.ErrFetcherSubscriptionNotSupported)
+}
+
+func TestShardedFetcherObservers(t *testing.T) {
+	registry := websocketfetcher.NewHealthRegistry(time.Minute)
+	registry.SetProviderFeeds(map[string][]int32{"test": {1, 2, 3}})
+
+	observers := []wss.Observer{}
+	factory := func(ctx context.Context, opts ...common.FetcherOption) (common.FetcherInterface, error) {
+		config := &common.FetcherConfig{}
+		for _, opt := range opts {
+			opt(config)
+		}
+		connectionConfig := &wss.ConnectionConfig{}
+		for _, opt := range config.ConnectionOptions {
+			opt(connectionConfig)
+		}
+		observers = append(observers, connectionConfig.Observer)
+		return &mockShardFetcher{feedMaps: config.FeedMaps}, nil
+	}
+
+	feedMaps := common.FeedMaps{
+		Combined:  map[string][]int32{"BTCUSDT": {1}, "ETHUSDT": {2}, "SOLUSDT": {3}},
+		Separated: map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2}, "SOL-USDT": {3}},
+	}
+	_, err := common.NewShardedFactory("test", factory, 2)(
+		context.Background(),
+		common.WithFeedMaps(feedMaps),
+		common.WithConnectionOptions(wss.WithObserver(registry.Observer("test"))),
+		common.WithShardObserver(func(shard int) wss.Observer { return registry.ShardObserver("test", shard) }),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, observers, 2)
+
+	observers[0].OnConnect()
+	observers[1].OnConnect()
+	observers[1].OnDisconnect()
+
+	snapshot := registry.Snapshot()
+	assert.Len(t, snapshot, 1)
+	assert.Equal(t, websocketfetcher.ConnectionStateConnected, snapshot[0].State)
+	assert.Equal(t, 1, snapshot[0].Connections)
+	assert.Len(t, snapshot[0].Shards, 2)
+	assert.Equal(t, websocketfetcher.ConnectionStateConnected, snapshot[0].Shards[0].State)
+	assert.Equal(t, websocketfetcher.ConnectionStateDisconnected, snapshot[0].Shards[1].State)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errorSentinel "bisonai.com/miko/node/pkg/error"
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/gateio"
+	"bisonai.com/miko/node/pkg/websocketfetcher/providers/okx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFeedMaps(t *testing.T) {
+	current := common.FeedMaps{
+		Separated:     map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2, 3}},
+		VolumeWindows: map[int32]time.Duration{1: time.Minute},
+	}
+	next := common.FeedMaps{
+		Separated:     map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2}, "SOL-USDT": {4}},
+		VolumeWindows: map[int32]time.Duration{1: time.Minute},
+	}
+
+	added := common.DiffFeedMaps(next, current)
+	assert.Equal(t, map[string][]int32{"SOL-USDT": {4}}, added.Separated)
+	assert.False(t, added.HasBookOrVolumeFeeds())
+
+	removed := common.DiffFeedMaps(current, next)
+	assert.Equal(t, map[string][]int32{"ETH-USDT": {3}}, removed.Separated)
+	assert.False(t, removed.IsEmpty())
+
+	assert.True(t, common.DiffFeedMaps(next, next).IsEmpty())
+
+	next.VolumeWindows[1] = time.Hour
+	assert.True(t, common.DiffFeedMaps(next, current).HasBookOrVolumeFeeds())
+}
+
+func TestAddRemoveFeedIds(t *testing.T) {
+	feedMap := map[string][]int32{"BTC-USDT": {1}}
+
+	added, newSymbols := common.AddFeedIds(feedMap, map[string][]int32{"BTC-USDT": {2}, "ETH-USDT": {3}})
+	assert.Equal(t, []string{"ETH-USDT"}, newSymbols)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {1, 2}, "ETH-USDT": {3}}, added)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {1}}, feedMap)
+
+	removed, emptySymbols := common.RemoveFeedIds(added, map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {3}})
+	assert.Equal(t, []string{"ETH-USDT"}, emptySymbols)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {2}}, removed)
+}
+
+func TestOkxSubscribe(t *testing.T) {
+	ctx := context.Background()
+	fetcher, err := okx.New(ctx,
+		common.WithFeedDataBuffer(make(chan *common.FeedData, 10)),
+		common.WithFeedMaps(common.FeedMaps{Separated: map[string][]int32{"BTC-USDT": {1}}}))
+	assert.NoError(t, err)
+
+	err = fetcher.Subscribe(ctx, common.FeedMaps{Separated: map[string][]int32{"ETH-USDT": {2}}})
+	assert.NoError(t, err)
+
+	okxFetcher := fetcher.(*okx.OkxFetcher)
+	assert.Equal(t, map[string][]int32{"BTC-USDT": {1}, "ETH-USDT": {2}}, okxFetcher.FeedMap)
+	assert.Len(t, okxFetcher.Ws.Subscriptions, 1)
+	assert.Len(t, okxFetcher.Ws.Subscriptions[0].(okx.Subscription).Args, 2)
+
+	err = fetcher.Unsubscribe(ctx, common.FeedMaps{Separated: map[string][]int32{"BTC-USDT": {1}}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int32{"ETH-USDT": {2}}, okxFetcher.FeedMap)
+	assert.Len(t, okxFetcher.Ws.Subscriptions[0].(okx.Subscription).Args, 1)
+
+	err = fetcher.Subscribe(ctx, common.FeedMaps{VolumeWindows: map[int32]time.Duration{3: time.Minute}})
+	assert.ErrorIs(t, err, errorSentinel.ErrFetcherSubscriptionNotSupported)
+}
+
+func TestSubscribeNotSupported(t *testing.T) {
+	ctx := context.Background()
+	fetcher, err := gateio.New(ctx,
+		common.WithFeedDataBuffer(make(chan *common.FeedData, 10)),
+		common.WithFeedMaps(common.FeedMaps{Combined: map[string][]int32{"BTC_USDT": {1}}, Separated: map[string][]int32{"BTC-USDT": {1}}}))
+	assert.NoError(t, err)
+
+	err = fetcher.Subscribe(ctx, common.FeedMaps{Separated: map[string][]int32{"ETH-USDT": {2}}})
+	assert.ErrorIs(t, err, errorSentinel.ErrFetcherSubscriptionNotSupported)
+}
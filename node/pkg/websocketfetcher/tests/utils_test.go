@@ -2,6 +2,7 @@ package tests
 
 import (
 	"encoding/json"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -80,16 +81,12 @@ func TestGetWssFeedMap(t *testing.T) {
 	}
 }
 
-func TestPriceStringToFloat64(t *testing.T) {
-	price := "10000.123400"
-	expected := 1000012340000.0
-	result, err := common.PriceStringToFloat64(price)
+func TestPriceStringToValue(t *testing.T) {
+	result, err := common.PriceStringToValue("10000.123400")
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	if result != expected {
-		t.Errorf("expected %f, got %f", expected, result)
-	}
+	assert.Equal(t, "10000123400000000000000", result.String())
 }
 
 func TestMessageToStruct(t *testing.T) {
@@ -688,3 +685,31 @@ func TestMessageToStruct(t *testing.T) {
 
 	})
 }
+
+func TestPriceStringToValueRounding(t *testing.T) {
+	// prices are scaled exactly and only rounded into the decimals of the config, scaled in float64 0.000000015 would land just below the half
+	cases := map[string]int64{
+		"0.000000015":     2,
+		"1.000000005":     100000001,
+		"-0.000000025":    -3,
+		"64123.123456785": 6412312345679,
+	}
+	for price, expected := range cases {
+		result, err := common.PriceStringToValue(price)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assert.Equal(t, big.NewInt(expected), common.RescalePrice(result, 8), price)
+	}
+}
+
+func TestRescalePrice(t *testing.T) {
+	// halves round away from zero once the decimals of the config are known
+	price, _ := new(big.Int).SetString("64123123456785000000000", 10)
+	assert.Equal(t, big.NewInt(2), common.RescalePrice(big.NewInt(15_000_000_000), 8))
+	assert.Equal(t, big.NewInt(-3), common.RescalePrice(big.NewInt(-25_000_000_000), 8))
+	assert.Equal(t, big.NewInt(6412312345679), common.RescalePrice(price, 8))
+	assert.Equal(t, big.NewInt(64123123457), common.RescalePrice(price, 6))
+	assert.Equal(t, big.NewInt(64123123456785), common.RescalePrice(price, 9))
+	assert.Equal(t, price, common.RescalePrice(price, common.DECIMALS))
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"bisonai.com/miko/node/pkg/websocketfetcher/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingVolume(t *testing.T) {
+	now := time.Now()
+	volume := common.NewRollingVolume(time.Minute)
+
+	volume.Add(now.Add(-90*time.Second), 5)
+	volume.Add(now.Add(-30*time.Second), 1)
+	volume.Add(now.Add(-30*time.Second), 1)
+	volume.Add(now, 2)
+
+	assert.Equal(t, float64(4), volume.Volume(now))
+	assert.Equal(t, float64(2), volume.Volume(now.Add(45*time.Second)))
+}
+
+func TestTradeVolumeMap(t *testing.T) {
+	feedMap := map[string][]int32{"BTCUSDT": {1, 2}, "ETHUSDT": {3}}
+	tradeVolumes := common.NewTradeVolumeMap(map[int32]time.Duration{1: time.Minute}, feedMap)
+
+	assert.Equal(t, []string{"BTCUSDT"}, tradeVolumes.Symbols())
+	assert.True(t, tradeVolumes.Tracks("BTCUSDT"))
+	assert.False(t, tradeVolumes.Tracks("ETHUSDT"))
+
+	tradeVolumes.AddTrade("BTCUSDT", 0.5)
+	tradeVolumes.AddTrade("BTCUSDT", 0.25)
+
+	feedDataList := tradeVolumes.Apply([]*common.FeedData{
+		{FeedID: 1, Value: big.NewInt(6000000000000), Volume: 12345},
+		{FeedID: 2, Value: big.NewInt(6000000000000), Volume: 12345},
+	})
+	assert.Equal(t, 0.75, feedDataList[0].Volume)
+	assert.Equal(t, float64(12345), feedDataList[1].Volume)
+
+	var nilTradeVolumes *common.TradeVolumeMap
+	assert.Empty(t, nilTradeVolumes.Symbols())
+	nilTradeVolumes.AddTrade("BTCUSDT", 1)
+}
+
+func TestGetWssFeedMapVolumeWindows(t *testing.T) {
+	feeds := []common.Feed{
+		{ID: 1, Name: "okx-wss-BTC-USDT", Definition: json.RawMessage(`{"type": "wss", "provider": "okx", "base": "btc", "quote": "usdt", "volumeWindow": "5m"}`)},
+		{ID: 2, Name: "okx-wss-ETH-USDT", Definition: json.RawMessage(`{"type": "wss", "provider": "okx", "base": "eth", "quote": "usdt", "volumeWindow": "soon"}`)},
+	}
+
+	feedMaps := common.GetWssFeedMap(feeds)
+	assert.Equal(t, map[int32]time.Duration{1: 5 * time.Minute}, feedMaps["okx"].VolumeWindows)
+}
@@ -0,0 +1,72 @@
+package wss
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+	"nhooyr.io/websocket"
+)
+
+// Decoder turns a raw frame into a typed message, e.g. protobuf encoded binary frames.
+// Frames carrying no message, such as subscription acks, are skipped by returning ok false.
+type Decoder[T any] func(messageType websocket.MessageType, data []byte) (message T, ok bool, err error)
+
+// RunTyped is Run for payloads which don't decode into a json map, every frame is decoded and routed as T.
+// Frames failing to decode are logged and skipped without dropping the connection.
+func RunTyped[T any](ctx context.Context, ws *WebsocketHelper, decode Decoder[T], router func(context.Context, T) error) {
+	ws.run(ctx, func(ctx context.Context, conn *websocket.Conn) error {
+		messageType, data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		pong, consumed := ws.isRawPong(messageType, data)
+		if pong {
+			ws.markPong()
+		}
+		if consumed {
+			return nil
+		}
+
+		message, ok, err := decode(messageType, data)
+		if err != nil {
+			ws.Observer.OnError(err)
+			log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error decoding websocket message")
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+
+		go func() {
+			routerErr := router(ctx, message)
+			if routerErr != nil {
+				ws.Observer.OnError(routerErr)
+				log.Warn().Err(routerErr).Str("endpoint", ws.Endpoint).Msg("error processing websocket message")
+			}
+		}()
+		return nil
+	})
+}
+
+// isRawPong matches undecoded frames against the keepalive, consumed reports frames holding nothing but the pong
+func (ws *WebsocketHelper) isRawPong(messageType websocket.MessageType, data []byte) (pong bool, consumed bool) {
+	keepalive := ws.Keepalive
+	switch {
+	case keepalive == nil:
+		return false, false
+	case keepalive.Pong != "":
+		pong = messageType == websocket.MessageText && string(data) == keepalive.Pong
+		return pong, pong
+	case keepalive.IsPong != nil:
+		if messageType != websocket.MessageText {
+			return false, false
+		}
+		var message map[string]any
+		pong = json.Unmarshal(data, &message) == nil && keepalive.IsPong(message)
+		return pong, pong
+	default:
+		return true, false
+	}
+}
@@ -0,0 +1,59 @@
+//nolint:all
+package wss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"nhooyr.io/websocket"
+)
+
+func TestRunTyped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte(`{"code":0,"msg":"subscribed"}`))
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte("pong"))
+		_ = conn.Write(r.Context(), websocket.MessageBinary, []byte{0x01, 0x02})
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws, err := NewWebsocketHelper(ctx,
+		WithEndpoint("ws"+server.URL[len("http"):]),
+		WithKeepalive(Keepalive{Interval: time.Minute, Message: "ping", Pong: "pong"}))
+	assert.NoError(t, err)
+
+	decode := func(messageType websocket.MessageType, data []byte) ([]byte, bool, error) {
+		return data, messageType == websocket.MessageBinary, nil
+	}
+	received := make(chan []byte, 10)
+	go RunTyped(ctx, ws, decode, func(ctx context.Context, message []byte) error {
+		received <- message
+		return nil
+	})
+
+	select {
+	case message := <-received:
+		assert.Equal(t, []byte{0x01, 0x02}, message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for decoded message")
+	}
+
+	select {
+	case message := <-received:
+		t.Fatalf("unexpected message routed: %v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
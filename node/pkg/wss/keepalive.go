@@ -0,0 +1,123 @@
+package wss
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"nhooyr.io/websocket"
+)
+
+const DefaultKeepaliveMaxMisses = 2
+
+// Keepalive sends an application level ping every Interval and reconnects once MaxMisses
+// pings in a row went unanswered, so dead or silently stalled streams are detected
+// long before InactivityTimeout.
+type Keepalive struct {
+	Interval time.Duration
+	// written as raw text when it is a string, as json otherwise
+	Message any
+	// raw text reply such as "pong", consumed before the message reaches the router.
+	// With a custom read func the reader is expected to drop it, returning no data.
+	Pong string
+	// matches json replies, when neither Pong nor IsPong is set any inbound message counts as a pong
+	IsPong    func(map[string]any) bool
+	MaxMisses int
+}
+
+func WithKeepalive(keepalive Keepalive) ConnectionOption {
+	return func(c *ConnectionConfig) {
+		if keepalive.MaxMisses <= 0 {
+			keepalive.MaxMisses = DefaultKeepaliveMaxMisses
+		}
+		c.Keepalive = &keepalive
+	}
+}
+
+func (ws *WebsocketHelper) markPong() {
+	ws.lastPong.Store(time.Now().UnixNano())
+}
+
+func (ws *WebsocketHelper) isPong(data map[string]any) bool {
+	keepalive := ws.Keepalive
+	switch {
+	case keepalive == nil:
+		return false
+	case keepalive.IsPong != nil:
+		return data != nil && keepalive.IsPong(data)
+	case keepalive.Pong != "":
+		return ws.CustomReadFunc != nil && len(data) == 0
+	default:
+		return true
+	}
+}
+
+// keepaliveReader is the default reader extended with raw pong handling
+func (ws *WebsocketHelper) keepaliveReader(ctx context.Context, conn *websocket.Conn) (map[string]interface{}, error) {
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(data) == ws.Keepalive.Pong {
+		ws.markPong()
+		return nil, nil
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// keepalive pings conn until ctx is done, closing it when pongs stop so Run reconnects
+func (ws *WebsocketHelper) keepalive(ctx context.Context, conn *websocket.Conn) {
+	keepalive := ws.Keepalive
+	ticker := time.NewTicker(keepalive.Interval)
+	defer ticker.Stop()
+
+	// zero until the first ping is written, misses are only counted against pings which went out
+	var lastPing time.Time
+	misses := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !lastPing.IsZero() {
+				if ws.lastPong.Load() < lastPing.UnixNano() {
+					misses++
+				} else {
+					misses = 0
+				}
+			}
+
+			if misses >= keepalive.MaxMisses {
+				log.Warn().Str("endpoint", ws.Endpoint).Int("misses", misses).Msg("keepalive pong missed, closing websocket")
+				conn.Close(websocket.StatusGoingAway, "keepalive timeout")
+				return
+			}
+
+			sent := time.Now()
+			var err error
+			switch message := keepalive.Message.(type) {
+			case string:
+				err = conn.Write(ctx, websocket.MessageText, []byte(message))
+			default:
+				var encoded []byte
+				encoded, err = json.Marshal(message)
+				if err == nil {
+					err = conn.Write(ctx, websocket.MessageText, encoded)
+				}
+			}
+			if err != nil {
+				log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error sending keepalive ping")
+				continue
+			}
+			lastPing = sent
+		}
+	}
+}
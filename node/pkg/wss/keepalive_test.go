@@ -0,0 +1,90 @@
+//nolint:all
+package wss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"nhooyr.io/websocket"
+)
+
+func pingServer(answer bool, dials *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+		dials.Add(1)
+
+		for {
+			_, data, err := conn.Read(r.Context())
+			if err != nil {
+				return
+			}
+			if answer && string(data) == "ping" {
+				if err = conn.Write(r.Context(), websocket.MessageText, []byte("pong")); err != nil {
+					return
+				}
+			}
+		}
+	}))
+}
+
+func TestKeepalive(t *testing.T) {
+	// dialing waits a second before subscribing, so runs last long enough for a redial
+	keepalive := Keepalive{Interval: 50 * time.Millisecond, Message: "ping", Pong: "pong"}
+
+	t.Run("answered pings keep the connection", func(t *testing.T) {
+		var dials atomic.Int32
+		server := pingServer(true, &dials)
+		defer server.Close()
+
+		ws, err := NewWebsocketHelper(context.Background(), WithEndpoint("ws"+server.URL[len("http"):]), WithKeepalive(keepalive))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		ws.Run(ctx, func(context.Context, map[string]any) error { return nil })
+
+		assert.Equal(t, int32(1), dials.Load())
+	})
+
+	t.Run("a single allowed miss is only counted after a ping went out", func(t *testing.T) {
+		var dials atomic.Int32
+		server := pingServer(true, &dials)
+		defer server.Close()
+
+		strict := keepalive
+		strict.MaxMisses = 1
+		ws, err := NewWebsocketHelper(context.Background(), WithEndpoint("ws"+server.URL[len("http"):]), WithKeepalive(strict))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		ws.Run(ctx, func(context.Context, map[string]any) error { return nil })
+
+		assert.Equal(t, int32(1), dials.Load())
+	})
+
+	t.Run("missed pongs reconnect", func(t *testing.T) {
+		var dials atomic.Int32
+		server := pingServer(false, &dials)
+		defer server.Close()
+
+		ws, err := NewWebsocketHelper(context.Background(), WithEndpoint("ws"+server.URL[len("http"):]), WithKeepalive(keepalive))
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultKeepaliveMaxMisses, ws.Keepalive.MaxMisses)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		ws.Run(ctx, func(context.Context, map[string]any) error { return nil })
+
+		assert.Greater(t, dials.Load(), int32(1))
+	})
+}
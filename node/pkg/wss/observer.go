@@ -0,0 +1,24 @@
+package wss
+
+// Observer is notified of connection events, letting callers aggregate health across connections.
+// Calls come from the read loop, implementations should return quickly.
+type Observer interface {
+	OnConnect()
+	OnDisconnect()
+	OnMessage()
+	// OnError reports frames which could not be decoded or processed
+	OnError(err error)
+}
+
+type noopObserver struct{}
+
+func (noopObserver) OnConnect()    {}
+func (noopObserver) OnDisconnect() {}
+func (noopObserver) OnMessage()    {}
+func (noopObserver) OnError(error) {}
+
+func WithObserver(observer Observer) ConnectionOption {
+	return func(c *ConnectionConfig) {
+		c.Observer = observer
+	}
+}
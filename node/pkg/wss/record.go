@@ -0,0 +1,233 @@
+package wss
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"nhooyr.io/websocket"
+)
+
+// Frame is a single inbound websocket message, stored one json object per line.
+// Text frames are kept readable so recordings can be edited as golden files.
+type Frame struct {
+	Time   time.Time `json:"time"`
+	Text   string    `json:"text,omitempty"`
+	Binary []byte    `json:"binary,omitempty"`
+}
+
+type DialFunc func(context.Context, string, *websocket.DialOptions) (*websocket.Conn, *http.Response, error)
+
+func (f Frame) messageType() (websocket.MessageType, []byte) {
+	if f.Binary != nil {
+		return websocket.MessageBinary, f.Binary
+	}
+	return websocket.MessageText, []byte(f.Text)
+}
+
+func newFrame(messageType websocket.MessageType, data []byte) Frame {
+	if messageType == websocket.MessageBinary {
+		return Frame{Time: time.Now(), Binary: data}
+	}
+	return Frame{Time: time.Now(), Text: string(data)}
+}
+
+// ReadFrames loads a recording written through WithRecordFile
+func ReadFrames(path string) ([]Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	frames := []Frame{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+	return frames, scanner.Err()
+}
+
+type recorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (r *recorder) write(frame Frame) error {
+	encoded, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(encoded, '\n'))
+	return err
+}
+
+// recordingDialFunc dials the upstream endpoint and hands the helper a connection to a local relay,
+// so every frame is recorded as received no matter which read func the provider uses
+func recordingDialFunc(dialFunc DialFunc, path string, readLimit int64) DialFunc {
+	return func(ctx context.Context, endpoint string, opts *websocket.DialOptions) (*websocket.Conn, *http.Response, error) {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upstream, response, err := dialFunc(ctx, endpoint, opts)
+		if err != nil {
+			file.Close()
+			return nil, response, err
+		}
+		if readLimit > 0 {
+			upstream.SetReadLimit(readLimit)
+		}
+
+		rec := &recorder{file: file}
+		conn, err := serveLocal(ctx, func(ctx context.Context, local *websocket.Conn) {
+			defer file.Close()
+			defer upstream.Close(websocket.StatusNormalClosure, "")
+			relay(ctx, upstream, local, rec)
+		})
+		if err != nil {
+			file.Close()
+			upstream.Close(websocket.StatusNormalClosure, "")
+			return nil, nil, err
+		}
+		return conn, response, nil
+	}
+}
+
+func relay(ctx context.Context, upstream *websocket.Conn, local *websocket.Conn, rec *recorder) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// outbound subscriptions and pings are forwarded untouched
+	go func() {
+		defer cancel()
+		for {
+			messageType, data, err := local.Read(ctx)
+			if err != nil {
+				return
+			}
+			if err = upstream.Write(ctx, messageType, data); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		messageType, data, err := upstream.Read(ctx)
+		if err != nil {
+			local.Close(websocket.StatusGoingAway, "upstream closed")
+			return
+		}
+		if err = rec.write(newFrame(messageType, data)); err != nil {
+			log.Warn().Err(err).Msg("error recording websocket frame")
+		}
+		if err = local.Write(ctx, messageType, data); err != nil {
+			return
+		}
+	}
+}
+
+// serveLocal accepts a single websocket connection on a loopback port and returns the client side of it
+func serveLocal(ctx context.Context, handle func(context.Context, *websocket.Conn)) (*websocket.Conn, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{ReadHeaderTimeout: 10 * time.Second}
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			go server.Close()
+		}()
+
+		conn, acceptErr := websocket.Accept(w, r, nil)
+		if acceptErr != nil {
+			log.Warn().Err(acceptErr).Msg("error accepting local websocket connection")
+			return
+		}
+		conn.SetReadLimit(-1)
+		handle(context.WithoutCancel(ctx), conn)
+	})
+	go func() {
+		serveErr := server.Serve(listener)
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			log.Warn().Err(serveErr).Msg("local websocket server stopped")
+		}
+	}()
+
+	conn, _, err := websocket.Dial(ctx, "ws://"+listener.Addr().String(), nil)
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// ReplayServer serves a recorded session to every connection, with the original gaps between frames
+// divided by speed. A speed of zero or less sends frames back to back.
+type ReplayServer struct {
+	frames []Frame
+	speed  float64
+}
+
+func NewReplayServer(path string, speed float64) (*ReplayServer, error) {
+	frames, err := ReadFrames(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayServer{frames: frames, speed: speed}, nil
+}
+
+// DialFunc ignores the endpoint and connects to a local server replaying the session, pass it to WithCustomDialFunc
+func (s *ReplayServer) DialFunc(ctx context.Context, endpoint string, opts *websocket.DialOptions) (*websocket.Conn, *http.Response, error) {
+	conn, err := serveLocal(ctx, s.replay)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn.SetReadLimit(-1)
+	return conn, nil, nil
+}
+
+func (s *ReplayServer) replay(ctx context.Context, conn *websocket.Conn) {
+	// subscriptions are read and dropped, the recording already holds the subscribed streams
+	ctx = conn.CloseRead(ctx)
+
+	for i, frame := range s.frames {
+		if i > 0 && s.speed > 0 {
+			gap := time.Duration(float64(frame.Time.Sub(s.frames[i-1].Time)) / s.speed)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(gap):
+			}
+		}
+
+		messageType, data := frame.messageType()
+		if err := conn.Write(ctx, messageType, data); err != nil {
+			return
+		}
+	}
+
+	// keep the session open so the helper doesn't reconnect and replay from the start
+	<-ctx.Done()
+	conn.Close(websocket.StatusNormalClosure, "")
+}
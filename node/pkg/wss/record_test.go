@@ -0,0 +1,71 @@
+//nolint:all
+package wss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(echoHandler))
+	defer server.Close()
+	wsURL := "ws" + server.URL[len("http"):] + "/ws"
+	recordFile := filepath.Join(t.TempDir(), "session.jsonl")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the echo server answers every subscription, which is what gets recorded
+	subscriptions := []any{map[string]any{"seq": 1}, map[string]any{"seq": 2}}
+	recording, err := NewWebsocketHelper(ctx, WithEndpoint(wsURL), WithSubscriptions(subscriptions), WithRecordFile(recordFile))
+	assert.NoError(t, err)
+
+	received := make(chan map[string]any, 10)
+	router := func(ctx context.Context, message map[string]any) error {
+		received <- message
+		return nil
+	}
+	go recording.Run(ctx, router)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for live message")
+		}
+	}
+	cancel()
+
+	var frames []Frame
+	assert.Eventually(t, func() bool {
+		frames, err = ReadFrames(recordFile)
+		return err == nil && len(frames) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.JSONEq(t, `{"seq": 1}`, frames[0].Text)
+	assert.False(t, frames[0].Time.IsZero())
+
+	replayServer, err := NewReplayServer(recordFile, 0)
+	assert.NoError(t, err)
+
+	replayCtx, replayCancel := context.WithCancel(context.Background())
+	defer replayCancel()
+
+	replaying, err := NewWebsocketHelper(replayCtx, WithEndpoint("wss://offline.invalid"), WithSubscriptions(subscriptions), WithCustomDialFunc(replayServer.DialFunc))
+	assert.NoError(t, err)
+	go replaying.Run(replayCtx, router)
+
+	for i := 1; i <= 2; i++ {
+		select {
+		case message := <-received:
+			assert.Contains(t, []float64{1, 2}, message["seq"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for replayed message")
+		}
+	}
+}
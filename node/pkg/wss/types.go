@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -11,6 +13,7 @@ import (
 )
 
 type WebsocketHelper struct {
+	// Conn and IsRunning change while running, read them through Connection and Running
 	Conn              *websocket.Conn
 	Endpoint          string
 	Subscriptions     []any
@@ -23,7 +26,13 @@ type WebsocketHelper struct {
 	ReadLimit         int64
 	ReconnectInterval time.Duration
 	InactivityTimeout time.Duration
+	RecordFile        string
+	Keepalive         *Keepalive
+	Observer          Observer
 	lastMessageTime   time.Time
+	lastPong          atomic.Int64
+	subscriptionsMu   sync.Mutex
+	connMu            sync.RWMutex
 }
 
 type ConnectionConfig struct {
@@ -37,6 +46,9 @@ type ConnectionConfig struct {
 	ReadLimit         int64
 	ReconnectInterval time.Duration
 	InactivityTimeout time.Duration
+	RecordFile        string
+	Keepalive         *Keepalive
+	Observer          Observer
 }
 
 type ConnectionOption func(*ConnectionConfig)
@@ -107,6 +119,22 @@ func WithInactivityTimeout(duration time.Duration) ConnectionOption {
 	}
 }
 
+// WithRecordFile appends every inbound frame with its receive time to path, see NewReplayServer for playback
+func WithRecordFile(path string) ConnectionOption {
+	return func(c *ConnectionConfig) {
+		c.RecordFile = path
+	}
+}
+
+// WithOptions applies a list of options, letting callers extend the options a provider sets
+func WithOptions(opts ...ConnectionOption) ConnectionOption {
+	return func(c *ConnectionConfig) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
 func NewWebsocketHelper(ctx context.Context, opts ...ConnectionOption) (*WebsocketHelper, error) {
 	config := &ConnectionConfig{
 		ReconnectInterval: DefaultReconnectInterval,
@@ -133,6 +161,13 @@ func NewWebsocketHelper(ctx context.Context, opts ...ConnectionOption) (*Websock
 		RequestHeaders:    config.RequestHeaders,
 		ReconnectInterval: config.ReconnectInterval,
 		InactivityTimeout: config.InactivityTimeout,
+		RecordFile:        config.RecordFile,
+		Keepalive:         config.Keepalive,
+		Observer:          config.Observer,
+	}
+
+	if ws.Observer == nil {
+		ws.Observer = noopObserver{}
 	}
 
 	if config.DialFunc != nil {
@@ -16,9 +16,10 @@ import (
 
 func (ws *WebsocketHelper) Dial(ctx context.Context) error {
 	dialOption := &websocket.DialOptions{}
+	endpoint := ws.Endpoint
 	if ws.Proxy != "" {
-		if strings.HasPrefix(ws.Endpoint, "wss") {
-			ws.Endpoint = strings.Replace(ws.Endpoint, "wss", "ws", 1)
+		if strings.HasPrefix(endpoint, "wss") {
+			endpoint = strings.Replace(endpoint, "wss", "ws", 1)
 		}
 
 		proxyURL, err := url.Parse(ws.Proxy)
@@ -47,11 +48,14 @@ func (ws *WebsocketHelper) Dial(ctx context.Context) error {
 		dialOption.CompressionMode = websocket.CompressionContextTakeover
 	}
 
-	dialFunc := websocket.Dial
+	var dialFunc DialFunc = websocket.Dial
 	if ws.CustomDialFunc != nil {
 		dialFunc = *ws.CustomDialFunc
 	}
-	conn, _, err := dialFunc(ctx, ws.Endpoint, dialOption)
+	if ws.RecordFile != "" {
+		dialFunc = recordingDialFunc(dialFunc, ws.RecordFile, ws.ReadLimit)
+	}
+	conn, _, err := dialFunc(ctx, endpoint, dialOption)
 	if err != nil {
 		log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error opening websocket connection")
 		return err
@@ -61,23 +65,72 @@ func (ws *WebsocketHelper) Dial(ctx context.Context) error {
 		conn.SetReadLimit(ws.ReadLimit)
 	}
 
+	ws.connMu.Lock()
 	ws.Conn = conn
+	ws.connMu.Unlock()
 	return nil
 }
 
+// Connection returns the current connection, it is replaced on every reconnect
+func (ws *WebsocketHelper) Connection() *websocket.Conn {
+	ws.connMu.RLock()
+	defer ws.connMu.RUnlock()
+	return ws.Conn
+}
+
+// Running reports whether Run keeps the connection alive
+func (ws *WebsocketHelper) Running() bool {
+	ws.connMu.RLock()
+	defer ws.connMu.RUnlock()
+	return ws.IsRunning
+}
+
 func (ws *WebsocketHelper) Run(ctx context.Context, router func(context.Context, map[string]any) error) {
 	readFunc := defaultReader
 	if ws.CustomReadFunc != nil {
 		readFunc = *ws.CustomReadFunc
+	} else if ws.Keepalive != nil && ws.Keepalive.Pong != "" {
+		readFunc = ws.keepaliveReader
 	}
 
+	ws.run(ctx, func(ctx context.Context, conn *websocket.Conn) error {
+		data, err := readFunc(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if ws.isPong(data) {
+			ws.markPong()
+		}
+
+		if len(data) != 0 {
+			go func(context.Context, map[string]any) {
+				routerErr := router(ctx, data)
+				if routerErr != nil {
+					ws.Observer.OnError(routerErr)
+					log.Warn().Err(routerErr).Str("endpoint", ws.Endpoint).Msg("error processing websocket message")
+				}
+			}(ctx, data)
+		}
+		return nil
+	})
+}
+
+// run keeps the connection alive, calling read for every inbound frame until ctx is done.
+// A read error closes the connection and dials again.
+func (ws *WebsocketHelper) run(ctx context.Context, read func(context.Context, *websocket.Conn) error) {
+	ws.connMu.Lock()
 	if ws.IsRunning {
+		ws.connMu.Unlock()
 		log.Warn().Msg("websocket is already running")
 		return
 	}
 	ws.IsRunning = true
+	ws.connMu.Unlock()
 	defer func() {
+		ws.connMu.Lock()
 		ws.IsRunning = false
+		ws.connMu.Unlock()
 	}()
 
 	reconnectTicker := time.NewTicker(ws.ReconnectInterval)
@@ -88,16 +141,28 @@ func (ws *WebsocketHelper) Run(ctx context.Context, router func(context.Context,
 	for {
 		err := ws.dialAndSubscribe(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error dialing and subscribing to websocket")
 			time.Sleep(time.Second)
 			continue
 		}
+
+		ws.Observer.OnConnect()
+		conn := ws.Connection()
+		connCtx, stopKeepalive := context.WithCancel(ctx)
+		if ws.Keepalive != nil && ws.Keepalive.Interval > 0 {
+			go ws.keepalive(connCtx, conn)
+		}
 	innerLoop:
 		for {
 			select {
 			case <-ctx.Done():
 				log.Info().Str("endpoint", ws.Endpoint).Msg("context cancelled, stopping websocket")
+				stopKeepalive()
 				ws.Close()
+				ws.Observer.OnDisconnect()
 				return
 			case <-reconnectTicker.C:
 				log.Info().Str("endpoint", ws.Endpoint).Msg("reconnect interval exceeded during read, closing websocket")
@@ -109,7 +174,7 @@ func (ws *WebsocketHelper) Run(ctx context.Context, router func(context.Context,
 				}
 				inactivityTimer.Reset(ws.InactivityTimeout - time.Since(ws.lastMessageTime))
 			default:
-				data, err := readFunc(ctx, ws.Conn)
+				err := read(ctx, conn)
 				if err != nil {
 					if isErrorNormalClosure(err) {
 						break innerLoop
@@ -117,20 +182,13 @@ func (ws *WebsocketHelper) Run(ctx context.Context, router func(context.Context,
 					log.Error().Err(err).Str("endpoint", ws.Endpoint).Msg("error reading from websocket")
 					break innerLoop
 				}
-
 				ws.lastMessageTime = time.Now()
-
-				if len(data) != 0 {
-					go func(context.Context, map[string]any) {
-						routerErr := router(ctx, data)
-						if routerErr != nil {
-							log.Warn().Err(routerErr).Str("endpoint", ws.Endpoint).Msg("error processing websocket message")
-						}
-					}(ctx, data)
-				}
+				ws.Observer.OnMessage()
 			}
 		}
+		stopKeepalive()
 		ws.Close()
+		ws.Observer.OnDisconnect()
 	}
 }
 
@@ -140,16 +198,14 @@ func (ws *WebsocketHelper) dialAndSubscribe(ctx context.Context) error {
 	}
 
 	subscribeJob := func() error {
-		for _, subscription := range ws.Subscriptions {
-			switch casted := subscription.(type) {
-			case []byte:
-				if err := ws.RawWrite(ctx, string(casted)); err != nil {
-					return err
-				}
-			default:
-				if err := ws.Write(ctx, casted); err != nil {
-					return err
-				}
+		ws.subscriptionsMu.Lock()
+		subscriptions := ws.Subscriptions
+		ws.subscriptionsMu.Unlock()
+
+		conn := ws.Connection()
+		for _, subscription := range subscriptions {
+			if err := writeSubscription(ctx, conn, subscription); err != nil {
+				return err
 			}
 		}
 		return nil
@@ -171,8 +227,42 @@ func (ws *WebsocketHelper) dialAndSubscribe(ctx context.Context) error {
 	return nil
 }
 
+// UpdateSubscriptions sends incremental subscribe or unsubscribe messages over the live connection
+// and replaces the subscriptions replayed on reconnect, so the stream stays connected while feeds change.
+// When the connection is down the messages are skipped, the next dial subscribes with the new list.
+// Messages only go to the connection live when the list was replaced, a newer one subscribes with the new list already.
+func (ws *WebsocketHelper) UpdateSubscriptions(ctx context.Context, subscriptions []any, messages []any) error {
+	ws.subscriptionsMu.Lock()
+	ws.Subscriptions = subscriptions
+	ws.subscriptionsMu.Unlock()
+
+	ws.connMu.RLock()
+	running, conn := ws.IsRunning, ws.Conn
+	ws.connMu.RUnlock()
+	if !running || conn == nil {
+		return nil
+	}
+
+	for _, message := range messages {
+		if err := writeSubscription(ctx, conn, message); err != nil {
+			log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error sending subscription update, applied on reconnect")
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSubscription(ctx context.Context, conn *websocket.Conn, subscription any) error {
+	switch casted := subscription.(type) {
+	case []byte:
+		return conn.Write(ctx, websocket.MessageText, casted)
+	default:
+		return wsjson.Write(ctx, conn, casted)
+	}
+}
+
 func (ws *WebsocketHelper) Write(ctx context.Context, message interface{}) error {
-	err := wsjson.Write(ctx, ws.Conn, message)
+	err := wsjson.Write(ctx, ws.Connection(), message)
 	if err != nil {
 		return err
 	}
@@ -180,13 +270,14 @@ func (ws *WebsocketHelper) Write(ctx context.Context, message interface{}) error
 }
 
 func (ws *WebsocketHelper) RawWrite(ctx context.Context, message string) error {
-	return ws.Conn.Write(ctx, websocket.MessageText, []byte(message))
+	return ws.Connection().Write(ctx, websocket.MessageText, []byte(message))
 }
 
 func (ws *WebsocketHelper) Read(ctx context.Context, ch chan any) error {
+	conn := ws.Connection()
 	for {
 		var t any
-		err := wsjson.Read(ctx, ws.Conn, &t)
+		err := wsjson.Read(ctx, conn, &t)
 		if err != nil {
 			log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error reading from websocket")
 			return err
@@ -196,10 +287,11 @@ func (ws *WebsocketHelper) Read(ctx context.Context, ch chan any) error {
 }
 
 func (ws *WebsocketHelper) Close() error {
-	if ws.Conn == nil {
+	conn := ws.Connection()
+	if conn == nil {
 		return nil
 	}
-	err := ws.Conn.Close(websocket.StatusNormalClosure, "")
+	err := conn.Close(websocket.StatusNormalClosure, "")
 	if err != nil {
 		log.Warn().Err(err).Str("endpoint", ws.Endpoint).Msg("error closing websocket")
 		return err
@@ -208,12 +300,13 @@ func (ws *WebsocketHelper) Close() error {
 }
 
 func (ws *WebsocketHelper) IsAlive(ctx context.Context) error {
-	if ws.Conn == nil {
+	conn := ws.Connection()
+	if conn == nil {
 		return fmt.Errorf("websocket is not running")
 	}
-	ctx = ws.Conn.CloseRead(ctx)
+	ctx = conn.CloseRead(ctx)
 
-	err := ws.Conn.Ping(ctx)
+	err := conn.Ping(ctx)
 	if err != nil {
 		log.Error().Err(err).Str("endpoint", ws.Endpoint).Msg("error pinging websocket")
 		return err
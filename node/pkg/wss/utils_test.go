@@ -3,8 +3,10 @@ package wss
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -91,8 +93,8 @@ func TestReconnectTicker(t *testing.T) {
 	time.Sleep(500 * time.Millisecond)
 
 	// Test if the connection has been re-established
-	assert.NotNil(t, conn.Conn) // Assuming that `conn.Conn` should not be nil
-	assert.True(t, conn.IsRunning)
+	assert.NotNil(t, conn.Connection()) // Assuming that the connection should not be nil
+	assert.True(t, conn.Running())
 
 	// Close the WebSocket helper
 	err = conn.Close()
@@ -123,10 +125,87 @@ func TestInactivityTimer(t *testing.T) {
 	time.Sleep(500 * time.Millisecond)
 
 	// Verify if the connection has been closed due to inactivity
-	assert.NotNil(t, conn.Conn) // Assuming that `conn.Conn` should be nil if closed
-	assert.True(t, conn.IsRunning)
+	assert.NotNil(t, conn.Connection()) // Assuming that the connection should be nil if closed
+	assert.True(t, conn.Running())
 
 	// Close the WebSocket helper
 	err = conn.Close()
 	assert.NoError(t, err)
 }
+
+func TestUpdateSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(echoHandler))
+	defer server.Close()
+	wsURL := "ws" + server.URL[len("http"):] + "/ws"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := map[string]any{"op": "subscribe", "args": "initial"}
+	conn, err := NewWebsocketHelper(ctx, WithEndpoint(wsURL), WithSubscriptions([]any{initial}))
+	assert.NoError(t, err)
+
+	received := make(chan map[string]any, 10)
+	go conn.Run(ctx, func(ctx context.Context, message map[string]any) error {
+		received <- message
+		return nil
+	})
+
+	waitForMessage := func() map[string]any {
+		select {
+		case message := <-received:
+			return message
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for message")
+			return nil
+		}
+	}
+
+	assert.Equal(t, "initial", waitForMessage()["args"])
+
+	added := map[string]any{"op": "subscribe", "args": "added"}
+	err = conn.UpdateSubscriptions(ctx, []any{initial, added}, []any{added})
+	assert.NoError(t, err)
+	assert.Equal(t, "added", waitForMessage()["args"])
+	assert.Len(t, conn.Subscriptions, 2)
+}
+
+// run with -race, updates keep coming in while the connection is closed and dialed again
+func TestUpdateSubscriptionsDuringReconnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(echoHandler))
+	defer server.Close()
+	wsURL := "ws" + server.URL[len("http"):] + "/ws"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := NewWebsocketHelper(ctx, WithEndpoint(wsURL), WithReconnectInterval(1500*time.Millisecond))
+	assert.NoError(t, err)
+
+	// messages are routed concurrently, the latest one is not necessarily routed last
+	var received sync.Map
+	go conn.Run(ctx, func(ctx context.Context, message map[string]any) error {
+		received.Store(message["args"], struct{}{})
+		return nil
+	})
+
+	deadline := time.After(5 * time.Second)
+	latest := map[string]any{}
+updates:
+	for i := 0; ; i++ {
+		select {
+		case <-deadline:
+			break updates
+		case <-time.After(10 * time.Millisecond):
+			latest = map[string]any{"op": "subscribe", "args": fmt.Sprintf("update-%d", i)}
+			// writes racing a closed connection fail, the update is applied on the next dial
+			_ = conn.UpdateSubscriptions(ctx, []any{latest}, []any{latest})
+		}
+	}
+
+	// either the last update went through or the next dial subscribes with the latest list
+	assert.Eventually(t, func() bool {
+		_, ok := received.Load(latest["args"])
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
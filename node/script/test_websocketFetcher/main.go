@@ -3,14 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 
 	"bisonai.com/miko/node/pkg/websocketfetcher/common"
 	"bisonai.com/miko/node/pkg/websocketfetcher/providers/bybit"
+	"bisonai.com/miko/node/pkg/wss"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
+	recordFile := flag.String("record", "", "append every inbound frame to this file")
+	replayFile := flag.String("replay", "", "replay a recorded session instead of connecting live")
+	speed := flag.Float64("speed", 1, "replay speed multiplier, 0 sends frames back to back")
+	flag.Parse()
 
 	ctx := context.Background()
 	feed := []common.Feed{
@@ -41,8 +47,21 @@ func main() {
 	}
 	feedMap := common.GetWssFeedMap(feed)
 
+	connectionOptions := []wss.ConnectionOption{}
+	if *recordFile != "" {
+		connectionOptions = append(connectionOptions, wss.WithRecordFile(*recordFile))
+	}
+	if *replayFile != "" {
+		replayServer, replayErr := wss.NewReplayServer(*replayFile, *speed)
+		if replayErr != nil {
+			log.Error().Err(replayErr).Msg("failed to load recorded session")
+			return
+		}
+		connectionOptions = append(connectionOptions, wss.WithCustomDialFunc(replayServer.DialFunc))
+	}
+
 	ch := make(chan *common.FeedData)
-	fetcher, err := bybit.New(ctx, common.WithFeedDataBuffer(ch), common.WithFeedMaps(feedMap["bybit"]))
+	fetcher, err := bybit.New(ctx, common.WithFeedDataBuffer(ch), common.WithFeedMaps(feedMap["bybit"]), common.WithConnectionOptions(connectionOptions...))
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create bybit fetcher")
 		return